@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -115,4 +117,11 @@ func main() {
 
 	// Attempt to shutdown the server, closing listeners and active connections properly.
 	_ = app.Shutdown()
+
+	// Let running background jobs finish (they need the DB, which is closed by the deferred CloseDB)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := worker.StopScheduler(ctx); err != nil {
+		log.Printf("Scheduler did not stop cleanly: %v", err)
+	}
 }
@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
 	"github.com/SysSyncer/placement-portal-kec/internal/repository"
 	"github.com/SysSyncer/placement-portal-kec/internal/utils"
 	"github.com/gofiber/fiber/v2"
@@ -142,7 +146,7 @@ func BulkDeleteStudents(c *fiber.Ctx) error {
 
 	// 1. Cleanup S3 Folders (Fetch students first to get Reg Nos)
 	// Passing limit: 10000 to fetch mostly all for cleanup (or we could fetch just RegNos via a specialized query, but this works given previous context)
-	students, _, err := repo.GetStudents(c.Context(), input.Department, input.BatchYear, "", 10000, 0)
+	students, _, err := repo.GetStudents(c.Context(), input.Department, input.BatchYear, "", models.StudentSectionFilter{}, 10000, 0)
 	if err == nil {
 		for _, s := range students {
 			if regNo, ok := s["register_number"].(string); ok && regNo != "" {
@@ -257,7 +261,7 @@ func ToggleBlockUser(c *fiber.Ctx) error {
 
 // UpdateApplicationStatus - PUT /api/v1/admin/applications/status
 // @Summary Update Application Status
-// @Description Update the status of a student application (shortlisted, placed, rejected)
+// @Description Move a student application to another status. Only moves in the transition graph are allowed (e.g. opted_in -> shortlisted -> placed); anything else returns 409 unless override is set with a remark. Every change is added to the application's status history.
 // @Tags Admin
 // @Accept json
 // @Produce json
@@ -265,30 +269,53 @@ func ToggleBlockUser(c *fiber.Ctx) error {
 // @Param input body map[string]interface{} true "Status Update Data"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/admin/applications/status [put]
 func UpdateApplicationStatus(c *fiber.Ctx) error {
-	// Admin sends: { "drive_id": 10, "student_id": 55, "status": "placed" }
+	// Admin sends: { "drive_id": 10, "student_id": 55, "status": "placed", "remark": "Cleared HR" }
 	var input struct {
-		DriveID   int64  `json:"drive_id"`
-		StudentID int64  `json:"student_id"`
-		Status    string `json:"status"` // 'shortlisted', 'placed', 'rejected'
+		DriveID   int64                    `json:"drive_id"`
+		StudentID int64                    `json:"student_id"`
+		Status    models.ApplicationStatus `json:"status"` // 'shortlisted', 'placed', 'rejected', ...
+		Remark    string                   `json:"remark"`
+		Override  bool                     `json:"override"` // Allow a move outside the transition graph
 	}
 	if err := c.BodyParser(&input); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
 	}
 
-	validStatuses := map[string]bool{"shortlisted": true, "placed": true, "rejected": true}
-	if !validStatuses[input.Status] {
-		return c.Status(400).JSON(fiber.Map{"error": "Invalid status value"})
+	if !input.Status.Valid() {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid status value. Must be one of: eligible, opted_in, opted_out, shortlisted, rejected, placed, removed"})
+	}
+	input.Remark = strings.TrimSpace(input.Remark)
+	if input.Override && input.Remark == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "A remark explaining the override is required"})
 	}
 
-	// Call Repository (Reusing DriveRepo or ApplicationRepo)
-	// Query: UPDATE drive_applications SET status = $1 WHERE drive_id = $2 AND student_id = $3
-	repo := repository.NewDriveRepository(database.DB)
-	if err := repo.UpdateApplicationStatus(c.Context(), input.DriveID, input.StudentID, input.Status); err != nil {
+	repo := repository.NewApplicationRepository(database.DB)
+	from, err := repo.TransitionStatus(c.Context(), input.DriveID, input.StudentID, input.Status, statusActor(c), input.Remark, input.Override)
+	if errors.Is(err, repository.ErrApplicationNotFound) {
+		return c.Status(404).JSON(fiber.Map{"error": "Application not found"})
+	}
+	if errors.Is(err, repository.ErrInvalidTransition) {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error(), "from": from, "to": input.Status, "hint": "Set override with a remark to force this change"})
+	}
+	if err != nil {
+		fmt.Printf("Error updating status for student %d drive %d: %v\n", input.StudentID, input.DriveID, err)
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to update status"})
 	}
+	if from == input.Status {
+		return c.JSON(fiber.Map{"message": "Student status unchanged"})
+	}
+
+	// Notify the student on WhatsApp for shortlist / offer (Async)
+	go notifyApplicationStatusWhatsApp(input.DriveID, input.StudentID, string(input.Status))
+	go notifyApplicationStatusEmail(input.DriveID, input.StudentID, string(input.Status))
+
+	// Outgoing webhooks (alumni tracker, department dashboard)
+	go emitApplicationStatusChanged(input.DriveID, input.StudentID, string(input.Status), "admin")
 
 	return c.JSON(fiber.Map{"message": "Student status updated"})
 }
@@ -302,6 +329,11 @@ func UpdateApplicationStatus(c *fiber.Ctx) error {
 // @Param dept query string false "Department Code"
 // @Param batch query int false "Batch Year"
 // @Param search query string false "Search Term (Name/RegNum)"
+// @Param skills query string false "Comma-separated skills the student must all have"
+// @Param proficiency query string false "Minimum skill proficiency" Enums(beginner, intermediate, advanced, expert)
+// @Param has_internship query bool false "Only students with an internship"
+// @Param internship_company query string false "Internship company name contains"
+// @Param certification query string false "Certification name or issuer contains"
 // @Success 200 {array} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/admin/students [get]
@@ -315,6 +347,21 @@ func ListStudents(c *fiber.Ctx) error {
 		batchYear, _ = strconv.Atoi(batchStr)
 	}
 
+	sections := models.StudentSectionFilter{
+		MinProficiency:    c.Query("proficiency"),
+		HasInternship:     c.QueryBool("has_internship"),
+		InternshipCompany: strings.TrimSpace(c.Query("internship_company")),
+		Certification:     strings.TrimSpace(c.Query("certification")),
+	}
+	for _, skill := range strings.Split(c.Query("skills"), ",") {
+		if skill = strings.TrimSpace(skill); skill != "" {
+			sections.Skills = append(sections.Skills, skill)
+		}
+	}
+	if sections.MinProficiency != "" && !slices.Contains(models.SkillProficiencies, sections.MinProficiency) {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid proficiency"})
+	}
+
 	// Pagination
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	if page < 1 {
@@ -327,7 +374,7 @@ func ListStudents(c *fiber.Ctx) error {
 	offset := (page - 1) * limit
 
 	repo := repository.NewUserRepository(database.DB)
-	students, total, err := repo.GetStudents(c.Context(), dept, batchYear, search, limit, offset)
+	students, total, err := repo.GetStudents(c.Context(), dept, batchYear, search, sections, limit, offset)
 
 	if err != nil {
 		fmt.Println("Error fetching students:", err)
@@ -1,14 +1,25 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
 	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/utils"
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 )
 
+// statusActor is the logged-in user, for the application status history
+func statusActor(c *fiber.Ctx) models.StatusActor {
+	role, _ := c.Locals("role").(string)
+	return models.StatusActor{UserID: int64(c.Locals("user_id").(float64)), Role: role}
+}
+
 // ApplyForDrive
 // @Summary Apply for a Placement Drive
 // @Description Allows a student to apply for a specific placement drive
@@ -35,7 +46,7 @@ func ApplyForDrive(c *fiber.Ctx) error {
 
 	// 3. Call Repo
 	repo := repository.NewApplicationRepository(database.DB)
-	success, message, err := repo.ApplyForDrive(c.Context(), studentID, driveID)
+	success, message, from, err := repo.ApplyForDrive(c.Context(), studentID, driveID)
 
 	fmt.Println(success, message)
 
@@ -48,19 +59,37 @@ func ApplyForDrive(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"success": false, "message": message})
 	}
 
+	if from != models.StatusOptedIn {
+		go emitApplicationStatusChanged(driveID, studentID, "opted_in", "student")
+	}
+
 	return c.JSON(fiber.Map{"success": true, "message": message})
 }
 
+// withdrawErrorMessage explains to a student why they can't withdraw
+func withdrawErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, repository.ErrApplicationNotFound):
+		return "You haven't applied to this drive, so there is nothing to withdraw"
+	case errors.Is(err, repository.ErrWithdrawalClosed):
+		return "This drive is closed or its deadline has passed, so you can no longer withdraw"
+	}
+	return "You can't withdraw once you have been shortlisted or the drive has concluded for you. Please contact the placement office"
+}
+
 // WithdrawFromDrive
 // @Summary Withdraw application for a Placement Drive
-// @Description Allows a student to withdraw (opt-out) from a drive
+// @Description Allows a student to withdraw (opt-out) from a drive they applied to, while the drive is open and before its deadline. A reason is required and recorded in the status history. Shortlisted, rejected and placed students can't withdraw; a shortlisted student's attempt is reported to the admins.
 // @Tags Application
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Drive ID"
+// @Param input body models.WithdrawInput true "Reason for withdrawing"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/drives/{id}/withdraw [post]
 func WithdrawFromDrive(c *fiber.Ctx) error {
@@ -70,10 +99,108 @@ func WithdrawFromDrive(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid Drive ID"})
 	}
 
+	var input models.WithdrawInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+	input.Reason = strings.TrimSpace(input.Reason)
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": "A reason of 5 to 500 characters is required"})
+	}
+
 	repo := repository.NewApplicationRepository(database.DB)
-	if err := repo.WithdrawApplication(c.Context(), studentID, driveID); err != nil {
+	from, err := repo.WithdrawApplication(c.Context(), studentID, driveID, input.Reason)
+	if errors.Is(err, repository.ErrDriveNotFound) {
+		return c.Status(404).JSON(fiber.Map{"error": "Drive not found"})
+	}
+	if errors.Is(err, repository.ErrApplicationNotFound) {
+		return c.Status(404).JSON(fiber.Map{"error": withdrawErrorMessage(err)})
+	}
+	if errors.Is(err, repository.ErrWithdrawalClosed) || errors.Is(err, repository.ErrInvalidTransition) {
+		if from == models.StatusShortlisted {
+			recordAudit(c, models.AuditLog{
+				Action:    models.AuditWithdrawBlocked,
+				DriveID:   &driveID,
+				StudentID: &studentID,
+				Details:   map[string]interface{}{"status": from, "reason": input.Reason},
+			})
+			go notifyAdminsWithdrawalAttempt(driveID, studentID, input.Reason)
+		}
+		return c.Status(409).JSON(fiber.Map{"error": withdrawErrorMessage(err), "status": from})
+	}
+	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to withdraw: " + err.Error()})
 	}
 
+	if from != models.StatusOptedOut {
+		go emitApplicationStatusChanged(driveID, studentID, "opted_out", "student")
+	}
+
 	return c.JSON(fiber.Map{"success": true, "message": "Successfully withdrawn from drive"})
 }
+
+// GetMyApplications
+// @Summary My Applications Dashboard
+// @Description The student's placement journey: every application with drive details, current status, round progress, offer details and a timeline, plus counts (applied, shortlisted, placed, rejected, withdrawn)
+// @Tags Student
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.StudentApplicationDashboard
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/applications [get]
+func GetMyApplications(c *fiber.Ctx) error {
+	studentID := int64(c.Locals("user_id").(float64))
+
+	dash, err := repository.NewApplicationRepository(database.DB).GetStudentDashboard(c.Context(), studentID)
+	if err != nil {
+		fmt.Printf("Error fetching applications for student %d: %v\n", studentID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch applications"})
+	}
+
+	// Offer letters live in a private bucket
+	for _, a := range dash.Applications {
+		if a.Offer == nil || a.Offer.OfferLetterURL == "" {
+			continue
+		}
+		url := ""
+		if key := utils.ExtractPathFromURL(a.Offer.OfferLetterURL); key != "" {
+			if url, err = utils.GetPresignedURL(key, 60); err != nil {
+				fmt.Printf("Error presigning offer letter for student %d drive %d: %v\n", studentID, a.DriveID, err)
+			}
+		}
+		a.Offer.OfferLetterURL = url
+	}
+
+	return c.JSON(dash)
+}
+
+// GetApplicationHistory
+// @Summary Application Status History
+// @Description Every status change of a student's application to a drive, oldest first, with who made it and their remark
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Param student_id path int true "Student ID"
+// @Success 200 {array} models.ApplicationStatusChange
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/drives/{id}/applicants/{student_id}/history [get]
+func GetApplicationHistory(c *fiber.Ctx) error {
+	driveID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Drive ID"})
+	}
+	studentID, err := strconv.ParseInt(c.Params("student_id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Student ID"})
+	}
+
+	history, err := repository.NewApplicationRepository(database.DB).GetStatusHistory(c.Context(), driveID, studentID)
+	if err != nil {
+		fmt.Printf("Error fetching status history for student %d drive %d: %v\n", studentID, driveID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch status history"})
+	}
+	return c.JSON(history)
+}
@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/services"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetCheckInToken returns the student's signed QR payload for a drive
+// @Summary Get Drive Check-in QR
+// @Description Get the signed token to render as a QR code and show to coordinators on drive day. Only for students who applied. The token is for one round and expires after CHECKIN_QR_TTL_SECONDS (default 5 minutes); refresh it before expires_at.
+// @Tags Student
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Param round_index query int false "Round (position in drive rounds, default 0)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /v1/drives/{id}/checkin-token [get]
+func GetCheckInToken(c *fiber.Ctx) error {
+	studentID := int64(c.Locals("user_id").(float64))
+	driveID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Drive ID"})
+	}
+	round, err := strconv.Atoi(c.Query("round_index", "0"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid round_index"})
+	}
+	drive, err := repository.NewDriveRepository(database.DB).GetDriveByID(c.Context(), driveID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Drive not found"})
+	}
+	if _, ok := roundName(drive, round); !ok {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid round_index"})
+	}
+
+	repo := repository.NewAttendanceRepository(database.DB)
+	app, err := repo.GetCheckInCandidate(c.Context(), driveID, studentID)
+	if err != nil {
+		fmt.Printf("Error fetching application for student %d drive %d: %v\n", studentID, driveID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch check-in token"})
+	}
+	if app == nil || (app.Status != "opted_in" && app.Status != "shortlisted" && app.Status != "placed") {
+		return c.Status(403).JSON(fiber.Map{"error": "Check-in is only available for drives you applied to"})
+	}
+
+	token, expiresAt, err := services.SignCheckInToken(driveID, studentID, round)
+	if err != nil {
+		fmt.Printf("Check-in Error: %v\n", err)
+		return c.Status(503).JSON(fiber.Map{"error": "Check-in is not configured"})
+	}
+	return c.JSON(fiber.Map{
+		"drive_id":    driveID,
+		"student_id":  studentID,
+		"round_index": round,
+		"token":       token,
+		"expires_at":  expiresAt,
+	})
+}
+
+// CheckInStudent records a scanned QR as attendance for a round
+// @Summary Check In Student (QR Scan)
+// @Description Coordinators scan a student's QR on drive day. The QR must be for this drive and round and not expired. Scanning twice is harmless and reports the first check-in time.
+// @Tags Coordinator
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param checkin body models.CheckInInput true "Scanned token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/coordinator/checkin [post]
+func CheckInStudent(c *fiber.Ctx) error {
+	coordinatorID := int64(c.Locals("user_id").(float64))
+
+	var input models.CheckInInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	// 1. Verify the QR
+	driveID, studentID, round, err := services.ParseCheckInToken(input.Token)
+	switch {
+	case errors.Is(err, services.ErrCheckInNotConfigured):
+		fmt.Printf("Check-in Error: %v\n", err)
+		return c.Status(503).JSON(fiber.Map{"error": "Check-in is not configured"})
+	case errors.Is(err, services.ErrCheckInTokenExpired):
+		return c.Status(400).JSON(fiber.Map{"error": "This QR code has expired. Ask the student to refresh it"})
+	case err != nil:
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid QR code"})
+	}
+	if driveID != input.DriveID {
+		return c.Status(400).JSON(fiber.Map{"error": "This QR code is for a different drive"})
+	}
+	if round != input.RoundIndex {
+		return c.Status(400).JSON(fiber.Map{"error": "This QR code is for a different round"})
+	}
+	drive, err := repository.NewDriveRepository(database.DB).GetDriveByID(c.Context(), driveID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Drive not found"})
+	}
+	if _, ok := roundName(drive, input.RoundIndex); !ok {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid round_index"})
+	}
+
+	// 2. The student must still be in the running
+	repo := repository.NewAttendanceRepository(database.DB)
+	student, err := repo.GetCheckInCandidate(c.Context(), driveID, studentID)
+	if err != nil {
+		fmt.Printf("Error fetching application for student %d drive %d: %v\n", studentID, driveID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to check in"})
+	}
+	if student == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Student has not applied to this drive"})
+	}
+	allowed := student.Status == "shortlisted" || student.Status == "placed" ||
+		(input.RoundIndex == 0 && student.Status == "opted_in")
+	if !allowed {
+		return c.Status(403).JSON(fiber.Map{
+			"error":   fmt.Sprintf("Student is not allowed in this round (status: %s)", botStatusLabel(student.Status)),
+			"student": student,
+		})
+	}
+
+	// 3. Record
+	at, already, err := repo.RecordCheckIn(c.Context(), driveID, studentID, input.RoundIndex, coordinatorID)
+	if err != nil {
+		fmt.Printf("Error checking in student %d drive %d: %v\n", studentID, driveID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to check in"})
+	}
+	student.Present = true
+	student.CheckedInAt = &at
+
+	message := "Checked in"
+	if already {
+		message = "Already checked in at " + at.Local().Format("03:04 PM")
+	}
+	return c.JSON(fiber.Map{
+		"message":            message,
+		"already_checked_in": already,
+		"student":            student,
+	})
+}
+
+// GetDriveAttendance lists who is present and absent for a round
+// @Summary Get Drive Attendance
+// @Description List every student expected at a round with their check-in status
+// @Tags Coordinator
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Param round_index query int false "Round (position in drive rounds, default 0)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/coordinator/drives/{id}/attendance [get]
+func GetDriveAttendance(c *fiber.Ctx) error {
+	driveID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Drive ID"})
+	}
+	round, err := strconv.Atoi(c.Query("round_index", "0"))
+	if err != nil || round < 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid round_index"})
+	}
+
+	records, err := repository.NewAttendanceRepository(database.DB).GetAttendance(c.Context(), driveID, round)
+	if err != nil {
+		fmt.Printf("Error fetching attendance for drive %d: %v\n", driveID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch attendance"})
+	}
+
+	present := 0
+	for _, r := range records {
+		if r.Present {
+			present++
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"data": records,
+		"meta": fiber.Map{
+			"expected": len(records),
+			"present":  present,
+			"absent":   len(records) - present,
+		},
+	})
+}
+
+// FinalizeDriveAttendance records absent students as no-shows once a round is over
+// @Summary Finalize Drive Attendance
+// @Description Record every expected student who didn't check in as a no-show. With NO_SHOW_PENALTY_DRIVES set, they are blocked from that many subsequently posted drives.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Param round body models.RoundInput true "Round"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/drives/{id}/attendance/finalize [post]
+func FinalizeDriveAttendance(c *fiber.Ctx) error {
+	driveID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Drive ID"})
+	}
+
+	var input models.RoundInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+	if input.RoundIndex < 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid round_index"})
+	}
+
+	penalty := services.NoShowPenaltyDrives()
+	count, err := repository.NewAttendanceRepository(database.DB).FinalizeNoShows(c.Context(), driveID, input.RoundIndex, penalty)
+	if err != nil {
+		fmt.Printf("Error finalizing attendance for drive %d: %v\n", driveID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to finalize attendance"})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":        fmt.Sprintf("Recorded %d no-shows", count),
+		"no_shows":       count,
+		"penalty_drives": penalty,
+	})
+}
+
+// ListNoShows returns recorded no-shows
+// @Summary List No-Shows
+// @Description List recorded no-shows with their penalty, optionally filtered by drive or student
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param drive_id query int false "Drive ID"
+// @Param student_id query int false "Student ID"
+// @Param page query int false "Page Number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/no-shows [get]
+func ListNoShows(c *fiber.Ctx) error {
+	driveID, _ := strconv.ParseInt(c.Query("drive_id"), 10, 64)
+	studentID, _ := strconv.ParseInt(c.Query("student_id"), 10, 64)
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	noShows, total, err := repository.NewAttendanceRepository(database.DB).ListNoShows(c.Context(), driveID, studentID, limit, offset)
+	if err != nil {
+		fmt.Printf("Error fetching no-shows: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch no-shows"})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": noShows,
+		"meta": fiber.Map{
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// WaiveNoShow lifts a no-show penalty
+// @Summary Waive No-Show
+// @Description Waive a recorded no-show (e.g. approved leave) so it no longer blocks the student
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param noshow body object true "{drive_id, student_id, round_index}"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/no-shows/waive [post]
+func WaiveNoShow(c *fiber.Ctx) error {
+	var input struct {
+		DriveID    int64 `json:"drive_id"`
+		StudentID  int64 `json:"student_id"`
+		RoundIndex int   `json:"round_index"`
+	}
+	if err := c.BodyParser(&input); err != nil || input.DriveID == 0 || input.StudentID == 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "drive_id and student_id are required"})
+	}
+
+	ok, err := repository.NewAttendanceRepository(database.DB).WaiveNoShow(c.Context(), input.DriveID, input.StudentID, input.RoundIndex)
+	if err != nil {
+		fmt.Printf("Error waiving no-show: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to waive no-show"})
+	}
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{"error": "No-show not found"})
+	}
+
+	return c.JSON(fiber.Map{"message": "No-show waived"})
+}
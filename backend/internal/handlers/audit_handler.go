@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// recordAudit stores an audit entry for the current request. Actor, company and IP default to the
+// logged-in user. Failures are logged, not returned: the action itself has already happened.
+func recordAudit(c *fiber.Ctx, entry models.AuditLog) {
+	if entry.ActorID == 0 {
+		if id, ok := c.Locals("user_id").(float64); ok {
+			entry.ActorID = int64(id)
+		}
+	}
+	if entry.ActorRole == "" {
+		entry.ActorRole, _ = c.Locals("role").(string)
+	}
+	if entry.CompanyID == nil {
+		if id, ok := c.Locals("company_id").(int64); ok {
+			entry.CompanyID = &id
+		}
+	}
+	entry.IPAddress = c.IP()
+
+	if err := repository.NewAuditRepository(database.DB).Log(c.Context(), entry); err != nil {
+		fmt.Printf("Audit Error: Failed to record %s by %d: %v\n", entry.Action, entry.ActorID, err)
+	}
+}
+
+// ListAuditLogs returns the audit trail
+// @Summary List Audit Logs
+// @Description Audit trail of recruiter (and recruiter-account) actions and blocked student withdrawals, newest first
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param company_id query int false "Company ID"
+// @Param actor_id query int false "User ID of the actor"
+// @Param drive_id query int false "Drive ID"
+// @Param action query string false "Action, e.g. recruiter.results_upload"
+// @Param page query int false "Page Number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/audit-logs [get]
+func ListAuditLogs(c *fiber.Ctx) error {
+	companyID, _ := strconv.ParseInt(c.Query("company_id"), 10, 64)
+	actorID, _ := strconv.ParseInt(c.Query("actor_id"), 10, 64)
+	driveID, _ := strconv.ParseInt(c.Query("drive_id"), 10, 64)
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	logs, total, err := repository.NewAuditRepository(database.DB).ListAuditLogs(c.Context(), companyID, actorID, driveID, c.Query("action"), limit, offset)
+	if err != nil {
+		fmt.Printf("Error fetching audit logs: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch audit logs"})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": logs,
+		"meta": fiber.Map{
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/SysSyncer/placement-portal-kec/internal/database"
 	"github.com/SysSyncer/placement-portal-kec/internal/models"
 	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/services"
 	"github.com/SysSyncer/placement-portal-kec/internal/utils"
 	"github.com/gofiber/fiber/v2"
 	"golang.org/x/crypto/bcrypt"
@@ -107,7 +108,8 @@ func AdminLogin(c *fiber.Ctx) error {
 		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	if user.Role != "admin" {
+	// Coordinators share the staff login; AdminOnly still keeps them out of admin routes
+	if user.Role != "admin" && user.Role != "coordinator" {
 		return c.Status(403).JSON(fiber.Map{"error": "Unauthorized: Access restricted to administrators"})
 	}
 
@@ -198,7 +200,8 @@ func AdminForgotPassword(c *fiber.Ctx) error {
 	}
 
 	go func() {
-		if err := utils.SendOTPEmail(input.Email, otp); err != nil {
+		data := map[string]interface{}{"OTP": otp}
+		if err := services.NewEmailService().SendTemplate([]string{input.Email}, services.EmailTemplateOTP, data, nil); err != nil {
 			fmt.Printf("Failed to send email to %s: %v\n", input.Email, err)
 		}
 	}()
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxBulkStatusRows = 5000
+
+// bulkStatusRow is one student of a bulk update; Row is 1-based in the request or CSV
+type bulkStatusRow struct {
+	Row            int
+	StudentID      int64
+	RegisterNumber string
+}
+
+// parseBulkStatusCSV reads student IDs / register numbers from an uploaded CSV.
+// A header with student_id and/or register_number columns picks the columns;
+// without one every row's first column is a register number.
+func parseBulkStatusCSV(r io.Reader) ([]bulkStatusRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Spreadsheet exports often have ragged rows
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	idCol, regCol, start := -1, -1, 0
+	for i, h := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))) {
+		case "student_id", "id":
+			idCol = i
+		case "register_number", "register_no", "reg_no", "regno":
+			regCol = i
+		}
+	}
+	if idCol >= 0 || regCol >= 0 {
+		start = 1 // Skip the header
+	} else {
+		regCol = 0
+	}
+
+	var rows []bulkStatusRow
+	for i, rec := range records[start:] {
+		row := bulkStatusRow{Row: i + start + 1}
+		if idCol >= 0 && idCol < len(rec) && strings.TrimSpace(rec[idCol]) != "" {
+			id, err := strconv.ParseInt(strings.TrimSpace(rec[idCol]), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid student_id %q", row.Row, rec[idCol])
+			}
+			row.StudentID = id
+		}
+		if regCol >= 0 && regCol < len(rec) {
+			row.RegisterNumber = strings.TrimSpace(rec[regCol])
+		}
+		if row.StudentID == 0 && row.RegisterNumber == "" {
+			continue // Blank line
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseBulkStatusInput reads a JSON body or a multipart form with a CSV "file"
+func parseBulkStatusInput(c *fiber.Ctx) (models.BulkStatusInput, []bulkStatusRow, error) {
+	var input models.BulkStatusInput
+	var rows []bulkStatusRow
+
+	if strings.HasPrefix(c.Get("Content-Type"), "multipart/form-data") {
+		input.Status = c.FormValue("status")
+		input.Remark = c.FormValue("remark")
+		input.Notify, _ = strconv.ParseBool(c.FormValue("notify"))
+		input.AllOrNothing, _ = strconv.ParseBool(c.FormValue("all_or_nothing"))
+		input.Override, _ = strconv.ParseBool(c.FormValue("override"))
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return input, nil, errors.New("CSV file is required")
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return input, nil, errors.New("Could not open file")
+		}
+		defer file.Close()
+		if rows, err = parseBulkStatusCSV(file); err != nil {
+			return input, nil, fmt.Errorf("Invalid CSV: %v", err)
+		}
+	} else {
+		if err := c.BodyParser(&input); err != nil {
+			return input, nil, errors.New("Invalid input")
+		}
+		for _, id := range input.StudentIDs {
+			rows = append(rows, bulkStatusRow{Row: len(rows) + 1, StudentID: id})
+		}
+		for _, regNo := range input.RegisterNumbers {
+			rows = append(rows, bulkStatusRow{Row: len(rows) + 1, RegisterNumber: strings.TrimSpace(regNo)})
+		}
+	}
+
+	input.Remark = strings.TrimSpace(input.Remark)
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return input, nil, fmt.Errorf("Validation failed: %v", err)
+	}
+	if input.Override && input.Remark == "" {
+		return input, nil, errors.New("A remark explaining the override is required")
+	}
+	if len(rows) == 0 {
+		return input, nil, errors.New("No students given")
+	}
+	if len(rows) > maxBulkStatusRows {
+		return input, nil, fmt.Errorf("At most %d students per request", maxBulkStatusRows)
+	}
+	return input, rows, nil
+}
+
+// BulkUpdateApplicationStatus moves many applicants of a drive to one status
+// @Summary Bulk Update Application Status
+// @Description Shortlist, reject or place many applicants of a drive at once, by student_ids / register_numbers (JSON) or a CSV upload (multipart "file" with a register_number or student_id column, plus status, remark, notify, all_or_nothing and override fields). Rows that aren't applicants, or whose current status can't move to the target (e.g. withdrawn), are reported and skipped unless override is set with a remark; the rest change in one transaction. With all_or_nothing, nothing changes if any row is rejected.
+// @Tags Admin
+// @Accept json,mpfd
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Param input body models.BulkStatusInput false "Students and target status (JSON)"
+// @Param file formData file false "CSV of register numbers / student IDs (multipart)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/drives/{id}/applications/status [post]
+func BulkUpdateApplicationStatus(c *fiber.Ctx) error {
+	driveID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Drive ID"})
+	}
+	input, rows, err := parseBulkStatusInput(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	driveRepo := repository.NewDriveRepository(database.DB)
+	if _, err := driveRepo.GetDriveByID(c.Context(), driveID); err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Drive not found"})
+	}
+	applicants, err := driveRepo.GetDriveApplicants(c.Context(), driveID)
+	if err != nil {
+		fmt.Printf("Error fetching applicants for drive %d: %v\n", driveID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch applicants"})
+	}
+	byID := make(map[int64]*models.DriveApplicant, len(applicants))
+	byRegNo := make(map[string]*models.DriveApplicant, len(applicants))
+	for i := range applicants {
+		byID[applicants[i].StudentID] = &applicants[i]
+		byRegNo[strings.ToUpper(applicants[i].RegisterNumber)] = &applicants[i]
+	}
+
+	// 1. Validate every row before changing anything
+	to := models.ApplicationStatus(input.Status)
+	rowErrors := []fiber.Map{}
+	seen := map[int64]bool{}
+	var studentIDs []int64
+	unchanged := 0
+	for _, r := range rows {
+		a := byID[r.StudentID]
+		if a == nil && r.RegisterNumber != "" {
+			a = byRegNo[strings.ToUpper(r.RegisterNumber)]
+		}
+		rowErr := ""
+		switch {
+		case a == nil:
+			rowErr = "Not an applicant of this drive"
+		case seen[a.StudentID]:
+			rowErr = "Duplicate student"
+		case !input.Override && !models.ApplicationStatus(a.Status).CanMoveTo(to):
+			rowErr = fmt.Sprintf("Can't move from %s to %s", a.Status, to)
+		}
+		if rowErr != "" {
+			rowErrors = append(rowErrors, fiber.Map{"row": r.Row, "student_id": r.StudentID, "register_number": r.RegisterNumber, "error": rowErr})
+			continue
+		}
+		seen[a.StudentID] = true
+		if a.Status == string(to) {
+			unchanged++
+			continue
+		}
+		studentIDs = append(studentIDs, a.StudentID)
+	}
+
+	if input.AllOrNothing && len(rowErrors) > 0 {
+		return c.Status(422).JSON(fiber.Map{
+			"error":  fmt.Sprintf("%d of %d rows were rejected; nothing was changed", len(rowErrors), len(rows)),
+			"errors": rowErrors,
+		})
+	}
+
+	// 2. Apply in one transaction
+	changes := []models.ApplicationStatusChange{}
+	if len(studentIDs) > 0 {
+		changes, err = repository.NewApplicationRepository(database.DB).BulkTransitionStatus(c.Context(), driveID, studentIDs, to, statusActor(c), input.Remark, input.Override)
+		if err != nil {
+			fmt.Printf("Error bulk updating status for drive %d: %v\n", driveID, err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to update status"})
+		}
+	}
+
+	// 3. Notify (Async). One goroutine sends them in turn rather than hundreds at once.
+	updated := make([]fiber.Map, 0, len(changes))
+	for _, ch := range changes {
+		regNo := ""
+		if a := byID[ch.StudentID]; a != nil {
+			regNo = a.RegisterNumber
+		}
+		updated = append(updated, fiber.Map{"student_id": ch.StudentID, "register_number": regNo, "from": ch.FromStatus, "to": ch.ToStatus})
+	}
+	go func(changes []models.ApplicationStatusChange, notify bool) {
+		for _, ch := range changes {
+			emitApplicationStatusChanged(driveID, ch.StudentID, ch.ToStatus, "admin")
+			if notify {
+				notifyApplicationStatusWhatsApp(driveID, ch.StudentID, ch.ToStatus)
+				notifyApplicationStatusEmail(driveID, ch.StudentID, ch.ToStatus)
+			}
+		}
+	}(changes, input.Notify)
+
+	return c.JSON(fiber.Map{
+		"message":   fmt.Sprintf("Updated %d of %d students", len(changes), len(rows)),
+		"updated":   updated,
+		"unchanged": unchanged,
+		"errors":    rowErrors,
+	})
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// newCalendarToken returns 32 random bytes as hex; the token is the only credential for the feed
+func newCalendarToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// calendarFeedResponse returns the subscription URLs for a token
+func calendarFeedResponse(c *fiber.Ctx, token string) fiber.Map {
+	feedURL := fmt.Sprintf("%s/api/v1/calendar/%s.ics", c.BaseURL(), token)
+	return fiber.Map{
+		"token":      token,
+		"feed_url":   feedURL,
+		"webcal_url": "webcal://" + strings.TrimPrefix(strings.TrimPrefix(feedURL, "https://"), "http://"),
+	}
+}
+
+// GetCalendarFeed returns the student's personal calendar feed URL, creating it on first use
+// @Summary Get Calendar Feed
+// @Description Get the logged-in student's iCalendar feed URL (drive dates, deadlines, rounds and interviews) for Google Calendar / Outlook subscriptions
+// @Tags Student
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/calendar [get]
+func GetCalendarFeed(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+
+	repo := repository.NewCalendarRepository(database.DB)
+	token, err := repo.GetToken(c.Context(), userID)
+	if err != nil {
+		fmt.Printf("Error fetching calendar token for %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch calendar feed"})
+	}
+
+	if token == "" {
+		if token, err = newCalendarToken(); err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to create calendar feed"})
+		}
+		if err := repo.SetToken(c.Context(), userID, token); err != nil {
+			fmt.Printf("Error saving calendar token for %d: %v\n", userID, err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to create calendar feed"})
+		}
+	}
+
+	return c.JSON(calendarFeedResponse(c, token))
+}
+
+// RotateCalendarFeed replaces the student's feed token, e.g. after the URL was shared by mistake
+// @Summary Rotate Calendar Feed
+// @Description Issue a new calendar feed URL. The old URL stops working, so existing subscriptions must be re-added.
+// @Tags Student
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/calendar/rotate [post]
+func RotateCalendarFeed(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+
+	token, err := newCalendarToken()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to rotate calendar feed"})
+	}
+	if err := repository.NewCalendarRepository(database.DB).SetToken(c.Context(), userID, token); err != nil {
+		fmt.Printf("Error rotating calendar token for %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to rotate calendar feed"})
+	}
+
+	return c.JSON(calendarFeedResponse(c, token))
+}
+
+// ServeCalendarFeed renders a student's calendar. Public: the token in the URL is the credential,
+// since calendar apps can't send an Authorization header.
+// @Summary Student Calendar Feed (iCal)
+// @Description iCalendar feed of every eligible or applied drive's date, deadline and rounds, plus booked interviews
+// @Tags Student
+// @Produce text/calendar
+// @Param token path string true "Feed token"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/calendar/{token}.ics [get]
+func ServeCalendarFeed(c *fiber.Ctx) error {
+	token := c.Params("token")
+
+	repo := repository.NewCalendarRepository(database.DB)
+	studentID, err := repo.GetStudentIDByToken(c.Context(), token)
+	if err != nil {
+		fmt.Printf("Error resolving calendar token: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load calendar"})
+	}
+	if studentID == 0 {
+		return c.Status(404).JSON(fiber.Map{"error": "Calendar not found"})
+	}
+
+	// 1. Eligible open drives + drives the student applied to (which may since have closed).
+	// Any failure is a 500: a partial feed would make calendar apps delete the missing events.
+	eligible, err := repository.NewDriveRepository(database.DB).GetEligibleDrives(c.Context(), studentID)
+	if err != nil {
+		fmt.Printf("Error fetching eligible drives for %d: %v\n", studentID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load calendar"})
+	}
+	applied, err := repo.GetAppliedDrives(c.Context(), studentID)
+	if err != nil {
+		fmt.Printf("Error fetching applied drives for %d: %v\n", studentID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load calendar"})
+	}
+
+	seen := make(map[int64]bool)
+	var events []utils.ICalEvent
+	for _, d := range append(applied, eligible...) {
+		if seen[d.ID] {
+			continue
+		}
+		seen[d.ID] = true
+		events = append(events, driveICalEvents(d)...)
+	}
+
+	// 2. Booked interview slots
+	interviews, err := repository.NewInterviewRepository(database.DB).GetStudentInterviews(c.Context(), studentID)
+	if err != nil {
+		fmt.Printf("Error fetching interviews for %d: %v\n", studentID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load calendar"})
+	}
+	for _, i := range interviews {
+		events = append(events, studentInterviewICalEvent(studentID, i))
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, max-age=900")
+	return sendICal(c, "placements.ics", "KEC Placements", events)
+}
+
+// driveICalEvents turns a drive into its deadline, drive day and round events.
+// UIDs are stable per drive so subscribed calendars update events when a drive changes.
+func driveICalEvents(d models.PlacementDrive) []utils.ICalEvent {
+	title := fmt.Sprintf("%s – %s", d.CompanyName, d.JobRole)
+	status := botStatusLabel(d.UserStatus)
+
+	events := []utils.ICalEvent{{
+		UID:         fmt.Sprintf("drive-%d-deadline@kec-placement-portal", d.ID),
+		Summary:     "Deadline: " + title,
+		Description: fmt.Sprintf("Registration for %s closes.\nYour status: %s", title, status),
+		Start:       d.DeadlineDate.Add(-30 * time.Minute),
+		End:         d.DeadlineDate,
+	}}
+
+	if d.DriveDate.Valid {
+		events = append(events, utils.ICalEvent{
+			UID:         fmt.Sprintf("drive-%d-date@kec-placement-portal", d.ID),
+			Summary:     "Drive: " + title,
+			Description: fmt.Sprintf("Placement drive for %s.\nYour status: %s", title, status),
+			Location:    d.Location,
+			Start:       d.DriveDate.Time,
+			AllDay:      true,
+		})
+	}
+
+	for i, r := range d.Rounds {
+		date, err := time.Parse("2006-01-02", r.Date)
+		if err != nil {
+			continue // Rounds without a (valid) date are not on the calendar
+		}
+		events = append(events, utils.ICalEvent{
+			UID:         fmt.Sprintf("drive-%d-round-%d@kec-placement-portal", d.ID, i),
+			Summary:     fmt.Sprintf("%s: %s", r.Name, title),
+			Description: r.Description,
+			Location:    d.Location,
+			Start:       date,
+			AllDay:      true,
+		})
+	}
+	return events
+}
@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// companyError maps repository conflicts to 4xx responses
+func companyError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, repository.ErrCompanyNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Company not found"})
+	case errors.Is(err, repository.ErrCompanyExists), errors.Is(err, repository.ErrAliasTaken):
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, repository.ErrCompanyMergeToSelf), errors.Is(err, repository.ErrCompanySpocNotFound):
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	fmt.Printf("Company Error: %s: %v\n", fallback, err)
+	return c.Status(500).JSON(fiber.Map{"error": fallback})
+}
+
+// ListCompanies returns the companies master
+// @Summary List Companies
+// @Description List companies with drive counts, searchable by name, domain or any alias
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "Name, alias or domain"
+// @Param page query int false "Page Number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/companies [get]
+func ListCompanies(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	companies, total, err := repository.NewCompanyRepository(database.DB).ListCompanies(c.Context(), c.Query("search"), limit, offset)
+	if err != nil {
+		return companyError(c, err, "Failed to fetch companies")
+	}
+
+	return c.JSON(fiber.Map{
+		"data": companies,
+		"meta": fiber.Map{
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetCompany returns a company with its aliases and SPOCs
+// @Summary Get Company
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Company ID"
+// @Success 200 {object} models.Company
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/companies/{id} [get]
+func GetCompany(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Company ID"})
+	}
+
+	company, err := repository.NewCompanyRepository(database.DB).GetCompany(c.Context(), id)
+	if err != nil {
+		return companyError(c, err, "Failed to fetch company")
+	}
+	if company == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Company not found"})
+	}
+	return c.JSON(company)
+}
+
+// CreateCompany adds a company to the master. Without a logo, one is looked up from the domain.
+// @Summary Create Company
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param company body models.CompanyInput true "Company"
+// @Success 201 {object} models.Company
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /v1/admin/companies [post]
+func CreateCompany(c *fiber.Ctx) error {
+	var input models.CompanyInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	if input.LogoURL == "" && input.Domain != "" {
+		if brand, err := utils.FetchCompanyBrand(input.Domain); err != nil {
+			fmt.Printf("Brand: Logo lookup for %s failed: %v\n", input.Domain, err)
+		} else {
+			input.LogoURL = brand.LogoURL
+		}
+	}
+
+	repo := repository.NewCompanyRepository(database.DB)
+	id, err := repo.CreateCompany(c.Context(), input)
+	if err != nil {
+		return companyError(c, err, "Failed to create company")
+	}
+
+	company, err := repo.GetCompany(c.Context(), id)
+	if err != nil {
+		return companyError(c, err, "Failed to fetch company")
+	}
+	return c.Status(201).JSON(company)
+}
+
+// UpdateCompany edits a company
+// @Summary Update Company
+// @Description Update display fields. aliases and spoc_ids, when sent, replace the current lists.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Company ID"
+// @Param company body models.CompanyInput true "Company"
+// @Success 200 {object} models.Company
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /v1/admin/companies/{id} [put]
+func UpdateCompany(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Company ID"})
+	}
+
+	var input models.CompanyInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	repo := repository.NewCompanyRepository(database.DB)
+	ok, err := repo.UpdateCompany(c.Context(), id, input)
+	if err != nil {
+		return companyError(c, err, "Failed to update company")
+	}
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{"error": "Company not found"})
+	}
+
+	company, err := repo.GetCompany(c.Context(), id)
+	if err != nil {
+		return companyError(c, err, "Failed to fetch company")
+	}
+	return c.JSON(company)
+}
+
+// MergeCompanies folds duplicates into one company
+// @Summary Merge Companies
+// @Description Move the drives, aliases and SPOCs of source_ids onto this company and delete the sources. Drives keep their typed company_name.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Company ID to keep"
+// @Param merge body models.MergeCompaniesInput true "Companies to merge in"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/companies/{id}/merge [post]
+func MergeCompanies(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Company ID"})
+	}
+
+	var input models.MergeCompaniesInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	repo := repository.NewCompanyRepository(database.DB)
+	merged, err := repo.MergeCompanies(c.Context(), id, input.SourceIDs)
+	if err != nil {
+		return companyError(c, err, "Failed to merge companies")
+	}
+
+	company, err := repo.GetCompany(c.Context(), id)
+	if err != nil {
+		return companyError(c, err, "Failed to fetch company")
+	}
+	return c.JSON(fiber.Map{
+		"message": fmt.Sprintf("Merged %d companies", merged),
+		"merged":  merged,
+		"company": company,
+	})
+}
+
+// RefreshCompanyBrand re-fetches the company's logo from its domain
+// @Summary Refresh Company Logo
+// @Description Look up the logo for the company's domain (or the given domain, which is saved if the company has none)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Company ID"
+// @Param domain query string false "Domain to use, e.g. tcs.com"
+// @Success 200 {object} models.Company
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /v1/admin/companies/{id}/refresh-brand [post]
+func RefreshCompanyBrand(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Company ID"})
+	}
+
+	repo := repository.NewCompanyRepository(database.DB)
+	company, err := repo.GetCompany(c.Context(), id)
+	if err != nil {
+		return companyError(c, err, "Failed to fetch company")
+	}
+	if company == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Company not found"})
+	}
+
+	domain := c.Query("domain", company.Domain)
+	if domain == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Company has no domain; pass ?domain="})
+	}
+
+	brand, err := utils.FetchCompanyBrand(domain)
+	if err != nil {
+		return c.Status(502).JSON(fiber.Map{"error": "Failed to fetch brand details", "details": err.Error()})
+	}
+	if brand.LogoURL == "" {
+		return c.Status(404).JSON(fiber.Map{"error": "No logo found for " + brand.Website})
+	}
+
+	if err := repo.SetBrand(c.Context(), id, brand.Website, brand.LogoURL); err != nil {
+		return companyError(c, err, "Failed to save logo")
+	}
+	company, err = repo.GetCompany(c.Context(), id)
+	if err != nil {
+		return companyError(c, err, "Failed to fetch company")
+	}
+	return c.JSON(company)
+}
+
+// GetCompanyHistory returns a company's track record with the college
+// @Summary Company History
+// @Description Every drive by the company with applicants and offers, plus drives, offers and CTC per year
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Company ID"
+// @Success 200 {object} models.CompanyHistory
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/companies/{id}/history [get]
+func GetCompanyHistory(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Company ID"})
+	}
+
+	history, err := repository.NewCompanyRepository(database.DB).GetCompanyHistory(c.Context(), id)
+	if err != nil {
+		return companyError(c, err, "Failed to fetch company history")
+	}
+	if history == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Company not found"})
+	}
+	return c.JSON(history)
+}
+
+// assignDriveCompany points a drive at its company, matching by website then name (creating the company
+// if it's new) unless drive.CompanyID is already set, and records the drive's SPOCs against the company.
+func assignDriveCompany(ctx context.Context, drive *models.PlacementDrive, spocs []models.DriveSpoc) error {
+	repo := repository.NewCompanyRepository(database.DB)
+	if drive.CompanyID == nil {
+		id, err := repo.ResolveCompany(ctx, drive.CompanyName, drive.Website, drive.LogoURL, drive.CompanyCategory)
+		if err != nil {
+			return err
+		}
+		drive.CompanyID = &id
+	}
+	for _, s := range spocs {
+		if err := repo.LinkSpoc(ctx, *drive.CompanyID, s.SpocID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyDriveCompany fills the blank company fields of a drive form from the company the admin picked.
+// Typed values win, so a drive can still carry its own spelling or logo.
+func applyDriveCompany(ctx context.Context, input *models.CreateDriveInput) error {
+	company, err := repository.NewCompanyRepository(database.DB).GetCompany(ctx, input.CompanyID)
+	if err != nil {
+		return err
+	}
+	if company == nil {
+		return repository.ErrCompanyNotFound
+	}
+	if input.CompanyName == "" {
+		input.CompanyName = company.Name
+	}
+	if input.Website == "" {
+		input.Website = company.Domain
+	}
+	if input.LogoURL == "" {
+		input.LogoURL = company.LogoURL
+	}
+	if input.CompanyCategory == "" {
+		input.CompanyCategory = company.Category
+	}
+	return nil
+}
@@ -3,8 +3,10 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/SysSyncer/placement-portal-kec/internal/database"
@@ -12,6 +14,7 @@ import (
 	"github.com/SysSyncer/placement-portal-kec/internal/repository"
 	"github.com/SysSyncer/placement-portal-kec/internal/services"
 	"github.com/SysSyncer/placement-portal-kec/internal/utils"
+	"github.com/SysSyncer/placement-portal-kec/internal/worker"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -55,6 +58,18 @@ func CreateDrive(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
 	}
 
+	spocs, err := driveSpocList(c.Context(), &input, nil)
+	if err != nil {
+		return driveSpocError(c, err)
+	}
+
+	// Picking an existing company fills in what the form left blank
+	if input.CompanyID != 0 {
+		if err := applyDriveCompany(c.Context(), &input); err != nil {
+			return companyError(c, err, "Failed to fetch company")
+		}
+	}
+
 	// 3. Handle File Uploads (S3)
 	files := form.File["attachments"]
 	var attachments []models.Attachment
@@ -138,12 +153,32 @@ func CreateDrive(c *fiber.Ctx) error {
 		DeadlineDate:        deadline,
 		DriveDate:           pgDate,
 	}
+	if input.CompanyID != 0 {
+		drive.CompanyID = &input.CompanyID
+	}
+	drive.SpocID = primaryDriveSpoc(spocs)
+
+	if err := assignDriveCompany(c.Context(), &drive, spocs); err != nil {
+		return companyError(c, err, "Failed to link company")
+	}
 
 	repo := repository.NewDriveRepository(database.DB)
-	if err := repo.CreateDrive(c.Context(), drive); err != nil {
+	driveID, err := repo.CreateDrive(c.Context(), drive, spocs)
+	if errors.Is(err, repository.ErrSpocNotFound) {
+		return driveSpocError(c, err)
+	}
+	if err != nil {
 		fmt.Printf("Error creating drive in DB: %v\n", err)
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to create drive", "details": err.Error()})
 	}
+	drive.ID = driveID
+
+	if drive.Spocs, err = driveSpocDetails(c.Context(), repo, driveID); err != nil {
+		fmt.Printf("Error fetching drive SPOCs: %v\n", err)
+	}
+
+	// Outgoing webhooks (Async)
+	go emitPortalEvent(models.PortalEventDrivePublished, drivePublishedData(drive))
 
 	// E. Send Notification to Eligible Students (Async)
 	go func(d models.PlacementDrive) {
@@ -183,6 +218,9 @@ func CreateDrive(c *fiber.Ctx) error {
 		}
 	}(drive)
 
+	// E.2 Send Email Alert with JD attached (Async)
+	go notifyNewDriveEmail(drive)
+
 	// F. Send WhatsApp Broadcast (Async)
 	go func(d models.PlacementDrive) {
 		// 1. Fetch Numbers
@@ -197,42 +235,22 @@ func CreateDrive(c *fiber.Ctx) error {
 			return
 		}
 
-		// 2. Initialize Service
-		waService := services.NewWhatsAppService()
+		// 2. Resolve the template configured for new drives
+		// Cloud API requires pre-approved templates for business-initiated messages.
+		// The mapping (template name + drive fields as variables) is managed via /v1/admin/whatsapp/templates.
+		tpl := worker.ResolveWhatsAppTemplate(context.Background(), models.WhatsAppEventNewDrive)
+		if tpl == nil {
+			fmt.Println("WhatsApp: new_drive template disabled, skipping broadcast.")
+			return
+		}
 
 		// 3. Send Template Message
-		// Cloud API requires templates for business-initiated messages.
-		// Template Name: new_placement_drive (assumption, user must create this in Meta Manager)
-		// Variables: {{1}}=Company, {{2}}=Role, {{3}}=Deadline
-
-		// If you haven't created a template yet, use "hello_world" (no params) for testing.
-		// For now, let's assume a generic template "new_drive_alert" exists.
-		// Or simpler: We just send "hello_world" to verify connectivity as requested.
-
-		// Better approach: Since we can't create templates dynamically via API,
-		// we will try to use the "hello_world" template which is standard for testing.
-		// Once the user verifies, they can create a real template.
-
-		templateName := "hello_world"
-		var components []interface{} // No params for hello_world
-
-		// If you have a custom template logic:
-		/*
-			templateName = "new_drive_alert"
-			components = []interface{}{
-				map[string]interface{}{
-					"type": "body",
-					"parameters": []map[string]string{
-						{"type": "text", "text": d.CompanyName},
-						{"type": "text", "text": d.JobRole},
-						{"type": "text", "text": d.DeadlineDate.Format("02 Jan")},
-					},
-				},
-			}
-		*/
-
-		count, _ := waService.SendBroadcast(numbers, templateName, components)
+		waService := services.NewWhatsAppService()
+		count, results := waService.BroadcastDriveTemplate(numbers, *tpl, d)
 		fmt.Printf("WhatsApp Cloud Broadcast: Sent to %d/%d students.\n", count, len(numbers))
+
+		// 4. Persist per-recipient delivery records (updated later by status webhooks)
+		recordWhatsAppResults(models.WhatsAppEventNewDrive, tpl.TemplateName, d.ID, results)
 	}(drive)
 
 	return c.Status(201).JSON(drive)
@@ -428,6 +446,12 @@ func UpdateDrive(c *fiber.Ctx) error {
 		}
 	}
 
+	if input.CompanyID != 0 {
+		if err := applyDriveCompany(c.Context(), &input); err != nil {
+			return companyError(c, err, "Failed to fetch company")
+		}
+	}
+
 	// Update Fields
 	if input.CompanyName != "" {
 		drive.CompanyName = input.CompanyName
@@ -453,8 +477,26 @@ func UpdateDrive(c *fiber.Ctx) error {
 	if input.CompanyCategory != "" {
 		drive.CompanyCategory = input.CompanyCategory
 	}
-	if input.SpocID != 0 {
-		drive.SpocID = input.SpocID
+	spocs, err := driveSpocList(c.Context(), &input, drive.Spocs)
+	if err != nil {
+		return driveSpocError(c, err)
+	}
+	if spocs != nil {
+		drive.SpocID = primaryDriveSpoc(spocs)
+	} else {
+		for _, s := range drive.Spocs {
+			spocs = append(spocs, models.DriveSpoc{SpocID: s.ID, IsPrimaryContact: s.IsPrimaryContact})
+		}
+	}
+
+	// Company: an explicit pick wins; a renamed (or never linked) drive is matched again
+	if input.CompanyID != 0 {
+		drive.CompanyID = &input.CompanyID
+	} else if input.CompanyName != "" || input.Website != "" {
+		drive.CompanyID = nil
+	}
+	if err := assignDriveCompany(c.Context(), drive, spocs); err != nil {
+		return companyError(c, err, "Failed to link company")
 	}
 
 	drive.CtcMin = input.CtcMin
@@ -520,9 +562,18 @@ func UpdateDrive(c *fiber.Ctx) error {
 		}
 	}
 
-	if err := repo.UpdateDrive(c.Context(), id, drive); err != nil {
+	// spocs is nil when the form doesn't touch SPOCs
+	if err := repo.UpdateDrive(c.Context(), id, drive, spocs); err != nil {
+		if errors.Is(err, repository.ErrSpocNotFound) {
+			return driveSpocError(c, err)
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to update drive"})
 	}
+	if spocs != nil {
+		if drive.Spocs, err = driveSpocDetails(c.Context(), repo, id); err != nil {
+			fmt.Printf("Error fetching drive SPOCs: %v\n", err)
+		}
+	}
 
 	// F. Send Notification to Eligible Students (Async)
 	go func(d models.PlacementDrive) {
@@ -674,7 +725,7 @@ func BulkDeleteDrives(c *fiber.Ctx) error {
 
 // AdminManualRegister - Handles POST /admin/drives/:id/add-student
 // @Summary Manually add a student to a drive
-// @Description Admin forces a student registration for a drive
+// @Description Admin forces a student registration for a drive, skipping deadline and eligibility checks. An existing application is moved back to opted_in only if the transition graph allows it; otherwise this returns 409 unless override is set with a remark.
 // @Tags Admin
 // @Accept json
 // @Produce json
@@ -683,6 +734,7 @@ func BulkDeleteDrives(c *fiber.Ctx) error {
 // @Param input body models.ManualRegisterInput true "Student ID"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/admin/drives/{id}/add-student [post]
 func AdminManualRegister(c *fiber.Ctx) error {
@@ -692,14 +744,118 @@ func AdminManualRegister(c *fiber.Ctx) error {
 	// Let's assume they send Student ID for now to keep it simple
 	var input models.ManualRegisterInput
 
-	if err := c.BodyParser(&input); err != nil {
+	if err := c.BodyParser(&input); err != nil || input.StudentID <= 0 {
 		return c.Status(400).JSON(fiber.Map{"error": "Student ID is required"})
 	}
+	input.Remark = strings.TrimSpace(input.Remark)
+	if input.Override && input.Remark == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "A remark explaining the override is required"})
+	}
 
 	repo := repository.NewDriveRepository(database.DB)
-	if err := repo.AdminForceRegister(c.Context(), driveID, input.StudentID); err != nil {
+	from, err := repo.AdminForceRegister(c.Context(), driveID, input.StudentID, statusActor(c), input.Remark, input.Override)
+	if errors.Is(err, repository.ErrInvalidTransition) {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error(), "from": from, "to": models.StatusOptedIn, "hint": "Set override with a remark to force this change"})
+	}
+	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Manual registration failed", "details": err.Error()})
 	}
+	if from == models.StatusOptedIn {
+		return c.JSON(fiber.Map{"message": "Student is already registered for this drive"})
+	}
+
+	go emitApplicationStatusChanged(driveID, input.StudentID, string(models.StatusOptedIn), "admin")
 
 	return c.JSON(fiber.Map{"message": "Student manually added to drive"})
 }
+
+var (
+	errDriveSpocRequired      = errors.New("every SPOC needs a spoc_id")
+	errDriveSpocMultiplePrime = errors.New("only one SPOC can be the primary contact")
+)
+
+// driveSpocList turns the form's SPOCs into the drive_spocs rows to store, checking they exist.
+// A bare spoc_id (older clients) becomes the primary contact, keeping the drive's other SPOCs in current.
+// Returns nil when the form doesn't touch SPOCs.
+func driveSpocList(ctx context.Context, input *models.CreateDriveInput, current []models.DriveSpocDetail) ([]models.DriveSpoc, error) {
+	var spocs []models.DriveSpoc
+	if len(input.Spocs) == 0 {
+		if input.SpocID == 0 {
+			return nil, nil
+		}
+		spocs = append(spocs, models.DriveSpoc{SpocID: input.SpocID, IsPrimaryContact: true})
+		for _, s := range current {
+			if s.ID != input.SpocID {
+				spocs = append(spocs, models.DriveSpoc{SpocID: s.ID})
+			}
+		}
+	} else {
+		seen := make(map[int64]bool, len(input.Spocs))
+		primaries := 0
+		for _, s := range input.Spocs {
+			if s.SpocID <= 0 {
+				return nil, errDriveSpocRequired
+			}
+			if seen[s.SpocID] {
+				continue
+			}
+			seen[s.SpocID] = true
+			if s.IsPrimaryContact {
+				primaries++
+			}
+			spocs = append(spocs, models.DriveSpoc{SpocID: s.SpocID, IsPrimaryContact: s.IsPrimaryContact})
+		}
+		if primaries > 1 {
+			return nil, errDriveSpocMultiplePrime
+		}
+		if primaries == 0 {
+			spocs[0].IsPrimaryContact = true
+		}
+	}
+
+	ids := make([]int64, len(spocs))
+	for i, s := range spocs {
+		ids[i] = s.SpocID
+	}
+	ok, err := repository.NewSpocRepository(database.DB).SpocsExist(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, repository.ErrSpocNotFound
+	}
+	return spocs, nil
+}
+
+// primaryDriveSpoc is the SPOC stored in placement_drives.spoc_id
+func primaryDriveSpoc(spocs []models.DriveSpoc) int64 {
+	for _, s := range spocs {
+		if s.IsPrimaryContact {
+			return s.SpocID
+		}
+	}
+	return 0
+}
+
+// driveSpocDetails loads a drive's SPOCs for the response
+func driveSpocDetails(ctx context.Context, repo *repository.DriveRepository, driveID int64) ([]models.DriveSpocDetail, error) {
+	spocs, err := repo.GetDriveSpocs(ctx, []int64{driveID})
+	if err != nil {
+		return nil, err
+	}
+	if spocs[driveID] == nil {
+		return []models.DriveSpocDetail{}, nil
+	}
+	return spocs[driveID], nil
+}
+
+func driveSpocError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, errDriveSpocRequired), errors.Is(err, errDriveSpocMultiplePrime):
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, repository.ErrSpocNotFound):
+		return c.Status(400).JSON(fiber.Map{"error": "SPOC not found"})
+	}
+	fmt.Printf("Error saving drive SPOCs: %v\n", err)
+	return c.Status(500).JSON(fiber.Map{"error": "Failed to save drive SPOCs"})
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// queryList splits a comma-separated query param, dropping blanks
+func queryList(c *fiber.Ctx, key string) []string {
+	var out []string
+	for _, v := range strings.Split(c.Query(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// parseDriveSearchQuery reads the shared search params of the student and admin endpoints
+func parseDriveSearchQuery(c *fiber.Ctx) (models.DriveSearchQuery, error) {
+	q := models.DriveSearchQuery{
+		Q:                 strings.TrimSpace(c.Query("q")),
+		DriveTypes:        queryList(c, "type"),
+		CompanyCategories: queryList(c, "category"),
+		Locations:         queryList(c, "location"),
+		CtcRanges:         queryList(c, "ctc_range"),
+		Sort:              c.Query("sort"),
+	}
+
+	switch q.Sort {
+	case "", "relevance", "deadline", "ctc", "newest":
+	default:
+		return q, fmt.Errorf("Invalid sort. Must be one of: relevance, deadline, ctc, newest")
+	}
+	for _, key := range q.CtcRanges {
+		valid := key == "undisclosed"
+		for _, rg := range models.DriveCtcRanges {
+			valid = valid || rg.Key == key
+		}
+		if !valid {
+			return q, fmt.Errorf("Invalid ctc_range %q", key)
+		}
+	}
+	if v := c.Query("ctc_min"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return q, fmt.Errorf("Invalid ctc_min")
+		}
+		q.CtcMin = n
+	}
+
+	q.Page, _ = strconv.Atoi(c.Query("page", "1"))
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	q.Limit, _ = strconv.Atoi(c.Query("limit", "20"))
+	if q.Limit < 1 || q.Limit > 100 {
+		q.Limit = 20
+	}
+	return q, nil
+}
+
+func driveSearchResponse(c *fiber.Ctx, q models.DriveSearchQuery, studentID int64) error {
+	drives, total, facets, err := repository.NewDriveRepository(database.DB).SearchDrives(c.Context(), q, studentID)
+	if err != nil {
+		fmt.Printf("Error searching drives: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Could not search drives"})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":   drives,
+		"facets": facets,
+		"meta": fiber.Map{
+			"total":       total,
+			"page":        q.Page,
+			"limit":       q.Limit,
+			"total_pages": (total + int64(q.Limit) - 1) / int64(q.Limit),
+		},
+	})
+}
+
+// SearchStudentDrives - Full-text search over the drives the student is eligible for
+// @Summary Search Drives (Student)
+// @Description Full-text search over company, role, description and location, limited to open drives the student is eligible for. Returns facet counts for drive type, company category, location and CTC range.
+// @Tags Drives
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Search text (supports \"quoted phrases\", OR and -exclusions)"
+// @Param type query string false "Drive types, comma-separated"
+// @Param category query string false "Company categories, comma-separated"
+// @Param location query string false "Locations, comma-separated"
+// @Param ctc_range query string false "CTC buckets, comma-separated" Enums(0-3, 3-6, 6-10, 10-20, 20+, undisclosed)
+// @Param ctc_min query int false "Minimum CTC per annum"
+// @Param sort query string false "Sort order (relevance is the default when q is set, else deadline)" Enums(relevance, deadline, ctc, newest)
+// @Param page query int false "Page Number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/drives/search [get]
+func SearchStudentDrives(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	q, err := parseDriveSearchQuery(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return driveSearchResponse(c, q, userID)
+}
+
+// SearchAdminDrives - Full-text search over all drives
+// @Summary Search Drives (Admin)
+// @Description Same search and facets as the student endpoint across every drive, with an optional status filter
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Search text"
+// @Param status query string false "Drive statuses, comma-separated (e.g. open,closed)"
+// @Param type query string false "Drive types, comma-separated"
+// @Param category query string false "Company categories, comma-separated"
+// @Param location query string false "Locations, comma-separated"
+// @Param ctc_range query string false "CTC buckets, comma-separated"
+// @Param ctc_min query int false "Minimum CTC per annum"
+// @Param sort query string false "Sort order" Enums(relevance, deadline, ctc, newest)
+// @Param page query int false "Page Number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/drives/search [get]
+func SearchAdminDrives(c *fiber.Ctx) error {
+	q, err := parseDriveSearchQuery(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	q.Statuses = queryList(c, "status")
+
+	return driveSearchResponse(c, q, 0)
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/services"
+	"github.com/SysSyncer/placement-portal-kec/internal/utils"
+)
+
+// Gmail rejects messages over 25MB; base64 adds ~33%, so keep raw attachments well under that
+const maxEmailAttachmentBytes = 15 << 20
+
+// driveEmailData is the shared template data for drive emails
+func driveEmailData(d models.PlacementDrive) map[string]interface{} {
+	driveDate := ""
+	if d.DriveDate.Valid {
+		driveDate = d.DriveDate.Time.Format("02 Jan 2006")
+	}
+	return map[string]interface{}{
+		"CompanyName": d.CompanyName,
+		"JobRole":     d.JobRole,
+		"DriveType":   d.DriveType,
+		"Location":    d.Location,
+		"CTC":         d.CtcDisplay,
+		"DriveDate":   driveDate,
+		"Deadline":    d.DeadlineDate.Format("02 Jan 2006, 03:04 PM"),
+	}
+}
+
+// driveEmailAttachments downloads the drive's attachments (JDs) from S3, skipping any that fail
+// or would push the email over the size limit.
+func driveEmailAttachments(d models.PlacementDrive) []services.EmailAttachment {
+	var attachments []services.EmailAttachment
+	var total int64
+	for _, a := range d.Attachments {
+		path := utils.ExtractPathFromURL(a.URL)
+		if path == "" {
+			continue
+		}
+		data, contentType, err := utils.DownloadFromS3(path, maxEmailAttachmentBytes-total)
+		if err != nil {
+			fmt.Printf("Email: Skipping attachment %s: %v\n", a.Name, err)
+			continue
+		}
+		total += int64(len(data))
+
+		name := a.Name
+		if name == "" {
+			name = filepath.Base(path)
+		}
+		attachments = append(attachments, services.EmailAttachment{Filename: name, ContentType: contentType, Data: data})
+	}
+	return attachments
+}
+
+// notifyNewDriveEmail emails every eligible student about a new drive with its JD attached (Async helper)
+func notifyNewDriveEmail(d models.PlacementDrive) {
+	ctx := context.Background()
+	contacts, err := repository.NewDriveRepository(database.DB).GetEligibleStudentContacts(ctx, d)
+	if err != nil {
+		fmt.Printf("Email Error: Failed to fetch eligible students: %v\n", err)
+		return
+	}
+	if len(contacts) == 0 {
+		fmt.Println("Email: No eligible students found.")
+		return
+	}
+
+	attachments := driveEmailAttachments(d)
+	shared := driveEmailData(d)
+	shared["HasAttachments"] = len(attachments) > 0
+
+	recipients := make([]services.BulkEmailRecipient, 0, len(contacts))
+	for _, sc := range contacts {
+		recipients = append(recipients, services.BulkEmailRecipient{
+			Email: sc.Email,
+			Data:  map[string]interface{}{"Name": sc.FullName},
+		})
+	}
+
+	sent, failures := services.NewEmailService().SendBulk(services.EmailTemplateNewDrive, shared, recipients, attachments)
+	for email, err := range failures {
+		fmt.Printf("Email Error: Failed to send drive alert to %s: %v\n", email, err)
+	}
+	fmt.Printf("Email: Sent drive alert for %s to %d/%d students.\n", d.CompanyName, sent, len(recipients))
+}
+
+// notifyApplicationStatusEmail emails a student when an admin changes their application status (Async helper)
+func notifyApplicationStatusEmail(driveID, studentID int64, status string) {
+	ctx := context.Background()
+	drive, err := repository.NewDriveRepository(database.DB).GetDriveByID(ctx, driveID)
+	if err != nil {
+		fmt.Printf("Email Error: Drive %d not found: %v\n", driveID, err)
+		return
+	}
+	contact, err := repository.NewUserRepository(database.DB).GetStudentContact(ctx, studentID)
+	if err != nil {
+		fmt.Printf("Email: Skipping student %d: %v\n", studentID, err)
+		return
+	}
+
+	data := driveEmailData(*drive)
+	data["Name"] = contact.FullName
+	data["Status"] = status
+	data["StatusLabel"] = botStatusLabel(status)
+
+	if err := services.NewEmailService().SendTemplate([]string{contact.Email}, services.EmailTemplateApplicationStatus, data, nil); err != nil {
+		fmt.Printf("Email Error: Failed to send status update to %s: %v\n", contact.Email, err)
+	}
+}
+
+// notifyAdminsWithdrawalAttempt alerts admins (email + push) that a shortlisted student tried to withdraw (Async helper)
+func notifyAdminsWithdrawalAttempt(driveID, studentID int64, reason string) {
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository(database.DB)
+	emails, tokens, err := userRepo.GetAdminNotificationTargets(ctx)
+	if err != nil {
+		fmt.Printf("Email Error: Failed to fetch admins: %v\n", err)
+		return
+	}
+	if len(emails) == 0 {
+		return
+	}
+	drive, err := repository.NewDriveRepository(database.DB).GetDriveByID(ctx, driveID)
+	if err != nil {
+		fmt.Printf("Email Error: Drive %d not found: %v\n", driveID, err)
+		return
+	}
+	contact, err := userRepo.GetStudentContact(ctx, studentID)
+	if err != nil {
+		fmt.Printf("Email: Skipping withdrawal alert for student %d: %v\n", studentID, err)
+		return
+	}
+
+	data := driveEmailData(*drive)
+	data["StudentName"] = contact.FullName
+	data["StudentEmail"] = contact.Email
+	data["Reason"] = reason
+	data["AttemptedAt"] = time.Now().Format("02 Jan 2006, 03:04 PM")
+	if err := services.NewEmailService().SendTemplate(emails, services.EmailTemplateWithdrawalAttempt, data, nil); err != nil {
+		fmt.Printf("Email Error: Failed to send withdrawal alert: %v\n", err)
+	}
+
+	// Push is best effort; email above is the primary channel
+	if len(tokens) > 0 {
+		ns, err := services.NewNotificationService("firebase-service-account.json")
+		if err != nil {
+			fmt.Printf("Notification Error: Failed to init service: %v\n", err)
+			return
+		}
+		title := "Withdrawal attempt"
+		body := fmt.Sprintf("%s (shortlisted) tried to withdraw from %s: %s", contact.FullName, drive.CompanyName, reason)
+		pushData := map[string]string{
+			"type":       "withdrawal_attempt",
+			"drive_id":   strconv.FormatInt(driveID, 10),
+			"student_id": strconv.FormatInt(studentID, 10),
+		}
+		if _, err := ns.SendMulticastNotification(ctx, tokens, title, body, pushData); err != nil {
+			fmt.Printf("Notification Error: Send failed: %v\n", err)
+		}
+	}
+}
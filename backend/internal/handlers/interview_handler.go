@@ -0,0 +1,565 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// roundName resolves a round index against the drive's rounds.
+// Drives without rounds defined get a single "Interview" round at index 0.
+func roundName(d *models.PlacementDrive, index int) (string, bool) {
+	if len(d.Rounds) == 0 {
+		return "Interview", index == 0
+	}
+	if index < 0 || index >= len(d.Rounds) {
+		return "", false
+	}
+	return d.Rounds[index].Name, true
+}
+
+// slotFromInput converts validated input into a slot, resolving the round name
+func slotFromInput(d *models.PlacementDrive, in models.InterviewSlotInput) (models.InterviewSlot, error) {
+	name, ok := roundName(d, in.RoundIndex)
+	if !ok {
+		return models.InterviewSlot{}, fmt.Errorf("round_index %d does not match a round of this drive", in.RoundIndex)
+	}
+	return models.InterviewSlot{
+		DriveID:     d.ID,
+		RoundIndex:  in.RoundIndex,
+		RoundName:   name,
+		PanelName:   in.PanelName,
+		PanelEmails: in.PanelEmails,
+		Location:    in.Location,
+		MeetingLink: in.MeetingLink,
+		StartTime:   in.StartTime,
+		EndTime:     in.EndTime,
+		Capacity:    in.Capacity,
+	}, nil
+}
+
+// CreateInterviewSlots defines interview slots for a drive round
+// @Summary Create Interview Slots
+// @Description Create one or more interview slots (panel, room or online link, time window, capacity) for rounds of a drive
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Param slots body models.CreateInterviewSlotsInput true "Slots"
+// @Success 201 {array} models.InterviewSlot
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/drives/{id}/slots [post]
+func CreateInterviewSlots(c *fiber.Ctx) error {
+	driveID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Drive ID"})
+	}
+
+	var input models.CreateInterviewSlotsInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	drive, err := repository.NewDriveRepository(database.DB).GetDriveByID(c.Context(), driveID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Drive not found"})
+	}
+
+	slots := make([]models.InterviewSlot, 0, len(input.Slots))
+	for _, in := range input.Slots {
+		slot, err := slotFromInput(drive, in)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		slots = append(slots, slot)
+	}
+
+	repo := repository.NewInterviewRepository(database.DB)
+	created, err := repo.CreateSlots(c.Context(), driveID, slots)
+	if err != nil {
+		fmt.Printf("Error creating interview slots for drive %d: %v\n", driveID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create slots"})
+	}
+
+	return c.Status(201).JSON(created)
+}
+
+// ListDriveSlots returns a drive's slots with the students booked into each
+// @Summary List Interview Slots
+// @Description List a drive's interview slots with booked students, optionally for one round
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Param round_index query int false "Round (position in drive rounds)"
+// @Success 200 {array} models.InterviewSlot
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/drives/{id}/slots [get]
+func ListDriveSlots(c *fiber.Ctx) error {
+	driveID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Drive ID"})
+	}
+	round, err := roundQuery(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid round_index"})
+	}
+
+	repo := repository.NewInterviewRepository(database.DB)
+	slots, err := repo.ListSlots(c.Context(), driveID, round, true)
+	if err != nil {
+		fmt.Printf("Error fetching interview slots for drive %d: %v\n", driveID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch slots"})
+	}
+
+	return c.JSON(slots)
+}
+
+// UpdateInterviewSlot changes a slot's panel, venue, time or capacity
+// @Summary Update Interview Slot
+// @Description Update a slot. Capacity cannot go below the number of students already booked. Booked students are notified when the time or venue changes.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Slot ID"
+// @Param slot body models.InterviewSlotInput true "Slot"
+// @Success 200 {object} models.InterviewSlot
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /v1/admin/interview-slots/{id} [put]
+func UpdateInterviewSlot(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Slot ID"})
+	}
+
+	var input models.InterviewSlotInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	repo := repository.NewInterviewRepository(database.DB)
+	existing, err := repo.GetSlot(c.Context(), id)
+	if err != nil {
+		fmt.Printf("Error fetching interview slot %d: %v\n", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch slot"})
+	}
+	if existing == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Slot not found"})
+	}
+	if input.RoundIndex != existing.RoundIndex && existing.BookedCount > 0 {
+		return c.Status(409).JSON(fiber.Map{"error": "Cannot move a slot with bookings to another round"})
+	}
+
+	drive, err := repository.NewDriveRepository(database.DB).GetDriveByID(c.Context(), existing.DriveID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Drive not found"})
+	}
+	slot, err := slotFromInput(drive, input)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	slot.ID = id
+
+	if err := repo.UpdateSlot(c.Context(), slot); err != nil {
+		if errors.Is(err, repository.ErrSlotNotFound) {
+			return c.Status(404).JSON(fiber.Map{"error": "Slot not found"})
+		}
+		if errors.Is(err, repository.ErrSlotFull) {
+			return c.Status(409).JSON(fiber.Map{"error": "Capacity is below the number of booked students"})
+		}
+		fmt.Printf("Error updating interview slot %d: %v\n", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update slot"})
+	}
+
+	// Booked students get the new time / venue with an updated invite (Async)
+	moved := !slot.StartTime.Equal(existing.StartTime) || !slot.EndTime.Equal(existing.EndTime) ||
+		slot.Location != existing.Location || slot.MeetingLink != existing.MeetingLink
+	if moved && existing.BookedCount > 0 {
+		go notifySlotRescheduled(*drive, slot.ID, slot.RoundIndex)
+	}
+
+	updated, err := repo.GetSlot(c.Context(), id)
+	if err != nil || updated == nil {
+		return c.JSON(slot)
+	}
+	return c.JSON(updated)
+}
+
+// notifySlotRescheduled re-sends the slot to everyone booked into it (Async helper)
+func notifySlotRescheduled(d models.PlacementDrive, slotID int64, roundIndex int) {
+	slots, err := repository.NewInterviewRepository(database.DB).ListSlots(context.Background(), d.ID, &roundIndex, true)
+	if err != nil {
+		fmt.Printf("Interview: Failed to load bookings of slot %d: %v\n", slotID, err)
+		return
+	}
+	for _, s := range slots {
+		if s.ID == slotID {
+			notifyInterviewBookings(d, s.Bookings, true)
+			return
+		}
+	}
+}
+
+// DeleteInterviewSlot removes a slot nobody is booked into
+// @Summary Delete Interview Slot
+// @Description Delete an interview slot. Slots with booked students cannot be deleted.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Slot ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /v1/admin/interview-slots/{id} [delete]
+func DeleteInterviewSlot(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Slot ID"})
+	}
+
+	repo := repository.NewInterviewRepository(database.DB)
+	existing, err := repo.GetSlot(c.Context(), id)
+	if err != nil {
+		fmt.Printf("Error fetching interview slot %d: %v\n", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch slot"})
+	}
+	if existing == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Slot not found"})
+	}
+
+	if err := repo.DeleteSlot(c.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrSlotHasBookings) {
+			return c.Status(409).JSON(fiber.Map{"error": "Slot has booked students"})
+		}
+		fmt.Printf("Error deleting interview slot %d: %v\n", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete slot"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Slot deleted"})
+}
+
+// AllocateInterviewSlots auto-allocates shortlisted students to a round's slots
+// @Summary Auto-allocate Interview Slots
+// @Description Fill a round's upcoming slots (earliest first) with shortlisted students who don't have a slot yet, and notify them
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Param round body models.RoundInput true "Round"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/drives/{id}/slots/allocate [post]
+func AllocateInterviewSlots(c *fiber.Ctx) error {
+	driveID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Drive ID"})
+	}
+
+	var input models.RoundInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+
+	drive, err := repository.NewDriveRepository(database.DB).GetDriveByID(c.Context(), driveID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Drive not found"})
+	}
+	if _, ok := roundName(drive, input.RoundIndex); !ok {
+		return c.Status(400).JSON(fiber.Map{"error": "round_index does not match a round of this drive"})
+	}
+
+	repo := repository.NewInterviewRepository(database.DB)
+	allocated, unallocated, err := repo.AllocateShortlisted(c.Context(), driveID, input.RoundIndex)
+	if err != nil {
+		fmt.Printf("Error allocating interview slots for drive %d: %v\n", driveID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to allocate slots"})
+	}
+
+	// Notify allocated students (Async)
+	if len(allocated) > 0 {
+		go notifyInterviewBookings(*drive, allocated, false)
+	}
+
+	return c.JSON(fiber.Map{
+		"message":     fmt.Sprintf("Allocated %d students", len(allocated)),
+		"allocated":   len(allocated),
+		"unallocated": unallocated, // Not enough capacity: add slots and allocate again
+		"bookings":    allocated,
+	})
+}
+
+// NotifyInterviewPanels emails each panel its schedule for a round
+// @Summary Notify Interview Panels
+// @Description Email every panel of a round its slots and booked students, with a calendar file attached
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Param round body models.RoundInput true "Round"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/drives/{id}/slots/notify-panels [post]
+func NotifyInterviewPanels(c *fiber.Ctx) error {
+	driveID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Drive ID"})
+	}
+
+	var input models.RoundInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+
+	drive, err := repository.NewDriveRepository(database.DB).GetDriveByID(c.Context(), driveID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Drive not found"})
+	}
+
+	round := input.RoundIndex
+	slots, err := repository.NewInterviewRepository(database.DB).ListSlots(c.Context(), driveID, &round, true)
+	if err != nil {
+		fmt.Printf("Error fetching interview slots for drive %d: %v\n", driveID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch slots"})
+	}
+	if len(slots) == 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "No slots defined for this round"})
+	}
+
+	go notifyInterviewPanels(*drive, slots)
+
+	return c.Status(202).JSON(fiber.Map{"message": "Panel schedules are being sent"})
+}
+
+// ExportDriveSlotsICal exports a drive's slots as an iCalendar file
+// @Summary Export Interview Slots (iCal)
+// @Description Download a drive's interview slots as an .ics file, optionally for one round
+// @Tags Admin
+// @Produce text/calendar
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Param round_index query int false "Round (position in drive rounds)"
+// @Success 200 {string} string "iCalendar file"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/drives/{id}/slots.ics [get]
+func ExportDriveSlotsICal(c *fiber.Ctx) error {
+	driveID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Drive ID"})
+	}
+	round, err := roundQuery(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid round_index"})
+	}
+
+	drive, err := repository.NewDriveRepository(database.DB).GetDriveByID(c.Context(), driveID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Drive not found"})
+	}
+
+	slots, err := repository.NewInterviewRepository(database.DB).ListSlots(c.Context(), driveID, round, true)
+	if err != nil {
+		fmt.Printf("Error fetching interview slots for drive %d: %v\n", driveID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch slots"})
+	}
+
+	events := make([]utils.ICalEvent, 0, len(slots))
+	for _, s := range slots {
+		events = append(events, slotICalEvent(*drive, s))
+	}
+
+	return sendICal(c, fmt.Sprintf("%s-interviews.ics", drive.CompanyName), drive.CompanyName+" Interviews", events)
+}
+
+// ListStudentDriveSlots shows a shortlisted student the slots they can book
+// @Summary List Bookable Interview Slots
+// @Description List a drive's upcoming interview slots with free seats. Only available to shortlisted students.
+// @Tags Student
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Success 200 {array} models.InterviewSlot
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /v1/drives/{id}/slots [get]
+func ListStudentDriveSlots(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	driveID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Drive ID"})
+	}
+
+	repo := repository.NewInterviewRepository(database.DB)
+	shortlisted, err := repo.IsShortlisted(c.Context(), driveID, userID)
+	if err != nil {
+		fmt.Printf("Error checking shortlist for student %d drive %d: %v\n", userID, driveID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch slots"})
+	}
+	if !shortlisted {
+		return c.Status(403).JSON(fiber.Map{"error": "Slots are only available to shortlisted students"})
+	}
+
+	slots, err := repo.ListSlots(c.Context(), driveID, nil, true)
+	if err != nil {
+		fmt.Printf("Error fetching interview slots for drive %d: %v\n", driveID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch slots"})
+	}
+
+	// Students see seat counts and their own booking, never who else is booked
+	for i := range slots {
+		for _, b := range slots[i].Bookings {
+			if b.StudentID == userID {
+				slots[i].IsMine = true
+			}
+		}
+		slots[i].Bookings = nil
+		slots[i].PanelEmails = nil
+	}
+
+	return c.JSON(slots)
+}
+
+// BookInterviewSlot books the student into a slot, replacing their slot for that round
+// @Summary Book Interview Slot
+// @Description Pick an interview slot. Moving to another slot of the same round releases the old one.
+// @Tags Student
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Slot ID"
+// @Success 200 {object} models.InterviewSlot
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /v1/interview-slots/{id}/book [post]
+func BookInterviewSlot(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	slotID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Slot ID"})
+	}
+
+	repo := repository.NewInterviewRepository(database.DB)
+	slot, err := repo.BookSlot(c.Context(), slotID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrSlotNotFound):
+			return c.Status(404).JSON(fiber.Map{"error": "Slot not found"})
+		case errors.Is(err, repository.ErrNotShortlisted):
+			return c.Status(403).JSON(fiber.Map{"error": "Slots are only available to shortlisted students"})
+		case errors.Is(err, repository.ErrSlotStarted):
+			return c.Status(409).JSON(fiber.Map{"error": "This slot has already started"})
+		case errors.Is(err, repository.ErrSlotFull):
+			return c.Status(409).JSON(fiber.Map{"error": "This slot is full"})
+		}
+		fmt.Printf("Error booking slot %d for student %d: %v\n", slotID, userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to book slot"})
+	}
+
+	// Send the confirmation with a calendar invite (Async)
+	go func(s models.InterviewSlot) {
+		drive, err := repository.NewDriveRepository(database.DB).GetDriveByID(context.Background(), s.DriveID)
+		if err != nil {
+			fmt.Printf("Email Error: Drive %d not found: %v\n", s.DriveID, err)
+			return
+		}
+		notifyInterviewBookings(*drive, []models.InterviewBooking{{SlotID: s.ID, StudentID: userID, Source: models.BookingSourceStudent}}, false)
+	}(*slot)
+
+	slot.IsMine = true
+	slot.PanelEmails = nil
+	return c.JSON(slot)
+}
+
+// ListMyInterviews returns the student's booked interview slots
+// @Summary My Interviews
+// @Description List the logged-in student's interview slots across drives, soonest first
+// @Tags Student
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.StudentInterview
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/interviews [get]
+func ListMyInterviews(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+
+	interviews, err := repository.NewInterviewRepository(database.DB).GetStudentInterviews(c.Context(), userID)
+	if err != nil {
+		fmt.Printf("Error fetching interviews for student %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch interviews"})
+	}
+
+	return c.JSON(interviews)
+}
+
+// ExportMyInterviewsICal exports the student's interview slots as an iCalendar file
+// @Summary Export My Interviews (iCal)
+// @Description Download the logged-in student's interview slots as an .ics file
+// @Tags Student
+// @Produce text/calendar
+// @Security BearerAuth
+// @Success 200 {string} string "iCalendar file"
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/interviews.ics [get]
+func ExportMyInterviewsICal(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+
+	interviews, err := repository.NewInterviewRepository(database.DB).GetStudentInterviews(c.Context(), userID)
+	if err != nil {
+		fmt.Printf("Error fetching interviews for student %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch interviews"})
+	}
+
+	events := make([]utils.ICalEvent, 0, len(interviews))
+	for _, i := range interviews {
+		events = append(events, studentInterviewICalEvent(userID, i))
+	}
+
+	return sendICal(c, "interviews.ics", "Placement Interviews", events)
+}
+
+// roundQuery parses the optional round_index query parameter
+func roundQuery(c *fiber.Ctx) (*int, error) {
+	v := c.Query("round_index")
+	if v == "" {
+		return nil, nil
+	}
+	round, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, err
+	}
+	return &round, nil
+}
+
+func sendICal(c *fiber.Ctx, filename, calendarName string, events []utils.ICalEvent) error {
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Send(utils.BuildICal(calendarName, events))
+}
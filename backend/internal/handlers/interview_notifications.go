@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/services"
+	"github.com/SysSyncer/placement-portal-kec/internal/utils"
+)
+
+const (
+	interviewTimeFormat = "Mon, 02 Jan 2006 03:04 PM"
+	interviewEndFormat  = "03:04 PM"
+)
+
+// slotICalEvent is a slot as a calendar event (admin and panel exports)
+func slotICalEvent(d models.PlacementDrive, s models.InterviewSlot) utils.ICalEvent {
+	desc := fmt.Sprintf("%s (%s)\nPanel: %s\nBooked: %d/%d", d.CompanyName, d.JobRole, s.PanelName, s.BookedCount, s.Capacity)
+	for _, b := range s.Bookings {
+		desc += fmt.Sprintf("\n- %s (%s)", b.FullName, b.RegisterNumber)
+	}
+	return utils.ICalEvent{
+		UID:         fmt.Sprintf("interview-slot-%d@kec-placement-portal", s.ID),
+		Summary:     fmt.Sprintf("%s %s – %s", d.CompanyName, s.RoundName, s.PanelName),
+		Description: desc,
+		Location:    s.Location,
+		URL:         s.MeetingLink,
+		Start:       s.StartTime,
+		End:         s.EndTime,
+	}
+}
+
+// studentInterviewICalEvent is a student's booking as a calendar event.
+// The UID is per drive round, so rebooking another slot moves the event instead of adding one.
+func studentInterviewICalEvent(studentID int64, i models.StudentInterview) utils.ICalEvent {
+	desc := fmt.Sprintf("%s – %s\nPanel: %s", i.CompanyName, i.JobRole, i.PanelName)
+	if i.MeetingLink != "" {
+		desc += "\nJoin: " + i.MeetingLink
+	}
+	return utils.ICalEvent{
+		UID:         fmt.Sprintf("interview-%d-%d-%d@kec-placement-portal", i.DriveID, i.RoundIndex, studentID),
+		Summary:     fmt.Sprintf("%s %s Interview", i.CompanyName, i.RoundName),
+		Description: desc,
+		Location:    i.Location,
+		URL:         i.MeetingLink,
+		Start:       i.StartTime,
+		End:         i.EndTime,
+		Updated:     i.BookedAt,
+	}
+}
+
+// notifyInterviewBookings sends each booked student their slot by push and by email with a calendar invite (Async helper).
+// rescheduled says the slot's time or venue changed after the students were booked.
+func notifyInterviewBookings(d models.PlacementDrive, bookings []models.InterviewBooking, rescheduled bool) {
+	ctx := context.Background()
+	repo := repository.NewInterviewRepository(database.DB)
+	userRepo := repository.NewUserRepository(database.DB)
+
+	ids := make([]int64, 0, len(bookings))
+	for _, b := range bookings {
+		ids = append(ids, b.StudentID)
+	}
+	tokens, err := userRepo.GetFCMTokensByIDs(ctx, ids)
+	if err != nil {
+		fmt.Printf("Notification Error: Failed to fetch tokens: %v\n", err)
+	}
+
+	var ns *services.NotificationService
+	if len(tokens) > 0 {
+		if ns, err = services.NewNotificationService("firebase-service-account.json"); err != nil {
+			fmt.Printf("Notification Error: Failed to init service: %v\n", err)
+		}
+	}
+	es := services.NewEmailService()
+	title := "Interview Scheduled"
+	if rescheduled {
+		title = "Interview Rescheduled"
+	}
+
+	for _, b := range bookings {
+		slot, err := repo.GetSlot(ctx, b.SlotID)
+		if err != nil || slot == nil {
+			fmt.Printf("Interview: Slot %d not found for student %d: %v\n", b.SlotID, b.StudentID, err)
+			continue
+		}
+		when := slot.StartTime.Local().Format(interviewTimeFormat)
+
+		// 1. Push
+		if token, ok := tokens[b.StudentID]; ok && ns != nil {
+			data := map[string]string{
+				"drive_id": strconv.FormatInt(d.ID, 10),
+				"slot_id":  strconv.FormatInt(slot.ID, 10),
+				"type":     "interview_slot",
+			}
+			body := fmt.Sprintf("%s %s interview: %s", d.CompanyName, slot.RoundName, when)
+			if _, err := ns.SendMulticastNotification(ctx, []string{token}, title, body, data); err != nil {
+				fmt.Printf("Notification Error: Interview push to %d failed: %v\n", b.StudentID, err)
+			}
+		}
+
+		// 2. Email with the invite attached
+		contact, err := userRepo.GetStudentContact(ctx, b.StudentID)
+		if err != nil {
+			fmt.Printf("Email: Skipping student %d: %v\n", b.StudentID, err)
+			continue
+		}
+		event := studentInterviewICalEvent(b.StudentID, models.StudentInterview{
+			SlotID: slot.ID, DriveID: d.ID, CompanyName: d.CompanyName, JobRole: d.JobRole,
+			RoundIndex: slot.RoundIndex, RoundName: slot.RoundName, PanelName: slot.PanelName,
+			Location: slot.Location, MeetingLink: slot.MeetingLink, StartTime: slot.StartTime, EndTime: slot.EndTime,
+			BookedAt: b.BookedAt,
+		})
+		if rescheduled {
+			event.Updated = time.Now() // Calendars replace the earlier invite with the same UID
+		}
+		invite := services.EmailAttachment{
+			Filename:    "interview.ics",
+			ContentType: "text/calendar; charset=utf-8; method=PUBLISH",
+			Data:        utils.BuildICal(d.CompanyName+" Interview", []utils.ICalEvent{event}),
+		}
+
+		data := map[string]interface{}{
+			"Name":        contact.FullName,
+			"CompanyName": d.CompanyName,
+			"JobRole":     d.JobRole,
+			"RoundName":   slot.RoundName,
+			"PanelName":   slot.PanelName,
+			"Location":    slot.Location,
+			"MeetingLink": slot.MeetingLink,
+			"Start":       when,
+			"End":         slot.EndTime.Local().Format(interviewEndFormat),
+			"Rescheduled": rescheduled,
+		}
+		if err := es.SendTemplate([]string{contact.Email}, services.EmailTemplateInterviewSlot, data, []services.EmailAttachment{invite}); err != nil {
+			fmt.Printf("Email Error: Failed to send interview slot to %s: %v\n", contact.Email, err)
+		}
+	}
+}
+
+// notifyInterviewPanels emails each panel (grouped by panel name) its slots and booked students (Async helper)
+func notifyInterviewPanels(d models.PlacementDrive, slots []models.InterviewSlot) {
+	type panel struct {
+		emails map[string]bool
+		slots  []models.InterviewSlot
+	}
+	panels := make(map[string]*panel)
+	var order []string
+	for _, s := range slots {
+		p, ok := panels[s.PanelName]
+		if !ok {
+			p = &panel{emails: make(map[string]bool)}
+			panels[s.PanelName] = p
+			order = append(order, s.PanelName)
+		}
+		for _, e := range s.PanelEmails {
+			p.emails[e] = true
+		}
+		p.slots = append(p.slots, s)
+	}
+
+	es := services.NewEmailService()
+	for _, name := range order {
+		p := panels[name]
+		if len(p.emails) == 0 {
+			fmt.Printf("Interview: Panel %q has no emails, skipping.\n", name)
+			continue
+		}
+
+		var to []string
+		for e := range p.emails {
+			to = append(to, e)
+		}
+
+		var slotData []map[string]interface{}
+		events := make([]utils.ICalEvent, 0, len(p.slots))
+		for _, s := range p.slots {
+			slotData = append(slotData, map[string]interface{}{
+				"Start":       s.StartTime.Local().Format(interviewTimeFormat),
+				"End":         s.EndTime.Local().Format(interviewEndFormat),
+				"Location":    s.Location,
+				"MeetingLink": s.MeetingLink,
+				"Students":    s.Bookings,
+			})
+			events = append(events, slotICalEvent(d, s))
+		}
+
+		data := map[string]interface{}{
+			"PanelName":   name,
+			"CompanyName": d.CompanyName,
+			"JobRole":     d.JobRole,
+			"RoundName":   p.slots[0].RoundName,
+			"Slots":       slotData,
+		}
+		invite := services.EmailAttachment{
+			Filename:    "panel-schedule.ics",
+			ContentType: "text/calendar; charset=utf-8; method=PUBLISH",
+			Data:        utils.BuildICal(fmt.Sprintf("%s – %s", d.CompanyName, name), events),
+		}
+		if err := es.SendTemplate(to, services.EmailTemplatePanelSchedule, data, []services.EmailAttachment{invite}); err != nil {
+			fmt.Printf("Email Error: Failed to send schedule to panel %q: %v\n", name, err)
+		}
+	}
+}
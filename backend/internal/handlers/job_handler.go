@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/worker"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListJobs returns every background job with its schedule and last run
+// @Summary List Background Jobs
+// @Description List scheduled background jobs with their cron schedule, next run time and last run outcome
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.JobInfo
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/jobs [get]
+func ListJobs(c *fiber.Ctx) error {
+	jobs, err := worker.DefaultScheduler.Jobs(c.Context())
+	if err != nil {
+		fmt.Printf("Error listing jobs: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch jobs"})
+	}
+
+	return c.JSON(jobs)
+}
+
+// TriggerJob runs a background job now
+// @Summary Run Background Job
+// @Description Start a job immediately. It runs in the background; poll the returned run for the outcome.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Job Name (e.g. close_expired_drives)"
+// @Success 202 {object} models.JobRun
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /v1/admin/jobs/{name}/run [post]
+func TriggerJob(c *fiber.Ctx) error {
+	name := c.Params("name")
+
+	run, err := worker.DefaultScheduler.Trigger(name)
+	if err != nil {
+		switch {
+		case errors.Is(err, worker.ErrJobNotFound):
+			return c.Status(404).JSON(fiber.Map{"error": "Job not found"})
+		case errors.Is(err, worker.ErrJobRunning):
+			return c.Status(409).JSON(fiber.Map{"error": "Job is already running"})
+		}
+		fmt.Printf("Error triggering job %s: %v\n", name, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to start job"})
+	}
+
+	return c.Status(202).JSON(run)
+}
+
+// ListJobRuns returns a job's run history
+// @Summary List Job Runs
+// @Description Paginated run history (start, end, result, error) of a background job, newest first
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Job Name"
+// @Param page query int false "Page Number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/jobs/{name}/runs [get]
+func ListJobRuns(c *fiber.Ctx) error {
+	name := c.Params("name")
+	if !worker.DefaultScheduler.HasJob(name) {
+		return c.Status(404).JSON(fiber.Map{"error": "Job not found"})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	repo := repository.NewJobRepository(database.DB)
+	runs, total, err := repo.ListRuns(c.Context(), name, limit, offset)
+	if err != nil {
+		fmt.Printf("Error fetching runs of job %s: %v\n", name, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch job runs"})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": runs,
+		"meta": fiber.Map{
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetJobRun returns a single run, e.g. to poll a manually triggered job
+// @Summary Get Job Run
+// @Description Get the status and outcome of a single job run
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Run ID"
+// @Success 200 {object} models.JobRun
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/jobs/runs/{id} [get]
+func GetJobRun(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Run ID"})
+	}
+
+	repo := repository.NewJobRepository(database.DB)
+	run, err := repo.GetRun(c.Context(), id)
+	if err != nil {
+		fmt.Printf("Error fetching job run %d: %v\n", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch job run"})
+	}
+	if run == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Job run not found"})
+	}
+
+	return c.JSON(run)
+}
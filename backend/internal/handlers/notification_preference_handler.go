@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetNotificationPreferences returns the channels the student receives reminders on
+// @Summary Get Notification Preferences
+// @Description Get which channels (push, email, WhatsApp) the logged-in student receives deadline reminders on
+// @Tags Student
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.NotificationPreferences
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/notification-preferences [get]
+func GetNotificationPreferences(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+
+	repo := repository.NewReminderRepository(database.DB)
+	prefs, err := repo.GetPreferences(c.Context(), userID)
+	if err != nil {
+		fmt.Printf("Error fetching notification preferences for %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch preferences"})
+	}
+
+	return c.JSON(prefs)
+}
+
+// UpdateNotificationPreferences turns reminder channels on or off
+// @Summary Update Notification Preferences
+// @Description Enable or disable push, email and WhatsApp deadline reminders. Omitted fields are unchanged.
+// @Tags Student
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param preferences body models.UpdateNotificationPreferencesInput true "Preferences"
+// @Success 200 {object} models.NotificationPreferences
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/notification-preferences [put]
+func UpdateNotificationPreferences(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+
+	var input models.UpdateNotificationPreferencesInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+
+	repo := repository.NewReminderRepository(database.DB)
+	prefs, err := repo.GetPreferences(c.Context(), userID)
+	if err != nil {
+		fmt.Printf("Error fetching notification preferences for %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch preferences"})
+	}
+
+	if input.PushEnabled != nil {
+		prefs.PushEnabled = *input.PushEnabled
+	}
+	if input.EmailEnabled != nil {
+		prefs.EmailEnabled = *input.EmailEnabled
+	}
+	if input.WhatsAppEnabled != nil {
+		prefs.WhatsAppEnabled = *input.WhatsAppEnabled
+	}
+
+	saved, err := repo.UpsertPreferences(c.Context(), *prefs)
+	if err != nil {
+		fmt.Printf("Error saving notification preferences for %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save preferences"})
+	}
+
+	return c.JSON(saved)
+}
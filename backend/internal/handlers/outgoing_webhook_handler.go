@@ -0,0 +1,344 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/services"
+	"github.com/SysSyncer/placement-portal-kec/internal/worker"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// emitPortalEvent queues an event for every subscribed outgoing webhook and kicks off delivery (Async helper).
+// Payload shape: {"id": "evt_...", "type": "drive.published", "created_at": "...", "data": {...}}
+func emitPortalEvent(eventType string, data interface{}) {
+	eventID := services.NewWebhookEventID()
+	payload, err := json.Marshal(fiber.Map{
+		"id":         eventID,
+		"type":       eventType,
+		"created_at": time.Now().UTC().Format(time.RFC3339),
+		"data":       data,
+	})
+	if err != nil {
+		fmt.Printf("Outgoing Webhooks: Failed to encode %s: %v\n", eventType, err)
+		return
+	}
+
+	repo := repository.NewOutgoingWebhookRepository(database.DB)
+	count, err := repo.EnqueueEvent(context.Background(), eventID, eventType, payload)
+	if err != nil {
+		fmt.Printf("Outgoing Webhooks: Failed to enqueue %s: %v\n", eventType, err)
+		return
+	}
+	if count > 0 {
+		worker.DeliverDueOutgoingWebhooks()
+	}
+}
+
+// emitApplicationStatusChanged sends application.status_changed, plus student.placed for offers (Async helper)
+func emitApplicationStatusChanged(driveID, studentID int64, status, changedBy string) {
+	emitPortalEvent(models.PortalEventApplicationStatusChanged, fiber.Map{
+		"drive_id":   driveID,
+		"student_id": studentID,
+		"status":     status,
+		"changed_by": changedBy, // 'admin', 'student', 'recruiter'
+	})
+
+	if status != "placed" {
+		return
+	}
+
+	data := fiber.Map{"drive_id": driveID, "student_id": studentID}
+	if drive, err := repository.NewDriveRepository(database.DB).GetDriveByID(context.Background(), driveID); err == nil {
+		data["company_name"] = drive.CompanyName
+		data["job_role"] = drive.JobRole
+		data["ctc_display"] = drive.CtcDisplay
+	}
+	emitPortalEvent(models.PortalEventStudentPlaced, data)
+}
+
+// drivePublishedData is the public part of a drive sent with drive.published
+func drivePublishedData(d models.PlacementDrive) fiber.Map {
+	driveDate := ""
+	if d.DriveDate.Valid {
+		driveDate = d.DriveDate.Time.Format("2006-01-02")
+	}
+	return fiber.Map{
+		"drive_id":             d.ID,
+		"company_name":         d.CompanyName,
+		"job_role":             d.JobRole,
+		"location":             d.Location,
+		"drive_type":           d.DriveType,
+		"company_category":     d.CompanyCategory,
+		"ctc_display":          d.CtcDisplay,
+		"eligible_departments": d.EligibleDepartments,
+		"eligible_batches":     d.EligibleBatches,
+		"drive_date":           driveDate,
+		"deadline_date":        d.DeadlineDate,
+	}
+}
+
+func validPortalEventTypes(types []string) bool {
+	for _, t := range types {
+		found := false
+		for _, known := range models.PortalEventTypes {
+			if t == known {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ListOutgoingWebhooks lists outgoing webhook subscriptions
+// @Summary List Outgoing Webhooks
+// @Description List webhook subscriptions registered for portal events (secrets are not returned)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/outgoing-webhooks [get]
+func ListOutgoingWebhooks(c *fiber.Ctx) error {
+	repo := repository.NewOutgoingWebhookRepository(database.DB)
+	webhooks, err := repo.ListWebhooks(c.Context())
+	if err != nil {
+		fmt.Printf("Error fetching outgoing webhooks: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch webhooks"})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":        webhooks,
+		"event_types": models.PortalEventTypes,
+	})
+}
+
+// CreateOutgoingWebhook registers a new subscription
+// @Summary Create Outgoing Webhook
+// @Description Subscribe a URL to portal events. Payloads are signed with HMAC-SHA256 over "<timestamp>.<body>" (X-Webhook-Signature: sha256=...). The secret is only returned here.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param webhook body models.CreateOutgoingWebhookInput true "Subscription"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/outgoing-webhooks [post]
+func CreateOutgoingWebhook(c *fiber.Ctx) error {
+	var input models.CreateOutgoingWebhookInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+	if !validPortalEventTypes(input.EventTypes) {
+		return c.Status(400).JSON(fiber.Map{"error": "Unknown event type", "event_types": models.PortalEventTypes})
+	}
+
+	w := models.OutgoingWebhook{
+		Name:       input.Name,
+		URL:        input.URL,
+		Secret:     input.Secret,
+		EventTypes: input.EventTypes,
+		IsActive:   true,
+	}
+	if w.Secret == "" {
+		w.Secret = services.NewWebhookSecret()
+	}
+	if input.IsActive != nil {
+		w.IsActive = *input.IsActive
+	}
+
+	repo := repository.NewOutgoingWebhookRepository(database.DB)
+	created, err := repo.CreateWebhook(c.Context(), w)
+	if err != nil {
+		fmt.Printf("Error creating outgoing webhook: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create webhook"})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"webhook": created,
+		"secret":  created.Secret, // Shown once so the subscriber can verify signatures
+	})
+}
+
+// UpdateOutgoingWebhook changes a subscription's URL, events, secret or active flag
+// @Summary Update Outgoing Webhook
+// @Description Update a webhook subscription. Leave secret empty to keep the current one.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param webhook body models.CreateOutgoingWebhookInput true "Subscription"
+// @Success 200 {object} models.OutgoingWebhook
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/outgoing-webhooks/{id} [put]
+func UpdateOutgoingWebhook(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Webhook ID"})
+	}
+
+	var input models.CreateOutgoingWebhookInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+	if !validPortalEventTypes(input.EventTypes) {
+		return c.Status(400).JSON(fiber.Map{"error": "Unknown event type", "event_types": models.PortalEventTypes})
+	}
+
+	w := models.OutgoingWebhook{
+		ID:         id,
+		Name:       input.Name,
+		URL:        input.URL,
+		Secret:     input.Secret,
+		EventTypes: input.EventTypes,
+		IsActive:   true,
+	}
+	if input.IsActive != nil {
+		w.IsActive = *input.IsActive
+	}
+
+	repo := repository.NewOutgoingWebhookRepository(database.DB)
+	updated, err := repo.UpdateWebhook(c.Context(), w)
+	if err != nil {
+		fmt.Printf("Error updating outgoing webhook %d: %v\n", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update webhook"})
+	}
+	if updated == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Webhook not found"})
+	}
+
+	return c.JSON(updated)
+}
+
+// DeleteOutgoingWebhook removes a subscription and its delivery log
+// @Summary Delete Outgoing Webhook
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/outgoing-webhooks/{id} [delete]
+func DeleteOutgoingWebhook(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Webhook ID"})
+	}
+
+	repo := repository.NewOutgoingWebhookRepository(database.DB)
+	deleted, err := repo.DeleteWebhook(c.Context(), id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Delete failed", "details": err.Error()})
+	}
+	if !deleted {
+		return c.Status(404).JSON(fiber.Map{"error": "Webhook not found"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Webhook deleted"})
+}
+
+// ListOutgoingWebhookDeliveries shows the delivery log of a subscription
+// @Summary List Outgoing Webhook Deliveries
+// @Description Per-delivery log with attempts, last response code/error and status (pending, delivered, dead)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param status query string false "Status (pending, delivered, dead)"
+// @Param page query int false "Page Number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /v1/admin/outgoing-webhooks/{id}/deliveries [get]
+func ListOutgoingWebhookDeliveries(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Webhook ID"})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	repo := repository.NewOutgoingWebhookRepository(database.DB)
+	deliveries, total, err := repo.ListDeliveries(c.Context(), id, c.Query("status"), limit, offset)
+	if err != nil {
+		fmt.Printf("Error fetching webhook deliveries: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch deliveries"})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": deliveries,
+		"meta": fiber.Map{
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// RetryOutgoingWebhookDelivery re-queues a dead-lettered delivery
+// @Summary Retry Dead Webhook Delivery
+// @Description Move a dead delivery back to pending with a fresh retry budget and attempt it immediately
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/outgoing-webhooks/{id}/deliveries/{delivery_id}/retry [post]
+func RetryOutgoingWebhookDelivery(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Webhook ID"})
+	}
+	deliveryID, err := strconv.ParseInt(c.Params("delivery_id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Delivery ID"})
+	}
+
+	repo := repository.NewOutgoingWebhookRepository(database.DB)
+	ok, err := repo.RetryDeadDelivery(c.Context(), id, deliveryID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Retry failed", "details": err.Error()})
+	}
+	if !ok {
+		return c.Status(404).JSON(fiber.Map{"error": "Dead delivery not found"})
+	}
+
+	go worker.DeliverDueOutgoingWebhooks()
+
+	return c.JSON(fiber.Map{"message": "Delivery re-queued"})
+}
@@ -0,0 +1,635 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// parseSectionInput binds and validates a section body
+func parseSectionInput(c *fiber.Ctx, input interface{}) error {
+	if err := c.BodyParser(input); err != nil {
+		return errors.New("Invalid input")
+	}
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return fmt.Errorf("Validation failed: %v", err)
+	}
+	return nil
+}
+
+// parseSectionDates reads an optional YYYY-MM-DD date range; end can't be before start
+func parseSectionDates(start, end, startField, endField string) (pgtype.Date, pgtype.Date, error) {
+	var from, to pgtype.Date
+	if start != "" {
+		t, err := time.Parse("2006-01-02", start)
+		if err != nil {
+			return from, to, fmt.Errorf("Invalid %s (YYYY-MM-DD)", startField)
+		}
+		from = pgtype.Date{Time: t, Valid: true}
+	}
+	if end != "" {
+		t, err := time.Parse("2006-01-02", end)
+		if err != nil {
+			return from, to, fmt.Errorf("Invalid %s (YYYY-MM-DD)", endField)
+		}
+		to = pgtype.Date{Time: t, Valid: true}
+	}
+	if from.Valid && to.Valid && to.Time.Before(from.Time) {
+		return from, to, fmt.Errorf("%s cannot be before %s", endField, startField)
+	}
+	return from, to, nil
+}
+
+func sectionItemID(c *fiber.Ctx) (int64, error) {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, errors.New("Invalid ID")
+	}
+	return id, nil
+}
+
+// deleteSectionItem handles the DELETE endpoints, which only differ in the repo call
+func deleteSectionItem(c *fiber.Ctx, section string, del func(ctx context.Context, userID, id int64) (bool, error)) error {
+	userID := int64(c.Locals("user_id").(float64))
+	id, err := sectionItemID(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	deleted, err := del(c.Context(), userID, id)
+	if err != nil {
+		fmt.Printf("Error deleting %s %d for %d: %v\n", section, id, userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete " + section})
+	}
+	if !deleted {
+		return c.Status(404).JSON(fiber.Map{"error": "Not found"})
+	}
+	return c.JSON(fiber.Map{"message": "Deleted successfully"})
+}
+
+// ==========================================
+// SKILLS
+// ==========================================
+
+// ListStudentSkills returns the student's skills
+// @Summary List My Skills
+// @Tags Student
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.StudentSkill
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/skills [get]
+func ListStudentSkills(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	skills, err := repository.NewProfileSectionRepository(database.DB).ListSkills(c.Context(), userID)
+	if err != nil {
+		fmt.Printf("Error fetching skills for %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch skills"})
+	}
+	return c.JSON(skills)
+}
+
+// CreateStudentSkill adds a skill
+// @Summary Add Skill
+// @Tags Student
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.StudentSkillInput true "Skill"
+// @Success 201 {object} models.StudentSkill
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/skills [post]
+func CreateStudentSkill(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	var input models.StudentSkillInput
+	if err := parseSectionInput(c, &input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	skill, err := repository.NewProfileSectionRepository(database.DB).CreateSkill(c.Context(), userID, input)
+	if errors.Is(err, repository.ErrSkillExists) {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		fmt.Printf("Error adding skill for %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to add skill"})
+	}
+	return c.Status(201).JSON(skill)
+}
+
+// UpdateStudentSkill renames a skill or changes its proficiency
+// @Summary Update Skill
+// @Tags Student
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Skill ID"
+// @Param input body models.StudentSkillInput true "Skill"
+// @Success 200 {object} models.StudentSkill
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/skills/{id} [put]
+func UpdateStudentSkill(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	id, err := sectionItemID(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	var input models.StudentSkillInput
+	if err := parseSectionInput(c, &input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	skill, err := repository.NewProfileSectionRepository(database.DB).UpdateSkill(c.Context(), userID, id, input)
+	if errors.Is(err, repository.ErrSkillExists) {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		fmt.Printf("Error updating skill %d for %d: %v\n", id, userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update skill"})
+	}
+	if skill == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Skill not found"})
+	}
+	return c.JSON(skill)
+}
+
+// DeleteStudentSkill removes a skill
+// @Summary Delete Skill
+// @Tags Student
+// @Security BearerAuth
+// @Param id path int true "Skill ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/student/skills/{id} [delete]
+func DeleteStudentSkill(c *fiber.Ctx) error {
+	return deleteSectionItem(c, "skill", repository.NewProfileSectionRepository(database.DB).DeleteSkill)
+}
+
+// ==========================================
+// PROJECTS
+// ==========================================
+
+// ListStudentProjects returns the student's projects
+// @Summary List My Projects
+// @Tags Student
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.StudentProject
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/projects [get]
+func ListStudentProjects(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	projects, err := repository.NewProfileSectionRepository(database.DB).ListProjects(c.Context(), userID)
+	if err != nil {
+		fmt.Printf("Error fetching projects for %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch projects"})
+	}
+	return c.JSON(projects)
+}
+
+// CreateStudentProject adds a project
+// @Summary Add Project
+// @Tags Student
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.StudentProjectInput true "Project"
+// @Success 201 {object} models.StudentProject
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/projects [post]
+func CreateStudentProject(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	var input models.StudentProjectInput
+	if err := parseSectionInput(c, &input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	start, end, err := parseSectionDates(input.StartDate, input.EndDate, "start_date", "end_date")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	project, err := repository.NewProfileSectionRepository(database.DB).CreateProject(c.Context(), userID, input, start, end)
+	if err != nil {
+		fmt.Printf("Error adding project for %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to add project"})
+	}
+	return c.Status(201).JSON(project)
+}
+
+// UpdateStudentProject edits a project
+// @Summary Update Project
+// @Tags Student
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Param input body models.StudentProjectInput true "Project"
+// @Success 200 {object} models.StudentProject
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/projects/{id} [put]
+func UpdateStudentProject(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	id, err := sectionItemID(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	var input models.StudentProjectInput
+	if err := parseSectionInput(c, &input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	start, end, err := parseSectionDates(input.StartDate, input.EndDate, "start_date", "end_date")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	project, err := repository.NewProfileSectionRepository(database.DB).UpdateProject(c.Context(), userID, id, input, start, end)
+	if err != nil {
+		fmt.Printf("Error updating project %d for %d: %v\n", id, userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update project"})
+	}
+	if project == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Project not found"})
+	}
+	return c.JSON(project)
+}
+
+// DeleteStudentProject removes a project
+// @Summary Delete Project
+// @Tags Student
+// @Security BearerAuth
+// @Param id path int true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/student/projects/{id} [delete]
+func DeleteStudentProject(c *fiber.Ctx) error {
+	return deleteSectionItem(c, "project", repository.NewProfileSectionRepository(database.DB).DeleteProject)
+}
+
+// ==========================================
+// INTERNSHIPS
+// ==========================================
+
+// ListStudentInternships returns the student's internships
+// @Summary List My Internships
+// @Tags Student
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.StudentInternship
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/internships [get]
+func ListStudentInternships(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	internships, err := repository.NewProfileSectionRepository(database.DB).ListInternships(c.Context(), userID)
+	if err != nil {
+		fmt.Printf("Error fetching internships for %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch internships"})
+	}
+	return c.JSON(internships)
+}
+
+// CreateStudentInternship adds an internship
+// @Summary Add Internship
+// @Description Leave end_date empty while the internship is ongoing
+// @Tags Student
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.StudentInternshipInput true "Internship"
+// @Success 201 {object} models.StudentInternship
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/internships [post]
+func CreateStudentInternship(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	var input models.StudentInternshipInput
+	if err := parseSectionInput(c, &input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	start, end, err := parseSectionDates(input.StartDate, input.EndDate, "start_date", "end_date")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	internship, err := repository.NewProfileSectionRepository(database.DB).CreateInternship(c.Context(), userID, input, start, end)
+	if err != nil {
+		fmt.Printf("Error adding internship for %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to add internship"})
+	}
+	return c.Status(201).JSON(internship)
+}
+
+// UpdateStudentInternship edits an internship
+// @Summary Update Internship
+// @Tags Student
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Internship ID"
+// @Param input body models.StudentInternshipInput true "Internship"
+// @Success 200 {object} models.StudentInternship
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/internships/{id} [put]
+func UpdateStudentInternship(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	id, err := sectionItemID(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	var input models.StudentInternshipInput
+	if err := parseSectionInput(c, &input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	start, end, err := parseSectionDates(input.StartDate, input.EndDate, "start_date", "end_date")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	internship, err := repository.NewProfileSectionRepository(database.DB).UpdateInternship(c.Context(), userID, id, input, start, end)
+	if err != nil {
+		fmt.Printf("Error updating internship %d for %d: %v\n", id, userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update internship"})
+	}
+	if internship == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Internship not found"})
+	}
+	return c.JSON(internship)
+}
+
+// DeleteStudentInternship removes an internship
+// @Summary Delete Internship
+// @Tags Student
+// @Security BearerAuth
+// @Param id path int true "Internship ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/student/internships/{id} [delete]
+func DeleteStudentInternship(c *fiber.Ctx) error {
+	return deleteSectionItem(c, "internship", repository.NewProfileSectionRepository(database.DB).DeleteInternship)
+}
+
+// ==========================================
+// CERTIFICATIONS
+// ==========================================
+
+// ListStudentCertifications returns the student's certifications
+// @Summary List My Certifications
+// @Tags Student
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.StudentCertification
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/certifications [get]
+func ListStudentCertifications(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	certs, err := repository.NewProfileSectionRepository(database.DB).ListCertifications(c.Context(), userID)
+	if err != nil {
+		fmt.Printf("Error fetching certifications for %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch certifications"})
+	}
+	return c.JSON(certs)
+}
+
+// CreateStudentCertification adds a certification. Upload its proof with the proof endpoint.
+// @Summary Add Certification
+// @Tags Student
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.StudentCertificationInput true "Certification"
+// @Success 201 {object} models.StudentCertification
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/certifications [post]
+func CreateStudentCertification(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	var input models.StudentCertificationInput
+	if err := parseSectionInput(c, &input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	issued, expires, err := parseSectionDates(input.IssueDate, input.ExpiryDate, "issue_date", "expiry_date")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	cert, err := repository.NewProfileSectionRepository(database.DB).CreateCertification(c.Context(), userID, input, issued, expires)
+	if err != nil {
+		fmt.Printf("Error adding certification for %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to add certification"})
+	}
+	return c.Status(201).JSON(cert)
+}
+
+// UpdateStudentCertification edits a certification; the uploaded proof is kept
+// @Summary Update Certification
+// @Tags Student
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Certification ID"
+// @Param input body models.StudentCertificationInput true "Certification"
+// @Success 200 {object} models.StudentCertification
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/certifications/{id} [put]
+func UpdateStudentCertification(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	id, err := sectionItemID(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	var input models.StudentCertificationInput
+	if err := parseSectionInput(c, &input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	issued, expires, err := parseSectionDates(input.IssueDate, input.ExpiryDate, "issue_date", "expiry_date")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	cert, err := repository.NewProfileSectionRepository(database.DB).UpdateCertification(c.Context(), userID, id, input, issued, expires)
+	if err != nil {
+		fmt.Printf("Error updating certification %d for %d: %v\n", id, userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update certification"})
+	}
+	if cert == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Certification not found"})
+	}
+	return c.JSON(cert)
+}
+
+// DeleteStudentCertification removes a certification and its proof file
+// @Summary Delete Certification
+// @Tags Student
+// @Security BearerAuth
+// @Param id path int true "Certification ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/student/certifications/{id} [delete]
+func DeleteStudentCertification(c *fiber.Ctx) error {
+	repo := repository.NewProfileSectionRepository(database.DB)
+	return deleteSectionItem(c, "certification", func(ctx context.Context, userID, id int64) (bool, error) {
+		cert, err := repo.GetCertification(ctx, userID, id)
+		if err != nil || cert == nil {
+			return false, err
+		}
+		deleted, err := repo.DeleteCertification(ctx, userID, id)
+		if deleted && cert.ProofURL != "" {
+			if key := utils.ExtractPathFromURL(cert.ProofURL); key != "" {
+				if err := utils.DeleteFromS3(key); err != nil {
+					fmt.Printf("Warning: failed to delete certification proof %s: %v\n", key, err)
+				}
+			}
+		}
+		return deleted, err
+	})
+}
+
+// UploadCertificationProof uploads the certificate file for a certification
+// @Summary Upload Certification Proof
+// @Description Upload the certificate (PDF or image) to S3. Replaces any earlier proof.
+// @Tags Student
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Certification ID"
+// @Param file formData file true "Certificate file"
+// @Success 200 {object} models.StudentCertification
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/certifications/{id}/proof [post]
+func UploadCertificationProof(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	id, err := sectionItemID(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "File is required"})
+	}
+
+	repo := repository.NewProfileSectionRepository(database.DB)
+	cert, err := repo.GetCertification(c.Context(), userID, id)
+	if err != nil {
+		fmt.Printf("Error fetching certification %d for %d: %v\n", id, userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch certification"})
+	}
+	if cert == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Certification not found"})
+	}
+
+	registerNumber, err := repository.NewUserRepository(database.DB).GetRegisterNumber(c.Context(), userID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Profile incomplete: Register Number not found"})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to open file", "details": err.Error()})
+	}
+	defer file.Close()
+
+	path := fmt.Sprintf("students/%s/certifications/%d", registerNumber, id)
+	url, err := utils.UploadToS3(file, fileHeader, path)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Upload failed", "details": err.Error()})
+	}
+
+	if err := repo.SetCertificationProof(c.Context(), userID, id, url); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Database update failed"})
+	}
+	cert.ProofURL = url
+	return c.JSON(cert)
+}
+
+// certificationProofURL presigns the proof of one of a student's certifications
+func certificationProofURL(c *fiber.Ctx, studentID, certID int64) error {
+	cert, err := repository.NewProfileSectionRepository(database.DB).GetCertification(c.Context(), studentID, certID)
+	if err != nil {
+		fmt.Printf("Error fetching certification %d for %d: %v\n", certID, studentID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch certification"})
+	}
+	if cert == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Certification not found"})
+	}
+	if cert.ProofURL == "" {
+		return c.Status(404).JSON(fiber.Map{"error": "Proof not uploaded yet"})
+	}
+
+	objectKey := utils.ExtractPathFromURL(cert.ProofURL)
+	if objectKey == "" {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to parse document URL"})
+	}
+	presignedURL, err := utils.GetPresignedURL(objectKey, 10080)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to generate document URL", "details": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"url":        presignedURL,
+		"expires_in": "7 days",
+	})
+}
+
+// GetCertificationProofURL returns a presigned URL for the student's own proof
+// @Summary Get Certification Proof URL
+// @Tags Student
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Certification ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/student/certifications/{id}/proof [get]
+func GetCertificationProofURL(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+	id, err := sectionItemID(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	return certificationProofURL(c, userID, id)
+}
+
+// GetStudentCertificationProofURL lets admins open any student's certification proof
+// @Summary Get Student Certification Proof URL (Admin)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param student_id path int true "Student User ID"
+// @Param id path int true "Certification ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/students/{student_id}/certifications/{id}/proof [get]
+func GetStudentCertificationProofURL(c *fiber.Ctx) error {
+	studentID, err := strconv.ParseInt(c.Params("student_id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid student ID"})
+	}
+	id, err := sectionItemID(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	return certificationProofURL(c, studentID, id)
+}
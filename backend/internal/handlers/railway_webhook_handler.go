@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"encoding/json"
 	"log"
 
 	"github.com/gofiber/fiber/v2"
@@ -53,35 +52,26 @@ func HandleRailwayWebhook(c *fiber.Ctx) error {
 		})
 	}
 
-	// 3. Log the webhook event for monitoring
-	payloadJSON, _ := json.MarshalIndent(payload, "", "  ")
-	log.Printf("Railway Webhook Received:\n%s", string(payloadJSON))
-
-	// 4. Process webhook event based on type
-	switch payload.Type {
-	case "deployment.success":
-		log.Printf("✅ Deployment successful for project: %s", payload.ProjectID)
-		// Add custom logic here if needed (e.g., send notifications, update database, etc.)
-
-	case "deployment.failed":
-		log.Printf("❌ Deployment failed for project: %s", payload.ProjectID)
-		// Add custom logic here (e.g., send alerts, log errors, etc.)
-
-	case "deployment.started":
-		log.Printf("🚀 Deployment started for project: %s", payload.ProjectID)
-		// Add custom logic here if needed
-
-	default:
-		log.Printf("⚠️  Unknown webhook type: %s", payload.Type)
+	// 3. Store the event and run its registered handlers (see webhook_event_handler.go)
+	event, err := storeAndDispatchWebhook(c, "railway", payload.Type)
+	if err != nil {
+		log.Printf("Railway Webhook: Failed to store event - %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to store webhook event",
+		})
 	}
 
-	// 5. Return HTTP 200 to acknowledge receipt
-	// Railway expects a 200 response to confirm successful webhook delivery
+	// 4. Return HTTP 200 to acknowledge receipt
+	// Railway expects a 200 response to confirm successful webhook delivery.
+	// Handler failures are recorded on the event and can be replayed by an admin.
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"success":   true,
 		"message":   "Webhook received successfully",
 		"type":      payload.Type,
 		"timestamp": payload.Timestamp,
+		"event_id":  event.ID,
+		"status":    event.Status,
 	})
 }
 
@@ -108,16 +98,30 @@ func HandleGenericWebhook(c *fiber.Ctx) error {
 		})
 	}
 
-	// 3. Log the webhook payload
-	payloadJSON, _ := json.MarshalIndent(payload, "", "  ")
-	log.Printf("Generic Webhook Received:\n%s", string(payloadJSON))
+	// 3. Store the event and run its registered handlers
+	// The event type is taken from "event" or "type", whichever the sender uses
+	eventType, _ := payload["event"].(string)
+	if eventType == "" {
+		eventType, _ = payload["type"].(string)
+	}
+	if eventType == "" {
+		eventType = "unknown"
+	}
 
-	// 4. Process the webhook (add your custom logic here)
-	// Example: Store in database, trigger notifications, update cache, etc.
+	event, err := storeAndDispatchWebhook(c, "generic", eventType)
+	if err != nil {
+		log.Printf("Generic Webhook: Failed to store event - %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"success": false,
+			"error":   "Failed to store webhook event",
+		})
+	}
 
-	// 5. Return HTTP 200
+	// 4. Return HTTP 200
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"message": "Webhook received and processed successfully",
+		"success":  true,
+		"message":  "Webhook received and processed successfully",
+		"event_id": event.ID,
+		"status":   event.Status,
 	})
 }
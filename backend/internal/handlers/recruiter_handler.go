@@ -0,0 +1,616 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// resumeURLExpiryMinutes is how long recruiter resume links stay valid
+const resumeURLExpiryMinutes = 60
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// recruiterDrive loads the drive in the URL if it belongs to the recruiter's company.
+// Drafts and other companies' drives are reported as not found.
+func recruiterDrive(c *fiber.Ctx) *models.PlacementDrive {
+	driveID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return nil
+	}
+	drive, err := repository.NewDriveRepository(database.DB).GetDriveByID(c.Context(), driveID)
+	if err != nil {
+		return nil
+	}
+	companyID := c.Locals("company_id").(int64)
+	if drive.CompanyID == nil || *drive.CompanyID != companyID || drive.Status == "draft" {
+		return nil
+	}
+	return drive
+}
+
+// recruiterApplicants returns the students who applied to a drive (not merely eligible or opted out)
+func recruiterApplicants(c *fiber.Ctx, driveID int64) ([]models.DriveApplicant, error) {
+	all, err := repository.NewDriveRepository(database.DB).GetDriveApplicants(c.Context(), driveID)
+	if err != nil {
+		return nil, err
+	}
+	applicants := make([]models.DriveApplicant, 0, len(all))
+	for _, a := range all {
+		if a.Status != "eligible" && a.Status != "opted_out" {
+			applicants = append(applicants, a)
+		}
+	}
+	return applicants, nil
+}
+
+// resumeDownloadURL swaps a stored resume URL for a short-lived presigned one
+func resumeDownloadURL(storedURL string) (string, error) {
+	if storedURL == "" {
+		return "", nil
+	}
+	key := utils.ExtractPathFromURL(storedURL)
+	if key == "" {
+		return "", fmt.Errorf("unrecognised storage URL")
+	}
+	return utils.GetPresignedURL(key, resumeURLExpiryMinutes)
+}
+
+// RecruiterLogin handles company HR authentication
+// @Summary Recruiter Login
+// @Description Authenticate a recruiter (company HR) account
+// @Tags Recruiter Auth
+// @Accept json
+// @Produce json
+// @Param credentials body models.LoginInput true "Login Credentials"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /v1/recruiter/auth/login [post]
+func RecruiterLogin(c *fiber.Ctx) error {
+	var input models.LoginInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+
+	user, err := authenticateUser(c, input.Email, input.Password)
+	if err != nil {
+		if err.Error() == "your account has been blocked by Admin" {
+			return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(401).JSON(fiber.Map{"error": err.Error()})
+	}
+	if user.Role != "recruiter" {
+		return c.Status(403).JSON(fiber.Map{"error": "This portal is for recruiters only"})
+	}
+
+	recruiter, err := repository.NewRecruiterRepository(database.DB).GetRecruiter(c.Context(), user.ID)
+	if err != nil || recruiter == nil {
+		return c.Status(403).JSON(fiber.Map{"error": "Recruiter account is not linked to a company"})
+	}
+
+	token, err := utils.GenerateToken(user.ID, user.Role)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Could not generate token"})
+	}
+
+	recordAudit(c, models.AuditLog{
+		ActorID:   user.ID,
+		ActorRole: user.Role,
+		CompanyID: &recruiter.CompanyID,
+		Action:    models.AuditRecruiterLogin,
+	})
+
+	return c.JSON(fiber.Map{
+		"message":   "Login successful",
+		"token":     token,
+		"role":      user.Role,
+		"email":     user.Email,
+		"recruiter": recruiter,
+	})
+}
+
+// GetRecruiterProfile returns the logged-in recruiter and their company
+// @Summary Recruiter Profile
+// @Tags Recruiter
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.Recruiter
+// @Router /v1/recruiter/me [get]
+func GetRecruiterProfile(c *fiber.Ctx) error {
+	return c.JSON(c.Locals("recruiter").(*models.Recruiter))
+}
+
+// ListRecruiterDrives returns the company's drives
+// @Summary List Recruiter Drives
+// @Description Drives of the recruiter's company (drafts excluded)
+// @Tags Recruiter
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.PlacementDrive
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/recruiter/drives [get]
+func ListRecruiterDrives(c *fiber.Ctx) error {
+	companyID := c.Locals("company_id").(int64)
+
+	drives, err := repository.NewDriveRepository(database.DB).GetDrives(c.Context(), map[string]interface{}{"company_id": companyID})
+	if err != nil {
+		fmt.Printf("Error fetching drives for company %d: %v\n", companyID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch drives"})
+	}
+
+	visible := []models.PlacementDrive{}
+	for _, d := range drives {
+		if d.Status != "draft" {
+			visible = append(visible, d)
+		}
+	}
+	return c.JSON(visible)
+}
+
+// GetRecruiterDriveApplicants returns a drive's applicants, as JSON or a CSV download
+// @Summary Recruiter Drive Applicants
+// @Description Students who applied to one of the company's drives. resume_url is a presigned link valid for 60 minutes.
+// @Tags Recruiter
+// @Produce json,text/csv
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Param format query string false "csv to download a spreadsheet"
+// @Success 200 {array} models.DriveApplicant
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/recruiter/drives/{id}/applicants [get]
+func GetRecruiterDriveApplicants(c *fiber.Ctx) error {
+	drive := recruiterDrive(c)
+	if drive == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Drive not found"})
+	}
+
+	applicants, err := recruiterApplicants(c, drive.ID)
+	if err != nil {
+		fmt.Printf("Error fetching applicants for drive %d: %v\n", drive.ID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch applicants"})
+	}
+	for i := range applicants {
+		url, err := resumeDownloadURL(applicants[i].ResumeURL)
+		if err != nil {
+			fmt.Printf("Resume: Failed to presign for student %d: %v\n", applicants[i].StudentID, err)
+		}
+		applicants[i].ResumeURL = url
+	}
+
+	driveID := drive.ID
+	if c.Query("format") != "csv" {
+		recordAudit(c, models.AuditLog{
+			Action:  models.AuditRecruiterApplicantsView,
+			DriveID: &driveID,
+			Details: map[string]interface{}{"count": len(applicants)},
+		})
+		return c.JSON(applicants)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"Register Number", "Name", "Email", "Department", "CGPA", "Status", "Applied At", "Resume"})
+	for _, a := range applicants {
+		w.Write([]string{
+			a.RegisterNumber, a.FullName, a.Email, a.Department,
+			strconv.FormatFloat(a.Cgpa, 'f', 2, 64), a.Status, a.AppliedAt, a.ResumeURL,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to build CSV"})
+	}
+
+	recordAudit(c, models.AuditLog{
+		Action:  models.AuditRecruiterApplicantsCSV,
+		DriveID: &driveID,
+		Details: map[string]interface{}{"count": len(applicants)},
+	})
+
+	filename := unsafeFileChars.ReplaceAllString(fmt.Sprintf("%s_%s_applicants.csv", drive.CompanyName, drive.JobRole), "_")
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	return c.Send(buf.Bytes())
+}
+
+// GetRecruiterApplicantResume returns a presigned link to one applicant's resume
+// @Summary Recruiter Applicant Resume
+// @Tags Recruiter
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Param student_id path int true "Student ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/recruiter/drives/{id}/applicants/{student_id}/resume [get]
+func GetRecruiterApplicantResume(c *fiber.Ctx) error {
+	drive := recruiterDrive(c)
+	if drive == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Drive not found"})
+	}
+	studentID, err := strconv.ParseInt(c.Params("student_id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid student ID"})
+	}
+
+	applicants, err := recruiterApplicants(c, drive.ID)
+	if err != nil {
+		fmt.Printf("Error fetching applicants for drive %d: %v\n", drive.ID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch applicants"})
+	}
+	var applicant *models.DriveApplicant
+	for i := range applicants {
+		if applicants[i].StudentID == studentID {
+			applicant = &applicants[i]
+			break
+		}
+	}
+	if applicant == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Student has not applied to this drive"})
+	}
+	if applicant.ResumeURL == "" {
+		return c.Status(404).JSON(fiber.Map{"error": "Resume not uploaded yet"})
+	}
+
+	url, err := resumeDownloadURL(applicant.ResumeURL)
+	if err != nil {
+		fmt.Printf("Resume: Failed to presign for student %d: %v\n", studentID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to generate resume link"})
+	}
+
+	driveID := drive.ID
+	recordAudit(c, models.AuditLog{
+		Action:    models.AuditRecruiterResumeView,
+		DriveID:   &driveID,
+		StudentID: &studentID,
+	})
+
+	return c.JSON(fiber.Map{
+		"url":                url,
+		"expires_in_minutes": resumeURLExpiryMinutes,
+		"register_number":    applicant.RegisterNumber,
+	})
+}
+
+// UploadRecruiterResults records a round's outcome for a drive's applicants
+// @Summary Upload Round Results
+// @Description Mark applicants shortlisted, rejected or placed (with package). Students are matched by student_id or register_number; rows that don't match an applicant are reported and skipped.
+// @Tags Recruiter
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Param results body models.RecruiterResultsInput true "Results"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/recruiter/drives/{id}/results [post]
+func UploadRecruiterResults(c *fiber.Ctx) error {
+	drive := recruiterDrive(c)
+	if drive == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Drive not found"})
+	}
+
+	var input models.RecruiterResultsInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	applicants, err := recruiterApplicants(c, drive.ID)
+	if err != nil {
+		fmt.Printf("Error fetching applicants for drive %d: %v\n", drive.ID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch applicants"})
+	}
+	byID := make(map[int64]*models.DriveApplicant, len(applicants))
+	byRegNo := make(map[string]*models.DriveApplicant, len(applicants))
+	for i := range applicants {
+		byID[applicants[i].StudentID] = &applicants[i]
+		byRegNo[strings.ToUpper(applicants[i].RegisterNumber)] = &applicants[i]
+	}
+
+	repo := repository.NewDriveRepository(database.DB)
+	appRepo := repository.NewApplicationRepository(database.DB)
+	actor := statusActor(c)
+	var rowErrors []fiber.Map
+	applied := []fiber.Map{}
+	for i, r := range input.Results {
+		a := byID[r.StudentID]
+		if a == nil {
+			a = byRegNo[strings.ToUpper(strings.TrimSpace(r.RegisterNumber))]
+		}
+		if a == nil {
+			rowErrors = append(rowErrors, fiber.Map{"row": i + 1, "student_id": r.StudentID, "register_number": r.RegisterNumber, "error": "Not an applicant of this drive"})
+			continue
+		}
+
+		_, err := appRepo.TransitionStatus(c.Context(), drive.ID, a.StudentID, models.ApplicationStatus(r.Status), actor, input.RoundName, false)
+		if errors.Is(err, repository.ErrInvalidTransition) {
+			rowErrors = append(rowErrors, fiber.Map{"row": i + 1, "register_number": a.RegisterNumber, "error": err.Error()})
+			continue
+		}
+		if err != nil {
+			fmt.Printf("Error updating status for student %d drive %d: %v\n", a.StudentID, drive.ID, err)
+			rowErrors = append(rowErrors, fiber.Map{"row": i + 1, "register_number": a.RegisterNumber, "error": "Failed to update status"})
+			continue
+		}
+		if r.Status == "placed" && r.PackageOffered > 0 {
+			if err := repo.SetApplicationOffer(c.Context(), drive.ID, a.StudentID, r.PackageOffered, ""); err != nil {
+				fmt.Printf("Error saving offer for student %d drive %d: %v\n", a.StudentID, drive.ID, err)
+			}
+		}
+
+		if a.Status != r.Status {
+			go notifyApplicationStatusWhatsApp(drive.ID, a.StudentID, r.Status)
+			go notifyApplicationStatusEmail(drive.ID, a.StudentID, r.Status)
+			go emitApplicationStatusChanged(drive.ID, a.StudentID, r.Status, "recruiter")
+		}
+		applied = append(applied, fiber.Map{"student_id": a.StudentID, "register_number": a.RegisterNumber, "from": a.Status, "to": r.Status})
+		a.Status = r.Status
+	}
+
+	driveID := drive.ID
+	recordAudit(c, models.AuditLog{
+		Action:  models.AuditRecruiterResultsUpload,
+		DriveID: &driveID,
+		Details: map[string]interface{}{
+			"round_name": input.RoundName,
+			"updated":    applied,
+			"failed":     len(rowErrors),
+		},
+	})
+
+	return c.JSON(fiber.Map{
+		"message": fmt.Sprintf("Updated %d of %d students", len(applied), len(input.Results)),
+		"updated": len(applied),
+		"errors":  rowErrors,
+	})
+}
+
+// UploadRecruiterOffer attaches an offer letter to an applicant and marks them placed
+// @Summary Upload Offer Letter
+// @Tags Recruiter
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Param student_id path int true "Student ID"
+// @Param offer_letter formData file true "Offer letter (PDF)"
+// @Param package_offered formData int false "Annual CTC in rupees"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/recruiter/drives/{id}/offers/{student_id} [post]
+func UploadRecruiterOffer(c *fiber.Ctx) error {
+	drive := recruiterDrive(c)
+	if drive == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Drive not found"})
+	}
+	studentID, err := strconv.ParseInt(c.Params("student_id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid student ID"})
+	}
+
+	var packageOffered int64
+	if v := c.FormValue("package_offered"); v != "" {
+		if packageOffered, err = strconv.ParseInt(v, 10, 64); err != nil || packageOffered < 0 {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid package_offered"})
+		}
+	}
+
+	fileHeader, err := c.FormFile("offer_letter")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "offer_letter file is required"})
+	}
+	if !strings.EqualFold(filepath.Ext(fileHeader.Filename), ".pdf") {
+		return c.Status(400).JSON(fiber.Map{"error": "Offer letter must be a PDF"})
+	}
+
+	applicants, err := recruiterApplicants(c, drive.ID)
+	if err != nil {
+		fmt.Printf("Error fetching applicants for drive %d: %v\n", drive.ID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch applicants"})
+	}
+	var applicant *models.DriveApplicant
+	for i := range applicants {
+		if applicants[i].StudentID == studentID {
+			applicant = &applicants[i]
+			break
+		}
+	}
+	if applicant == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Student has not applied to this drive"})
+	}
+	if !models.ApplicationStatus(applicant.Status).CanMoveTo(models.StatusPlaced) {
+		return c.Status(409).JSON(fiber.Map{"error": fmt.Sprintf("Can't record an offer for a student whose status is %s", applicant.Status)})
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to process file"})
+	}
+	defer file.Close()
+
+	path := fmt.Sprintf("offers/%d/%s_offer_letter.pdf", drive.ID, unsafeFileChars.ReplaceAllString(applicant.RegisterNumber, "_"))
+	url, err := utils.UploadToS3(file, fileHeader, path)
+	if err != nil {
+		fmt.Printf("Error uploading offer letter for student %d: %v\n", studentID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to upload offer letter"})
+	}
+
+	repo := repository.NewDriveRepository(database.DB)
+	if applicant.Status != "placed" {
+		_, err := repository.NewApplicationRepository(database.DB).TransitionStatus(c.Context(), drive.ID, studentID, models.StatusPlaced, statusActor(c), "Offer letter uploaded", false)
+		if errors.Is(err, repository.ErrInvalidTransition) {
+			return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to update status"})
+		}
+		go notifyApplicationStatusWhatsApp(drive.ID, studentID, "placed")
+		go notifyApplicationStatusEmail(drive.ID, studentID, "placed")
+		go emitApplicationStatusChanged(drive.ID, studentID, "placed", "recruiter")
+	}
+	if err := repo.SetApplicationOffer(c.Context(), drive.ID, studentID, packageOffered, url); err != nil {
+		fmt.Printf("Error saving offer for student %d drive %d: %v\n", studentID, drive.ID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save offer"})
+	}
+
+	driveID := drive.ID
+	recordAudit(c, models.AuditLog{
+		Action:    models.AuditRecruiterOfferUpload,
+		DriveID:   &driveID,
+		StudentID: &studentID,
+		Details: map[string]interface{}{
+			"previous_status": applicant.Status,
+			"package_offered": packageOffered,
+			"file":            fileHeader.Filename,
+		},
+	})
+
+	return c.JSON(fiber.Map{"message": "Offer recorded", "offer_letter_url": url})
+}
+
+// CreateRecruiter gives a company HR a recruiter login
+// @Summary Create Recruiter Account
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Company ID"
+// @Param recruiter body models.CreateRecruiterInput true "Recruiter"
+// @Success 201 {object} models.Recruiter
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /v1/admin/companies/{id}/recruiters [post]
+func CreateRecruiter(c *fiber.Ctx) error {
+	companyID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Company ID"})
+	}
+
+	var input models.CreateRecruiterInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	company, err := repository.NewCompanyRepository(database.DB).GetCompany(c.Context(), companyID)
+	if err != nil {
+		return companyError(c, err, "Failed to fetch company")
+	}
+	if company == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Company not found"})
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Could not process password"})
+	}
+
+	adminID := int64(c.Locals("user_id").(float64))
+	repo := repository.NewRecruiterRepository(database.DB)
+	id, err := repo.CreateRecruiter(c.Context(), companyID, adminID, input, string(hash))
+	if errors.Is(err, repository.ErrEmailTaken) {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		fmt.Printf("Error creating recruiter for company %d: %v\n", companyID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to create recruiter"})
+	}
+
+	recordAudit(c, models.AuditLog{
+		CompanyID: &companyID,
+		Action:    models.AuditRecruiterCreated,
+		Details:   map[string]interface{}{"recruiter_id": id, "email": input.Email},
+	})
+
+	recruiter, err := repo.GetRecruiter(c.Context(), id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch recruiter"})
+	}
+	return c.Status(201).JSON(recruiter)
+}
+
+// ListCompanyRecruiters lists a company's recruiter accounts
+// @Summary List Company Recruiters
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Company ID"
+// @Success 200 {array} models.Recruiter
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/companies/{id}/recruiters [get]
+func ListCompanyRecruiters(c *fiber.Ctx) error {
+	companyID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Company ID"})
+	}
+
+	recruiters, err := repository.NewRecruiterRepository(database.DB).ListRecruiters(c.Context(), companyID)
+	if err != nil {
+		fmt.Printf("Error fetching recruiters for company %d: %v\n", companyID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch recruiters"})
+	}
+	return c.JSON(recruiters)
+}
+
+// DeleteRecruiter removes a recruiter login
+// @Summary Delete Recruiter Account
+// @Description Delete the login. Their audit trail is kept. Use PUT /admin/users/{id}/block to suspend instead.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Recruiter user ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/recruiters/{id} [delete]
+func DeleteRecruiter(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid recruiter ID"})
+	}
+
+	repo := repository.NewRecruiterRepository(database.DB)
+	recruiter, err := repo.GetRecruiter(c.Context(), id)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch recruiter"})
+	}
+	if recruiter == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Recruiter not found"})
+	}
+
+	if _, err := repo.DeleteRecruiter(c.Context(), id); err != nil {
+		fmt.Printf("Error deleting recruiter %d: %v\n", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete recruiter"})
+	}
+
+	recordAudit(c, models.AuditLog{
+		CompanyID: &recruiter.CompanyID,
+		Action:    models.AuditRecruiterDeleted,
+		Details:   map[string]interface{}{"recruiter_id": id, "email": recruiter.Email},
+	})
+
+	return c.JSON(fiber.Map{"message": "Recruiter deleted"})
+}
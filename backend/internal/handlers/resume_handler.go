@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/services"
+	"github.com/SysSyncer/placement-portal-kec/internal/utils"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+const maxResumeBytes = 10 << 20
+
+// parseStudentResume downloads the student's uploaded resume and builds the suggested profile diff.
+// Returns a fiber error carrying the HTTP status for anything the student can fix.
+func parseStudentResume(ctx context.Context, userID int64) (*models.UpdateProfileInput, *models.ResumeSuggestions, error) {
+	profile, err := repository.NewStudentRepository(database.DB).GetProfileInput(ctx, userID)
+	if err != nil {
+		fmt.Printf("Resume Error: Failed to load profile for %d: %v\n", userID, err)
+		return nil, nil, fiber.NewError(500, "Failed to fetch profile")
+	}
+	if profile == nil {
+		return nil, nil, fiber.NewError(404, "Profile not found")
+	}
+	if profile.ResumeURL == "" {
+		return nil, nil, fiber.NewError(404, "Upload a resume first")
+	}
+
+	key := utils.ExtractPathFromURL(profile.ResumeURL)
+	if key == "" {
+		return nil, nil, fiber.NewError(500, "Invalid resume location")
+	}
+	data, _, err := utils.DownloadFromS3(key, maxResumeBytes)
+	if err != nil {
+		fmt.Printf("Resume Error: Failed to download %s: %v\n", key, err)
+		return nil, nil, fiber.NewError(502, "Could not read the uploaded resume")
+	}
+
+	text, err := utils.ExtractResumeText(data)
+	if errors.Is(err, utils.ErrUnsupportedResume) || errors.Is(err, utils.ErrEncryptedPDF) {
+		return nil, nil, fiber.NewError(422, err.Error())
+	}
+	if err != nil {
+		fmt.Printf("Resume Error: Failed to extract text from %s: %v\n", key, err)
+		return nil, nil, fiber.NewError(422, "Could not read text from the resume")
+	}
+
+	parsed := services.ParseResume(text)
+	return profile, &models.ResumeSuggestions{
+		ResumeURL:   profile.ResumeURL,
+		Parsed:      parsed,
+		Suggestions: resumeSuggestions(parsed, profile),
+	}, nil
+}
+
+func resumeError(c *fiber.Ctx, err error) error {
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return c.Status(fe.Code).JSON(fiber.Map{"error": fe.Message})
+	}
+	return c.Status(500).JSON(fiber.Map{"error": "Failed to parse resume"})
+}
+
+// resumeSuggestions compares what the resume says with the profile, keeping only real changes
+func resumeSuggestions(parsed models.ParsedResume, p *models.UpdateProfileInput) []models.ProfileSuggestion {
+	out := []models.ProfileSuggestion{}
+	suggest := func(field string, current, suggested interface{}) {
+		switch v := suggested.(type) {
+		case string:
+			if v == "" || strings.EqualFold(v, current.(string)) {
+				return
+			}
+		case float64:
+			if v == 0 || v == current.(float64) {
+				return
+			}
+		case int:
+			if v == 0 || v == current.(int) {
+				return
+			}
+		}
+		out = append(out, models.ProfileSuggestion{Field: field, Current: current, Suggested: suggested})
+	}
+
+	if parsed.Phone != "" && !strings.HasSuffix(digitsOnly(p.MobileNumber), parsed.Phone) {
+		suggest("mobile_number", p.MobileNumber, parsed.Phone)
+	}
+
+	for key, link := range parsed.Links {
+		current := p.SocialLinks[key]
+		if normalizeLink(current) != normalizeLink(link) {
+			suggest("social_links."+key, current, link)
+		}
+	}
+
+	if len(parsed.Languages) > 0 {
+		merged := append([]string{}, p.LanguageSkills...)
+		added := false
+		for _, lang := range parsed.Languages {
+			if !containsFold(merged, lang) {
+				merged = append(merged, lang)
+				added = true
+			}
+		}
+		if added {
+			out = append(out, models.ProfileSuggestion{Field: "language_skills", Current: p.LanguageSkills, Suggested: merged})
+		}
+	}
+
+	hasDegree := false
+	for _, e := range parsed.Education {
+		switch e.Level {
+		case "pg":
+			hasDegree = true
+			suggest("pg_cgpa", p.PgCgpa, e.CGPA)
+		case "ug":
+			hasDegree = true
+			suggest("ug_cgpa", p.UgCgpa, e.CGPA)
+		case "diploma":
+			suggest("diploma_mark", p.DiplomaMark, e.Percentage)
+			suggest("diploma_year_pass", p.DiplomaYearPass, e.Year)
+			suggest("diploma_institution", p.DiplomaInstitution, e.Institution)
+		case "twelfth":
+			suggest("twelfth_mark", p.TwelfthMark, e.Percentage)
+			suggest("twelfth_board", p.TwelfthBoard, e.Board)
+			suggest("twelfth_year_pass", p.TwelfthYearPass, e.Year)
+			suggest("twelfth_institution", p.TwelfthInstitution, e.Institution)
+		case "tenth":
+			suggest("tenth_mark", p.TenthMark, e.Percentage)
+			suggest("tenth_board", p.TenthBoard, e.Board)
+			suggest("tenth_year_pass", p.TenthYearPass, e.Year)
+			suggest("tenth_institution", p.TenthInstitution, e.Institution)
+		}
+	}
+	// A lone "CGPA: 8.4" with no degree line is taken as the UG CGPA
+	if !hasDegree {
+		suggest("ug_cgpa", p.UgCgpa, parsed.CGPA)
+	}
+	return out
+}
+
+// applyResumeSuggestion writes one accepted suggestion into the profile
+func applyResumeSuggestion(p *models.UpdateProfileInput, s models.ProfileSuggestion) {
+	if key, ok := strings.CutPrefix(s.Field, "social_links."); ok {
+		if p.SocialLinks == nil {
+			p.SocialLinks = map[string]string{}
+		}
+		p.SocialLinks[key] = s.Suggested.(string)
+		return
+	}
+	switch s.Field {
+	case "mobile_number":
+		p.MobileNumber = s.Suggested.(string)
+	case "language_skills":
+		p.LanguageSkills = s.Suggested.([]string)
+	case "pg_cgpa":
+		p.PgCgpa = s.Suggested.(float64)
+	case "ug_cgpa":
+		p.UgCgpa = s.Suggested.(float64)
+	case "diploma_mark":
+		p.DiplomaMark = s.Suggested.(float64)
+	case "diploma_year_pass":
+		p.DiplomaYearPass = s.Suggested.(int)
+	case "diploma_institution":
+		p.DiplomaInstitution = s.Suggested.(string)
+	case "twelfth_mark":
+		p.TwelfthMark = s.Suggested.(float64)
+	case "twelfth_board":
+		p.TwelfthBoard = s.Suggested.(string)
+	case "twelfth_year_pass":
+		p.TwelfthYearPass = s.Suggested.(int)
+	case "twelfth_institution":
+		p.TwelfthInstitution = s.Suggested.(string)
+	case "tenth_mark":
+		p.TenthMark = s.Suggested.(float64)
+	case "tenth_board":
+		p.TenthBoard = s.Suggested.(string)
+	case "tenth_year_pass":
+		p.TenthYearPass = s.Suggested.(int)
+	case "tenth_institution":
+		p.TenthInstitution = s.Suggested.(string)
+	}
+}
+
+func digitsOnly(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func normalizeLink(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "https://"), "http://")
+	return strings.TrimSuffix(strings.TrimPrefix(s, "www."), "/")
+}
+
+func containsFold(list []string, s string) bool {
+	for _, x := range list {
+		if strings.EqualFold(x, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetResumeSuggestions parses the uploaded resume into profile suggestions
+// @Summary Resume Profile Suggestions
+// @Description Reads the uploaded resume (PDF or DOCX, parsed on the server) and returns detected skills, education, CGPA, links and contact details with the profile changes they suggest. Nothing is saved.
+// @Tags Student
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.ResumeSuggestions
+// @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/resume/suggestions [get]
+func GetResumeSuggestions(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+
+	_, result, err := parseStudentResume(c.Context(), userID)
+	if err != nil {
+		return resumeError(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+// ApplyResumeSuggestions saves the suggestions the student confirmed
+// @Summary Apply Resume Suggestions
+// @Description Re-parses the resume and writes only the confirmed fields to the profile (via the normal profile update)
+// @Tags Student
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.ApplyResumeSuggestionsInput true "Accepted fields, e.g. [\"ug_cgpa\", \"social_links.github\"]"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 422 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/student/resume/suggestions/apply [post]
+func ApplyResumeSuggestions(c *fiber.Ctx) error {
+	userID := int64(c.Locals("user_id").(float64))
+
+	var input models.ApplyResumeSuggestionsInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	profile, result, err := parseStudentResume(c.Context(), userID)
+	if err != nil {
+		return resumeError(c, err)
+	}
+
+	byField := make(map[string]models.ProfileSuggestion, len(result.Suggestions))
+	for _, s := range result.Suggestions {
+		byField[s.Field] = s
+	}
+	applied := []models.ProfileSuggestion{}
+	var unknown []string
+	for _, field := range input.Fields {
+		s, ok := byField[field]
+		if !ok {
+			unknown = append(unknown, field)
+			continue
+		}
+		applyResumeSuggestion(profile, s)
+		applied = append(applied, s)
+	}
+	if len(unknown) > 0 {
+		return c.Status(400).JSON(fiber.Map{"error": "No current suggestion for these fields", "fields": unknown})
+	}
+
+	if profile.UgCgpa > 10.0 || profile.PgCgpa > 10.0 {
+		return c.Status(400).JSON(fiber.Map{"error": "CGPA cannot be greater than 10.0"})
+	}
+
+	if err := repository.NewStudentRepository(database.DB).UpdateStudentProfile(c.Context(), userID, *profile); err != nil {
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update profile", "details": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Profile updated from resume", "applied": applied})
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// parseContactLogInput validates a contact log body and resolves its dates.
+// contacted_at defaults to now; follow_up_date is optional.
+func parseContactLogInput(c *fiber.Ctx) (models.SpocContactLogInput, time.Time, pgtype.Date, error) {
+	var input models.SpocContactLogInput
+	var followUp pgtype.Date
+	if err := c.BodyParser(&input); err != nil {
+		return input, time.Time{}, followUp, errors.New("Invalid input")
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return input, time.Time{}, followUp, fmt.Errorf("Validation failed: %v", err)
+	}
+
+	contactedAt := time.Now()
+	if input.ContactedAt != "" {
+		t, err := time.Parse(time.RFC3339, input.ContactedAt)
+		if err != nil {
+			return input, time.Time{}, followUp, errors.New("Invalid contacted_at (RFC3339)")
+		}
+		contactedAt = t
+	}
+	if input.FollowUpDate != "" {
+		t, err := time.Parse("2006-01-02", input.FollowUpDate)
+		if err != nil {
+			return input, time.Time{}, followUp, errors.New("Invalid follow_up_date (YYYY-MM-DD)")
+		}
+		followUp = pgtype.Date{Time: t, Valid: true}
+	}
+	return input, contactedAt, followUp, nil
+}
+
+// spocContactParams reads :id and (when present) :log_id
+func spocContactParams(c *fiber.Ctx) (int64, int64, error) {
+	spocID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return 0, 0, errors.New("Invalid SPOC ID")
+	}
+	if c.Params("log_id") == "" {
+		return spocID, 0, nil
+	}
+	logID, err := strconv.ParseInt(c.Params("log_id"), 10, 64)
+	if err != nil {
+		return 0, 0, errors.New("Invalid contact log ID")
+	}
+	return spocID, logID, nil
+}
+
+// ListSpocContactLogs returns a SPOC's contact history
+// @Summary List SPOC Contact Logs
+// @Description Calls, emails and visits with a SPOC, most recent first
+// @Tags Spocs
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "SPOC ID"
+// @Param page query int false "Page Number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/spocs/{id}/contacts [get]
+func ListSpocContactLogs(c *fiber.Ctx) error {
+	spocID, _, err := spocContactParams(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	logs, total, err := repository.NewSpocRepository(database.DB).ListContactLogs(c.Context(), spocID, limit, offset)
+	if err != nil {
+		fmt.Printf("Error fetching contact logs for spoc %d: %v\n", spocID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch contact logs"})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": logs,
+		"meta": fiber.Map{
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// CreateSpocContactLog records an interaction with a SPOC
+// @Summary Log SPOC Contact
+// @Description Record a call, email or visit with notes and an optional follow-up date
+// @Tags Spocs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "SPOC ID"
+// @Param input body models.SpocContactLogInput true "Contact"
+// @Success 201 {object} models.SpocContactLog
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/spocs/{id}/contacts [post]
+func CreateSpocContactLog(c *fiber.Ctx) error {
+	spocID, _, err := spocContactParams(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	input, contactedAt, followUp, err := parseContactLogInput(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	repo := repository.NewSpocRepository(database.DB)
+	spoc, err := repo.GetSpoc(c.Context(), spocID)
+	if err != nil {
+		fmt.Printf("Error fetching spoc %d: %v\n", spocID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch SPOC"})
+	}
+	if spoc == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "SPOC not found"})
+	}
+
+	userID := int64(c.Locals("user_id").(float64))
+	entry, err := repo.CreateContactLog(c.Context(), spocID, userID, input, contactedAt, followUp)
+	if errors.Is(err, repository.ErrContactLogLink) {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		fmt.Printf("Error creating contact log for spoc %d: %v\n", spocID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save contact log"})
+	}
+
+	return c.Status(201).JSON(entry)
+}
+
+// UpdateSpocContactLog edits a contact log entry
+// @Summary Update SPOC Contact Log
+// @Description Edit notes or dates, or mark the follow-up done
+// @Tags Spocs
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "SPOC ID"
+// @Param log_id path int true "Contact Log ID"
+// @Param input body models.SpocContactLogInput true "Contact"
+// @Success 200 {object} models.SpocContactLog
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/spocs/{id}/contacts/{log_id} [put]
+func UpdateSpocContactLog(c *fiber.Ctx) error {
+	spocID, logID, err := spocContactParams(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	input, contactedAt, followUp, err := parseContactLogInput(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	entry, err := repository.NewSpocRepository(database.DB).UpdateContactLog(c.Context(), spocID, logID, input, contactedAt, followUp)
+	if errors.Is(err, repository.ErrContactLogLink) {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		fmt.Printf("Error updating contact log %d: %v\n", logID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update contact log"})
+	}
+	if entry == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Contact log not found"})
+	}
+
+	return c.JSON(entry)
+}
+
+// DeleteSpocContactLog removes a contact log entry
+// @Summary Delete SPOC Contact Log
+// @Tags Spocs
+// @Security BearerAuth
+// @Param id path int true "SPOC ID"
+// @Param log_id path int true "Contact Log ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/spocs/{id}/contacts/{log_id} [delete]
+func DeleteSpocContactLog(c *fiber.Ctx) error {
+	spocID, logID, err := spocContactParams(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	deleted, err := repository.NewSpocRepository(database.DB).DeleteContactLog(c.Context(), spocID, logID)
+	if err != nil {
+		fmt.Printf("Error deleting contact log %d: %v\n", logID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete contact log"})
+	}
+	if !deleted {
+		return c.Status(404).JSON(fiber.Map{"error": "Contact log not found"})
+	}
+
+	return c.JSON(fiber.Map{"message": "Contact log deleted"})
+}
+
+// ListSpocFollowUps returns follow-ups that are due
+// @Summary List Due SPOC Follow-ups
+// @Description Open follow-ups across all SPOCs due on or before due_by (default today), oldest first
+// @Tags Spocs
+// @Produce json
+// @Security BearerAuth
+// @Param due_by query string false "YYYY-MM-DD"
+// @Success 200 {array} models.SpocContactLog
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/spocs/follow-ups [get]
+func ListSpocFollowUps(c *fiber.Ctx) error {
+	dueBy := time.Now()
+	if q := c.Query("due_by"); q != "" {
+		t, err := time.Parse("2006-01-02", q)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Invalid due_by (YYYY-MM-DD)"})
+		}
+		dueBy = t
+	}
+
+	logs, err := repository.NewSpocRepository(database.DB).ListDueFollowUps(c.Context(), dueBy)
+	if err != nil {
+		fmt.Printf("Error fetching spoc follow-ups: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch follow-ups"})
+	}
+
+	return c.JSON(logs)
+}
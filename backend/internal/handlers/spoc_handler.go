@@ -1,43 +1,91 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/SysSyncer/placement-portal-kec/internal/database"
 	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 )
 
 // ListSpocs returns all active SPOCs
 // @Summary List all SPOCs
-// @Description Get a list of all Single Points of Contact
+// @Description Get a list of all active Single Points of Contact (for drive forms). Admins only.
 // @Tags Spocs
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {array} models.Spoc
+// @Failure 403 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/spocs [get]
 func ListSpocs(c *fiber.Ctx) error {
-	query := `SELECT id, name, designation, mobile_number, email, is_active FROM spocs WHERE is_active = true ORDER BY name ASC`
-
-	rows, err := database.DB.Query(c.Context(), query)
+	active := true
+	spocs, _, err := repository.NewSpocRepository(database.DB).ListSpocs(c.Context(), "", &active, 0, 0)
 	if err != nil {
 		fmt.Printf("Error fetching spocs: %v\n", err)
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch SPOCs"})
 	}
-	defer rows.Close()
 
-	spocs := []models.Spoc{}
-	for rows.Next() {
-		var s models.Spoc
-		// Note: models.Spoc struct: ID, Name, Designation, MobileNumber, Email, IsActive
-		if err := rows.Scan(&s.ID, &s.Name, &s.Designation, &s.MobileNumber, &s.Email, &s.IsActive); err != nil {
-			return c.Status(500).JSON(fiber.Map{"error": "Failed to process SPOC data"})
-		}
-		spocs = append(spocs, s)
+	return c.JSON(spocs)
+}
+
+// ListAdminSpocs returns SPOCs page by page
+// @Summary List SPOCs (Admin)
+// @Description Paginated SPOC directory with search and active/inactive filter
+// @Tags Spocs
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "Name, designation, email or mobile"
+// @Param status query string false "active, inactive or all (default)"
+// @Param page query int false "Page Number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/spocs [get]
+func ListAdminSpocs(c *fiber.Ctx) error {
+	var active *bool
+	switch c.Query("status", "all") {
+	case "active":
+		v := true
+		active = &v
+	case "inactive":
+		v := false
+		active = &v
+	case "all":
+	default:
+		return c.Status(400).JSON(fiber.Map{"error": "status must be active, inactive or all"})
 	}
 
-	return c.JSON(spocs)
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	spocs, total, err := repository.NewSpocRepository(database.DB).ListSpocs(c.Context(), c.Query("search"), active, limit, offset)
+	if err != nil {
+		fmt.Printf("Error fetching spocs: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch SPOCs"})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": spocs,
+		"meta": fiber.Map{
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
 }
 
 // CreateSpoc adds a new SPOC
@@ -58,17 +106,12 @@ func CreateSpoc(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
 	}
 
-	query := `
-		INSERT INTO spocs (name, designation, mobile_number, email, is_active, created_at)
-		VALUES ($1, $2, $3, $4, true, NOW())
-		RETURNING id, name, designation, mobile_number, email, is_active, created_at
-	`
-
-	var s models.Spoc
-	err := database.DB.QueryRow(c.Context(), query,
-		input.Name, input.Designation, input.MobileNumber, input.Email,
-	).Scan(&s.ID, &s.Name, &s.Designation, &s.MobileNumber, &s.Email, &s.IsActive, &s.CreatedAt)
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
 
+	s, err := repository.NewSpocRepository(database.DB).CreateSpoc(c.Context(), input)
 	if err != nil {
 		fmt.Printf("Error creating spoc: %v\n", err)
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to create SPOC"})
@@ -88,12 +131,13 @@ func CreateSpoc(c *fiber.Ctx) error {
 // @Param spoc body models.CreateSpocInput true "SPOC Details"
 // @Success 200 {object} models.Spoc
 // @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/admin/spocs/{id} [put]
 func UpdateSpoc(c *fiber.Ctx) error {
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "SPOC ID required"})
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid SPOC ID"})
 	}
 
 	var input models.CreateSpocInput
@@ -101,48 +145,52 @@ func UpdateSpoc(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
 	}
 
-	query := `
-		UPDATE spocs 
-		SET name = $1, designation = $2, mobile_number = $3, email = $4
-		WHERE id = $5
-		RETURNING id, name, designation, mobile_number, email, is_active, created_at
-	`
-
-	var s models.Spoc
-	err := database.DB.QueryRow(c.Context(), query,
-		input.Name, input.Designation, input.MobileNumber, input.Email, id,
-	).Scan(&s.ID, &s.Name, &s.Designation, &s.MobileNumber, &s.Email, &s.IsActive, &s.CreatedAt)
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
 
+	s, err := repository.NewSpocRepository(database.DB).UpdateSpoc(c.Context(), id, input)
 	if err != nil {
 		fmt.Printf("Error updating spoc: %v\n", err)
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to update SPOC"})
 	}
+	if s == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "SPOC not found"})
+	}
 
 	return c.JSON(s)
 }
 
 // DeleteSpoc deletes a SPOC
 // @Summary Delete SPOC
-// @Description Delete a SPOC by ID
+// @Description Delete a SPOC by ID. SPOCs that are a drive's primary contact or have contact history must be deactivated instead.
 // @Tags Spocs
 // @Security BearerAuth
 // @Param id path int true "SPOC ID"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/admin/spocs/{id} [delete]
 func DeleteSpoc(c *fiber.Ctx) error {
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "SPOC ID required"})
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid SPOC ID"})
 	}
 
-	query := `DELETE FROM spocs WHERE id = $1`
-	_, err := database.DB.Exec(c.Context(), query, id)
+	deleted, err := repository.NewSpocRepository(database.DB).DeleteSpoc(c.Context(), id)
+	if errors.Is(err, repository.ErrSpocInUse) {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
 	if err != nil {
 		fmt.Printf("Error deleting spoc: %v\n", err)
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete SPOC"})
 	}
+	if !deleted {
+		return c.Status(404).JSON(fiber.Map{"error": "SPOC not found"})
+	}
 
 	return c.JSON(fiber.Map{"message": "SPOC deleted successfully"})
 }
@@ -158,12 +206,13 @@ func DeleteSpoc(c *fiber.Ctx) error {
 // @Param status body map[string]bool true "Status"
 // @Success 200 {object} models.Spoc
 // @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /v1/admin/spocs/{id}/status [put]
 func ToggleSpocStatus(c *fiber.Ctx) error {
-	id := c.Params("id")
-	if id == "" {
-		return c.Status(400).JSON(fiber.Map{"error": "SPOC ID required"})
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid SPOC ID"})
 	}
 
 	var input struct {
@@ -173,22 +222,50 @@ func ToggleSpocStatus(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
 	}
 
-	query := `
-		UPDATE spocs 
-		SET is_active = $1
-		WHERE id = $2
-		RETURNING id, name, designation, mobile_number, email, is_active, created_at
-	`
-
-	var s models.Spoc
-	err := database.DB.QueryRow(c.Context(), query, input.IsActive, id).Scan(
-		&s.ID, &s.Name, &s.Designation, &s.MobileNumber, &s.Email, &s.IsActive, &s.CreatedAt,
-	)
-
+	s, err := repository.NewSpocRepository(database.DB).SetSpocActive(c.Context(), id, input.IsActive)
 	if err != nil {
 		fmt.Printf("Error toggling spoc status: %v\n", err)
 		return c.Status(500).JSON(fiber.Map{"error": "Failed to update SPOC status"})
 	}
+	if s == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "SPOC not found"})
+	}
 
 	return c.JSON(s)
 }
+
+// ListSpocDrives lists every drive a SPOC is attached to
+// @Summary List SPOC Drives
+// @Description Drives the SPOC is a contact for, latest first, flagging where they are the primary contact
+// @Tags Spocs
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "SPOC ID"
+// @Success 200 {array} models.SpocDrive
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/spocs/{id}/drives [get]
+func ListSpocDrives(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid SPOC ID"})
+	}
+
+	spoc, err := repository.NewSpocRepository(database.DB).GetSpoc(c.Context(), id)
+	if err != nil {
+		fmt.Printf("Error fetching spoc %d: %v\n", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch SPOC"})
+	}
+	if spoc == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "SPOC not found"})
+	}
+
+	drives, err := repository.NewDriveRepository(database.DB).GetSpocDrives(c.Context(), id)
+	if err != nil {
+		fmt.Printf("Error fetching drives for spoc %d: %v\n", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch SPOC drives"})
+	}
+
+	return c.JSON(drives)
+}
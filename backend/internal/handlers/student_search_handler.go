@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// runStudentSearch executes a search and writes the page, mapping bad sorts and cursors to 400
+func runStudentSearch(c *fiber.Ctx, q models.StudentSearchQuery) error {
+	students, next, total, err := repository.NewStudentSearchRepository(database.DB).SearchStudents(c.Context(), q)
+	if errors.Is(err, repository.ErrInvalidStudentSort) || errors.Is(err, repository.ErrInvalidCursor) {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		fmt.Println("Error searching students:", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to search students"})
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	return c.JSON(fiber.Map{
+		"data": students,
+		"meta": fiber.Map{
+			"total":       total,
+			"limit":       limit,
+			"next_cursor": next,
+		},
+	})
+}
+
+// SearchStudents runs a composable student search
+// @Summary Advanced Student Search
+// @Description Filter students by academics, backlogs, willingness, placement status, skills, gender and student type; sort on any column (register_number, full_name, email, department, batch_year, ug_cgpa, pg_cgpa, tenth_mark, twelfth_mark, diploma_mark, current_backlogs, history_of_backlogs, last_login, created_at). Pass meta.next_cursor back as cursor for the next page.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.StudentSearchQuery true "Search"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/students/search [post]
+func SearchStudents(c *fiber.Ctx) error {
+	var q models.StudentSearchQuery
+	if err := c.BodyParser(&q); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+	validate := validator.New()
+	if err := validate.Struct(q); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	return runStudentSearch(c, q)
+}
+
+// ListSavedStudentSearches
+// @Summary List Saved Student Searches
+// @Description The admin's own saved searches plus those shared by other admins
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.SavedStudentSearch
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/student-searches [get]
+func ListSavedStudentSearches(c *fiber.Ctx) error {
+	adminID := int64(c.Locals("user_id").(float64))
+
+	searches, err := repository.NewStudentSearchRepository(database.DB).ListSavedSearches(c.Context(), adminID)
+	if err != nil {
+		fmt.Println("Error fetching saved searches:", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch saved searches"})
+	}
+	return c.JSON(searches)
+}
+
+// parseSavedSearchInput validates a saved search, including its sort column
+func parseSavedSearchInput(c *fiber.Ctx) (models.SavedStudentSearchInput, error) {
+	var input models.SavedStudentSearchInput
+	if err := c.BodyParser(&input); err != nil {
+		return input, errors.New("Invalid input")
+	}
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return input, fmt.Errorf("Validation failed: %v", err)
+	}
+	if !repository.IsStudentSortColumn(input.Query.Sort) {
+		return input, repository.ErrInvalidStudentSort
+	}
+	return input, nil
+}
+
+// CreateSavedStudentSearch
+// @Summary Save Student Search
+// @Description Save filters and sort under a name. Set is_shared to show it to every admin.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param input body models.SavedStudentSearchInput true "Saved Search"
+// @Success 201 {object} models.SavedStudentSearch
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/student-searches [post]
+func CreateSavedStudentSearch(c *fiber.Ctx) error {
+	adminID := int64(c.Locals("user_id").(float64))
+	input, err := parseSavedSearchInput(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	search, err := repository.NewStudentSearchRepository(database.DB).CreateSavedSearch(c.Context(), adminID, input)
+	if errors.Is(err, repository.ErrSavedSearchExists) {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		fmt.Println("Error saving search:", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save search"})
+	}
+	return c.Status(201).JSON(search)
+}
+
+// UpdateSavedStudentSearch
+// @Summary Update Saved Student Search
+// @Description Only the admin who created a search can change it
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Saved Search ID"
+// @Param input body models.SavedStudentSearchInput true "Saved Search"
+// @Success 200 {object} models.SavedStudentSearch
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/student-searches/{id} [put]
+func UpdateSavedStudentSearch(c *fiber.Ctx) error {
+	adminID := int64(c.Locals("user_id").(float64))
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid ID"})
+	}
+	input, err := parseSavedSearchInput(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	search, err := repository.NewStudentSearchRepository(database.DB).UpdateSavedSearch(c.Context(), id, adminID, input)
+	if errors.Is(err, repository.ErrSavedSearchExists) {
+		return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err != nil {
+		fmt.Println("Error updating saved search:", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to update saved search"})
+	}
+	if search == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Saved search not found"})
+	}
+	return c.JSON(search)
+}
+
+// DeleteSavedStudentSearch
+// @Summary Delete Saved Student Search
+// @Tags Admin
+// @Security BearerAuth
+// @Param id path int true "Saved Search ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/student-searches/{id} [delete]
+func DeleteSavedStudentSearch(c *fiber.Ctx) error {
+	adminID := int64(c.Locals("user_id").(float64))
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid ID"})
+	}
+
+	deleted, err := repository.NewStudentSearchRepository(database.DB).DeleteSavedSearch(c.Context(), id, adminID)
+	if err != nil {
+		fmt.Println("Error deleting saved search:", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to delete saved search"})
+	}
+	if !deleted {
+		return c.Status(404).JSON(fiber.Map{"error": "Saved search not found"})
+	}
+	return c.JSON(fiber.Map{"message": "Saved search deleted"})
+}
+
+// RunSavedStudentSearch
+// @Summary Run Saved Student Search
+// @Description Runs a saved search with its stored filters and sort
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Saved Search ID"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/student-searches/{id}/results [get]
+func RunSavedStudentSearch(c *fiber.Ctx) error {
+	adminID := int64(c.Locals("user_id").(float64))
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid ID"})
+	}
+
+	search, err := repository.NewStudentSearchRepository(database.DB).GetSavedSearch(c.Context(), id, adminID)
+	if err != nil {
+		fmt.Println("Error fetching saved search:", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch saved search"})
+	}
+	if search == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Saved search not found"})
+	}
+
+	q := search.Query
+	q.Cursor = c.Query("cursor")
+	q.Limit, _ = strconv.Atoi(c.Query("limit", "50"))
+	if q.Limit < 1 || q.Limit > 500 {
+		q.Limit = 50
+	}
+	return runStudentSearch(c, q)
+}
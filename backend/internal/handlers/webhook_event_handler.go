@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/services"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Built-in webhook event handlers. New integrations register theirs the same way.
+func init() {
+	services.RegisterWebhookHandler("railway", "deployment.success", logRailwayDeployment)
+	services.RegisterWebhookHandler("railway", "deployment.started", logRailwayDeployment)
+	services.RegisterWebhookHandler("railway", "deployment.failed", logRailwayDeployment)
+	services.RegisterWebhookHandler("railway", "deployment.failed", notifyAdminsDeploymentFailed)
+}
+
+// maxWebhookEventTypeLen is the width of webhook_events.event_type
+const maxWebhookEventTypeLen = 100
+
+// storeAndDispatchWebhook persists the current request as a webhook event and runs its handlers.
+// Only a storage failure is returned; handler errors are recorded on the event.
+func storeAndDispatchWebhook(c *fiber.Ctx, source, eventType string) (*models.WebhookEvent, error) {
+	// The event type comes from the payload; an oversized one can't match a handler anyway
+	if runes := []rune(eventType); len(runes) > maxWebhookEventTypeLen {
+		eventType = string(runes[:maxWebhookEventTypeLen])
+	}
+
+	// Keep headers for debugging, but never credentials
+	headers := map[string]string{}
+	c.Request().Header.VisitAll(func(key, value []byte) {
+		k := string(key)
+		if strings.EqualFold(k, "Authorization") || strings.EqualFold(k, "Cookie") {
+			return
+		}
+		headers[k] = string(value)
+	})
+
+	event := models.WebhookEvent{
+		Source:    source,
+		EventType: eventType,
+		Headers:   headers,
+		Body:      json.RawMessage(append([]byte{}, c.Body()...)),
+		Status:    models.WebhookEventReceived,
+	}
+
+	repo := repository.NewWebhookEventRepository(database.DB)
+	id, err := repo.CreateEvent(c.Context(), event)
+	if err != nil {
+		return nil, err
+	}
+	event.ID = id
+
+	processWebhookEvent(c.Context(), &event)
+	return &event, nil
+}
+
+// processWebhookEvent dispatches a stored event and records the outcome on it
+func processWebhookEvent(ctx context.Context, event *models.WebhookEvent) {
+	status, err := services.DispatchWebhookEvent(ctx, *event)
+	event.Status = status
+	event.Error = ""
+	if err != nil {
+		event.Error = err.Error()
+		log.Printf("Webhook Event %d (%s/%s) failed: %v", event.ID, event.Source, event.EventType, err)
+	}
+
+	repo := repository.NewWebhookEventRepository(database.DB)
+	if err := repo.MarkOutcome(ctx, event.ID, event.Status, event.Error); err != nil {
+		log.Printf("Webhook Event %d: Failed to record outcome: %v", event.ID, err)
+	}
+}
+
+func logRailwayDeployment(ctx context.Context, event models.WebhookEvent) error {
+	var payload RailwayWebhookPayload
+	_ = json.Unmarshal(event.Body, &payload)
+
+	switch event.EventType {
+	case "deployment.success":
+		log.Printf("✅ Deployment successful for project: %s", payload.ProjectID)
+	case "deployment.failed":
+		log.Printf("❌ Deployment failed for project: %s", payload.ProjectID)
+	case "deployment.started":
+		log.Printf("🚀 Deployment started for project: %s", payload.ProjectID)
+	}
+	return nil
+}
+
+// notifyAdminsDeploymentFailed alerts all active admins by email and push notification
+func notifyAdminsDeploymentFailed(ctx context.Context, event models.WebhookEvent) error {
+	var payload RailwayWebhookPayload
+	if err := json.Unmarshal(event.Body, &payload); err != nil {
+		return fmt.Errorf("invalid railway payload: %w", err)
+	}
+
+	emails, tokens, err := repository.NewUserRepository(database.DB).GetAdminNotificationTargets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch admins: %w", err)
+	}
+	if len(emails) == 0 {
+		return nil
+	}
+
+	deploymentID, _ := payload.Data["deploymentId"].(string)
+	title := "Deployment failed"
+	body := fmt.Sprintf("Railway deployment %s for project %s failed at %s.", deploymentID, payload.ProjectID, payload.Timestamp)
+
+	data := map[string]interface{}{
+		"DeploymentID": deploymentID,
+		"ProjectID":    payload.ProjectID,
+		"Timestamp":    payload.Timestamp,
+		"EventID":      event.ID,
+	}
+	if err := services.NewEmailService().SendTemplate(emails, services.EmailTemplateDeploymentFailed, data, nil); err != nil {
+		return err
+	}
+
+	// Push is best effort; email above is the primary channel
+	if len(tokens) > 0 {
+		ns, err := services.NewNotificationService("firebase-service-account.json")
+		if err != nil {
+			log.Printf("Notification Error: Failed to init service: %v", err)
+			return nil
+		}
+		data := map[string]string{"type": "deployment_failed", "webhook_event_id": strconv.FormatInt(event.ID, 10)}
+		if _, err := ns.SendMulticastNotification(ctx, tokens, title, body, data); err != nil {
+			log.Printf("Notification Error: Send failed: %v", err)
+		}
+	}
+	return nil
+}
+
+// ListWebhookEvents lists stored incoming webhook events
+// @Summary List Webhook Events
+// @Description List incoming webhook events (newest first) with their processing outcome
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param source query string false "Source (railway, generic)"
+// @Param type query string false "Event Type (e.g. deployment.failed)"
+// @Param status query string false "Status (received, processed, failed, ignored)"
+// @Param page query int false "Page Number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/webhook-events [get]
+func ListWebhookEvents(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	repo := repository.NewWebhookEventRepository(database.DB)
+	events, total, err := repo.ListEvents(c.Context(), c.Query("source"), c.Query("type"), c.Query("status"), limit, offset)
+	if err != nil {
+		fmt.Printf("Error fetching webhook events: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch webhook events"})
+	}
+
+	return c.JSON(fiber.Map{
+		"data": events,
+		"meta": fiber.Map{
+			"total":       total,
+			"page":        page,
+			"limit":       limit,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetWebhookEvent returns a single webhook event with headers and body
+// @Summary Get Webhook Event
+// @Description Inspect a stored webhook event including its headers, raw body and processing outcome
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook Event ID"
+// @Success 200 {object} models.WebhookEvent
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/webhook-events/{id} [get]
+func GetWebhookEvent(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Event ID"})
+	}
+
+	repo := repository.NewWebhookEventRepository(database.DB)
+	event, err := repo.GetEvent(c.Context(), id)
+	if err != nil {
+		fmt.Printf("Error fetching webhook event %d: %v\n", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch webhook event"})
+	}
+	if event == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Webhook event not found"})
+	}
+
+	return c.JSON(event)
+}
+
+// ReplayWebhookEvent runs a stored event through its handlers again
+// @Summary Replay Webhook Event
+// @Description Re-dispatch a stored webhook event (e.g. after fixing a failed handler) and record the new outcome
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Webhook Event ID"
+// @Success 200 {object} models.WebhookEvent
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /v1/admin/webhook-events/{id}/replay [post]
+func ReplayWebhookEvent(c *fiber.Ctx) error {
+	id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Event ID"})
+	}
+
+	repo := repository.NewWebhookEventRepository(database.DB)
+	event, err := repo.GetEvent(c.Context(), id)
+	if err != nil {
+		fmt.Printf("Error fetching webhook event %d: %v\n", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch webhook event"})
+	}
+	if event == nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Webhook event not found"})
+	}
+
+	processWebhookEvent(c.Context(), event)
+
+	// Reload for the updated attempts / processed_at
+	if updated, err := repo.GetEvent(c.Context(), id); err == nil && updated != nil {
+		event = updated
+	}
+	return c.JSON(event)
+}
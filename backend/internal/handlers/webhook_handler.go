@@ -1,16 +1,138 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"strings"
+	"strconv"
+	"time"
 
 	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
 	"github.com/SysSyncer/placement-portal-kec/internal/repository"
 	"github.com/SysSyncer/placement-portal-kec/internal/services"
 	"github.com/gofiber/fiber/v2"
 )
 
+// whatsAppWebhookPayload mirrors the parts of the Cloud API webhook we act on
+type whatsAppWebhookPayload struct {
+	Entry []struct {
+		Changes []struct {
+			Value struct {
+				Messages []whatsAppInboundMessage `json:"messages"`
+				Statuses []whatsAppStatus         `json:"statuses"`
+			} `json:"value"`
+		} `json:"changes"`
+	} `json:"entry"`
+}
+
+type whatsAppInboundMessage struct {
+	From string `json:"from"`
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Text *struct {
+		Body string `json:"body"`
+	} `json:"text"`
+	Button *struct {
+		Text    string `json:"text"`
+		Payload string `json:"payload"`
+	} `json:"button"`
+	Interactive *struct {
+		Type        string `json:"type"` // 'button_reply'
+		ButtonReply *struct {
+			ID    string `json:"id"`
+			Title string `json:"title"`
+		} `json:"button_reply"`
+	} `json:"interactive"`
+}
+
+type whatsAppStatus struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"` // sent, delivered, read, failed
+	Timestamp   string `json:"timestamp"`
+	RecipientID string `json:"recipient_id"`
+	Errors      []struct {
+		Code  int    `json:"code"`
+		Title string `json:"title"`
+	} `json:"errors"`
+}
+
+// processWhatsAppStatuses updates per-recipient delivery records from a status webhook
+func processWhatsAppStatuses(ctx context.Context, statuses []whatsAppStatus) {
+	repo := repository.NewWhatsAppMessageRepository(database.DB)
+	for _, st := range statuses {
+		switch st.Status {
+		case "sent", "delivered", "read", "failed":
+		default:
+			continue
+		}
+
+		update := models.WhatsAppStatusUpdate{
+			MessageID:   st.ID,
+			Status:      st.Status,
+			RecipientID: st.RecipientID,
+			Timestamp:   time.Now(),
+		}
+		if secs, err := strconv.ParseInt(st.Timestamp, 10, 64); err == nil {
+			update.Timestamp = time.Unix(secs, 0)
+		}
+		if len(st.Errors) > 0 {
+			update.Error = fmt.Sprintf("%d: %s", st.Errors[0].Code, st.Errors[0].Title)
+		}
+
+		if _, err := repo.ApplyStatusUpdate(ctx, update); err != nil {
+			fmt.Printf("WhatsApp Error: Failed to apply status %s for %s: %v\n", st.Status, st.ID, err)
+		}
+	}
+}
+
+// processWhatsAppMessages runs each inbound message through the bot and sends its reply
+func processWhatsAppMessages(ctx context.Context, messages []whatsAppInboundMessage) {
+	waService := services.NewWhatsAppService()
+	for _, msgData := range messages {
+		from := msgData.From // Provide number
+
+		body := ""
+		switch msgData.Type {
+		case "text":
+			if msgData.Text != nil {
+				body = msgData.Text.Body
+			}
+		case "button":
+			// Quick-reply buttons on template messages
+			if msgData.Button != nil {
+				body = msgData.Button.Payload
+				if body == "" {
+					body = msgData.Button.Text
+				}
+			}
+		case "interactive":
+			// Reply buttons sent by the bot (the ID is the command)
+			if msgData.Interactive != nil && msgData.Interactive.ButtonReply != nil {
+				body = msgData.Interactive.ButtonReply.ID
+			}
+		}
+
+		fmt.Printf("WhatsApp Cloud: From=%s Body=%s\n", from, body)
+
+		// Bot Logic
+		reply := handleWhatsAppCommand(ctx, from, body)
+		if reply == nil {
+			continue
+		}
+
+		var err error
+		if len(reply.Buttons) > 0 {
+			err = waService.SendButtons(from, reply.Text, reply.Buttons)
+		} else {
+			err = waService.SendMessage(from, reply.Text)
+		}
+		if err != nil {
+			fmt.Printf("WhatsApp Error: Failed to reply to %s: %v\n", from, err)
+		}
+	}
+}
+
 // HandleWhatsAppWebhook handles both verification (GET) and messages (POST)
 // GET /v1/webhooks/whatsapp -> Verification
 // POST /v1/webhooks/whatsapp -> Message Processing
@@ -28,8 +150,8 @@ func HandleWhatsAppWebhook(c *fiber.Ctx) error {
 		return c.Status(403).SendString("Verification failed")
 	}
 
-	// 2. Incoming Message (POST)
-	var payload map[string]interface{}
+	// 2. Incoming Message / Status Update (POST)
+	var payload whatsAppWebhookPayload
 	if err := c.BodyParser(&payload); err != nil {
 		return c.Status(400).SendString("Invalid JSON")
 	}
@@ -37,78 +159,23 @@ func HandleWhatsAppWebhook(c *fiber.Ctx) error {
 	// Basic logging
 	// fmt.Printf("Webhook Payload: %+v\n", payload)
 
-	// Extract Message
-	// Structure: entry[0].changes[0].value.messages[0]
-	entry, ok := payload["entry"].([]interface{})
-	if !ok || len(entry) == 0 {
-		return c.Status(200).SendString("No entry")
-	}
-
-	changes, ok := entry[0].(map[string]interface{})["changes"].([]interface{})
-	if !ok || len(changes) == 0 {
-		return c.Status(200).SendString("No changes")
-	}
-
-	value, ok := changes[0].(map[string]interface{})["value"].(map[string]interface{})
-	if !ok {
-		return c.Status(200).SendString("No value")
-	}
-
-	messages, ok := value["messages"].([]interface{})
-	if !ok || len(messages) == 0 {
-		// Could be a status update (sent/delivered/read), just ignore
-		return c.Status(200).SendString("No messages")
-	}
-
-	msgData := messages[0].(map[string]interface{})
-	from := msgData["from"].(string) // Provide number
-
-	body := ""
-	if msgData["type"] == "text" {
-		textBody, ok := msgData["text"].(map[string]interface{})
-		if ok {
-			body = textBody["body"].(string)
-		}
-	} else if msgData["type"] == "button" {
-		// Handle button replies if you add interactive messages later
-		btnBody, ok := msgData["button"].(map[string]interface{})
-		if ok {
-			body = btnBody["text"].(string)
-		}
-	}
-
-	fmt.Printf("WhatsApp Cloud: From=%s Body=%s\n", from, body)
-
-	// Bot Logic
-	command := strings.ToLower(strings.TrimSpace(body))
-	waService := services.NewWhatsAppService()
-	response := ""
-
-	switch {
-	case strings.Contains(command, "drive") || strings.Contains(command, "job"):
-		repo := repository.NewDriveRepository(database.DB)
-		drives, err := repo.GetDrives(c.Context(), nil)
-		if err != nil {
-			response = "Sorry, unavailable right now."
-		} else if len(drives) == 0 {
-			response = "No active drives."
-		} else {
-			response = "🚀 *Active Drives* 🚀\n\n"
-			for _, d := range drives {
-				response += fmt.Sprintf("🏢 *%s*\n💼 %s\n📅 %s\n\n",
-					d.CompanyName, d.JobRole, d.DeadlineDate.Format("02 Jan"))
+	// Structure: entry[].changes[].value.{messages[], statuses[]}
+	// A single delivery can batch several changes, so walk all of them.
+	var messages []whatsAppInboundMessage
+	for _, entry := range payload.Entry {
+		for _, change := range entry.Changes {
+			if len(change.Value.Statuses) > 0 {
+				processWhatsAppStatuses(c.Context(), change.Value.Statuses)
 			}
+			messages = append(messages, change.Value.Messages...)
 		}
+	}
 
-	case strings.Contains(command, "hi") || strings.Contains(command, "hello"):
-		response = "👋 KEC Placement Bot.\nSend *Drives* to see jobs."
-
-	default:
-		// Optional: Don't reply to everything to save costs/spam
-		// response = "Unknown command. Try *Drives*."
-		return c.Status(200).SendString("Ignored")
+	if len(messages) == 0 {
+		// Pure status update (sent/delivered/read/failed)
+		return c.Status(200).SendString("No messages")
 	}
 
-	waService.SendMessage(from, response)
+	processWhatsAppMessages(c.Context(), messages)
 	return c.Status(200).SendString("OK")
 }
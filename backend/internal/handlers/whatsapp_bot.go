@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/services"
+)
+
+// botReply is what the WhatsApp bot sends back: plain text, optionally with quick-reply buttons
+type botReply struct {
+	Text    string
+	Buttons []services.ReplyButton
+}
+
+// Button IDs double as commands so a tap behaves exactly like typing the text
+var (
+	botMenuButtons = []services.ReplyButton{
+		{ID: "my drives", Title: "My Drives"},
+		{ID: "status", Title: "My Status"},
+		{ID: "deadline", Title: "Deadlines"},
+	}
+	botConfirmButtons = []services.ReplyButton{
+		{ID: "confirm", Title: "Confirm"},
+		{ID: "cancel", Title: "Cancel"},
+	}
+)
+
+const (
+	botUnregisteredReply = "⚠️ This number isn't linked to a student profile.\nUpdate your mobile number in the placement app and try again."
+	botAmbiguousReply    = "⚠️ This number is registered on more than one student profile, so we can't tell who you are.\nPlease contact the placement office to fix your mobile number."
+)
+
+// handleWhatsAppCommand routes an incoming message to a bot command.
+// Returns nil when the message should be ignored (unknown text from unknown senders).
+func handleWhatsAppCommand(ctx context.Context, from string, input string) *botReply {
+	command := strings.ToLower(strings.TrimSpace(input))
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	// 1. Pending confirmation takes priority (multi-step apply / withdraw)
+	if state, ok := services.DefaultConversationStore.Get(from); ok {
+		switch command {
+		case "confirm", "yes", "y":
+			services.DefaultConversationStore.Clear(from)
+			return executeBotAction(ctx, state)
+		case "cancel", "no", "n":
+			services.DefaultConversationStore.Clear(from)
+			return &botReply{Text: "👍 Cancelled. Nothing was changed."}
+		}
+		// Any other command abandons the pending action
+		services.DefaultConversationStore.Clear(from)
+	}
+
+	// 2. Public commands (no identity needed)
+	switch {
+	case command == "hi" || command == "hello" || command == "help" || command == "menu":
+		return &botReply{
+			Text: "👋 *KEC Placement Bot*\n\n" +
+				"• *My Drives* – drives you are eligible for\n" +
+				"• *Status* – your application statuses\n" +
+				"• *Apply <id>* / *Withdraw <id> <reason>*\n" +
+				"• *Deadline* – upcoming deadlines\n" +
+				"• *Drives* – all active drives",
+			Buttons: botMenuButtons,
+		}
+	case command == "drives" || command == "jobs":
+		return botAllDrives(ctx)
+	}
+
+	// 3. Personal commands: identify the student by their registered mobile number
+	isPersonal := command == "my drives" || command == "status" || command == "deadline" || command == "deadlines" ||
+		fields[0] == "apply" || fields[0] == "withdraw"
+	if !isPersonal {
+		// Don't reply to everything to save costs/spam
+		return nil
+	}
+
+	studentID, fullName, err := repository.NewUserRepository(database.DB).GetStudentByMobileNumber(ctx, from)
+	if errors.Is(err, repository.ErrMobileNumberAmbiguous) {
+		return &botReply{Text: botAmbiguousReply}
+	}
+	if err != nil {
+		return &botReply{Text: botUnregisteredReply}
+	}
+
+	switch fields[0] {
+	case "apply", "withdraw":
+		if fields[0] == "apply" && len(fields) != 2 {
+			return &botReply{Text: "Usage: *apply <drive id>*, e.g. *apply 12*"}
+		}
+		if fields[0] == "withdraw" && len(fields) < 3 {
+			return &botReply{Text: "Usage: *withdraw <drive id> <reason>*, e.g. *withdraw 12 accepted another offer*"}
+		}
+		driveID, err := strconv.ParseInt(strings.TrimPrefix(fields[1], "#"), 10, 64)
+		if err != nil {
+			return &botReply{Text: "❌ Invalid drive ID. Send *My Drives* to see IDs."}
+		}
+		// The reason keeps the student's own casing
+		reason := ""
+		if raw := strings.Fields(input); len(raw) > 2 {
+			reason = strings.Join(raw[2:], " ")
+		}
+		return botConfirmAction(ctx, from, studentID, fields[0], driveID, reason)
+	}
+
+	switch command {
+	case "my drives":
+		return botMyDrives(ctx, studentID, fullName)
+	case "status":
+		return botStatus(ctx, studentID)
+	default: // deadline(s)
+		return botDeadlines(ctx, studentID)
+	}
+}
+
+func botAllDrives(ctx context.Context) *botReply {
+	repo := repository.NewDriveRepository(database.DB)
+	drives, err := repo.GetDrives(ctx, nil)
+	if err != nil {
+		return &botReply{Text: "Sorry, unavailable right now."}
+	}
+	if len(drives) == 0 {
+		return &botReply{Text: "No active drives."}
+	}
+
+	text := "🚀 *Active Drives* 🚀\n\n"
+	for _, d := range drives {
+		text += fmt.Sprintf("🏢 *%s*\n💼 %s\n📅 %s\n\n",
+			d.CompanyName, d.JobRole, d.DeadlineDate.Format("02 Jan"))
+	}
+	return &botReply{Text: text}
+}
+
+func botMyDrives(ctx context.Context, studentID int64, fullName string) *botReply {
+	repo := repository.NewDriveRepository(database.DB)
+	drives, err := repo.GetEligibleDrives(ctx, studentID)
+	if err != nil {
+		return &botReply{Text: "Sorry, unavailable right now."}
+	}
+	if len(drives) == 0 {
+		return &botReply{Text: fmt.Sprintf("Hi %s, there are no open drives you are eligible for right now.", fullName)}
+	}
+
+	text := fmt.Sprintf("🎯 *Drives for %s*\n\n", fullName)
+	for _, d := range drives {
+		text += fmt.Sprintf("#%d 🏢 *%s*\n💼 %s\n📅 Deadline: %s\n📌 %s\n\n",
+			d.ID, d.CompanyName, d.JobRole, d.DeadlineDate.Format("02 Jan, 03:04 PM"), botStatusLabel(d.UserStatus))
+	}
+	text += "Reply *Apply <id>* to register."
+	return &botReply{Text: text}
+}
+
+func botStatus(ctx context.Context, studentID int64) *botReply {
+	repo := repository.NewApplicationRepository(database.DB)
+	apps, err := repo.GetStudentApplications(ctx, studentID)
+	if err != nil {
+		return &botReply{Text: "Sorry, unavailable right now."}
+	}
+	if len(apps) == 0 {
+		return &botReply{Text: "You haven't applied to any drives yet. Send *My Drives* to get started."}
+	}
+
+	text := "📋 *Your Applications*\n\n"
+	for _, a := range apps {
+		text += fmt.Sprintf("#%d 🏢 *%s* – %s\n📌 %s\n\n", a.DriveID, a.CompanyName, a.JobRole, botStatusLabel(a.Status))
+	}
+	return &botReply{Text: text}
+}
+
+func botDeadlines(ctx context.Context, studentID int64) *botReply {
+	repo := repository.NewDriveRepository(database.DB)
+	drives, err := repo.GetEligibleDrives(ctx, studentID)
+	if err != nil {
+		return &botReply{Text: "Sorry, unavailable right now."}
+	}
+
+	// Drives are already sorted by deadline; show the ones still awaiting a decision
+	text := "⏰ *Upcoming Deadlines*\n\n"
+	count := 0
+	for _, d := range drives {
+		if d.UserStatus != "" && d.UserStatus != "eligible" {
+			continue
+		}
+		text += fmt.Sprintf("#%d *%s* – %s\n📅 %s\n\n", d.ID, d.CompanyName, d.JobRole, d.DeadlineDate.Format("02 Jan, 03:04 PM"))
+		count++
+		if count == 10 {
+			break
+		}
+	}
+	if count == 0 {
+		return &botReply{Text: "✅ No pending deadlines. You've responded to every open drive."}
+	}
+	return &botReply{Text: text}
+}
+
+// botConfirmAction validates an apply/withdraw request and asks the student to confirm it
+func botConfirmAction(ctx context.Context, from string, studentID int64, action string, driveID int64, reason string) *botReply {
+	repo := repository.NewDriveRepository(database.DB)
+	drives, err := repo.GetEligibleDrives(ctx, studentID)
+	if err != nil {
+		return &botReply{Text: "Sorry, unavailable right now."}
+	}
+
+	var drive *models.PlacementDrive
+	for i := range drives {
+		if drives[i].ID == driveID {
+			drive = &drives[i]
+			break
+		}
+	}
+	if drive == nil {
+		return &botReply{Text: fmt.Sprintf("❌ Drive #%d is not open for you. Send *My Drives* to see eligible drives.", driveID)}
+	}
+
+	if action == "apply" && drive.UserStatus == "opted_in" {
+		return &botReply{Text: fmt.Sprintf("✅ You have already applied to *%s*.", drive.CompanyName)}
+	}
+	if action == "withdraw" {
+		switch {
+		case utf8.RuneCountInString(reason) < 5 || utf8.RuneCountInString(reason) > 500:
+			return &botReply{Text: "❌ Please give a reason of 5 to 500 characters, e.g. *withdraw 12 accepted another offer*"}
+		case drive.UserStatus == string(models.StatusShortlisted):
+			botWithdrawBlocked(ctx, studentID, driveID, reason)
+			return &botReply{Text: "❌ " + withdrawErrorMessage(repository.ErrInvalidTransition)}
+		case drive.UserStatus == string(models.StatusOptedOut):
+			return &botReply{Text: fmt.Sprintf("You have already withdrawn from *%s*.", drive.CompanyName)}
+		case drive.UserStatus != string(models.StatusOptedIn):
+			return &botReply{Text: fmt.Sprintf("You haven't applied to *%s*, nothing to withdraw.", drive.CompanyName)}
+		}
+	}
+
+	services.DefaultConversationStore.Set(from, services.ConversationState{
+		Action:    action,
+		DriveID:   driveID,
+		StudentID: studentID,
+		Reason:    reason,
+	})
+
+	verb := "Apply to"
+	if action == "withdraw" {
+		verb = "Withdraw from"
+	}
+	return &botReply{
+		Text: fmt.Sprintf("%s *%s* (%s)?\n📅 Deadline: %s",
+			verb, drive.CompanyName, drive.JobRole, drive.DeadlineDate.Format("02 Jan, 03:04 PM")),
+		Buttons: botConfirmButtons,
+	}
+}
+
+// executeBotAction performs a confirmed apply/withdraw
+func executeBotAction(ctx context.Context, state services.ConversationState) *botReply {
+	repo := repository.NewApplicationRepository(database.DB)
+
+	switch state.Action {
+	case "apply":
+		success, message, _, err := repo.ApplyForDrive(ctx, state.StudentID, state.DriveID)
+		if err == nil && !success {
+			return &botReply{Text: "❌ " + message}
+		}
+		if err != nil {
+			fmt.Printf("WhatsApp Bot: apply failed for student %d drive %d: %v %s\n", state.StudentID, state.DriveID, err, message)
+			return &botReply{Text: "❌ Could not apply right now. Please try again in the app."}
+		}
+		return &botReply{Text: "🎉 Applied successfully! Good luck."}
+
+	case "withdraw":
+		current, err := repo.WithdrawApplication(ctx, state.StudentID, state.DriveID, state.Reason)
+		if errors.Is(err, repository.ErrWithdrawalClosed) || errors.Is(err, repository.ErrInvalidTransition) ||
+			errors.Is(err, repository.ErrApplicationNotFound) {
+			if current == models.StatusShortlisted {
+				botWithdrawBlocked(ctx, state.StudentID, state.DriveID, state.Reason)
+			}
+			return &botReply{Text: "❌ " + withdrawErrorMessage(err)}
+		}
+		if err != nil {
+			fmt.Printf("WhatsApp Bot: withdraw failed for student %d drive %d: %v\n", state.StudentID, state.DriveID, err)
+			return &botReply{Text: "❌ Could not withdraw right now. Please try again in the app."}
+		}
+		return &botReply{Text: "✅ You have been withdrawn from the drive."}
+	}
+
+	return nil
+}
+
+// botWithdrawBlocked records a shortlisted student's withdrawal attempt and alerts the admins
+func botWithdrawBlocked(ctx context.Context, studentID, driveID int64, reason string) {
+	entry := models.AuditLog{
+		ActorID:   studentID,
+		ActorRole: "student",
+		Action:    models.AuditWithdrawBlocked,
+		DriveID:   &driveID,
+		StudentID: &studentID,
+		Details:   map[string]interface{}{"status": models.StatusShortlisted, "reason": reason, "channel": "whatsapp"},
+	}
+	if err := repository.NewAuditRepository(database.DB).Log(ctx, entry); err != nil {
+		fmt.Printf("Audit Error: Failed to record %s by %d: %v\n", entry.Action, studentID, err)
+	}
+	go notifyAdminsWithdrawalAttempt(driveID, studentID, reason)
+}
+
+func botStatusLabel(status string) string {
+	switch status {
+	case "opted_in":
+		return "Applied"
+	case "opted_out":
+		return "Withdrawn"
+	case "shortlisted":
+		return "Shortlisted 🎯"
+	case "placed":
+		return "Placed 🎉"
+	case "rejected":
+		return "Not selected"
+	case "removed":
+		return "Removed"
+	default:
+		return "Not applied"
+	}
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/services"
+	"github.com/SysSyncer/placement-portal-kec/internal/worker"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListWhatsAppTemplates returns the event -> template registry
+// @Summary List WhatsApp Templates
+// @Description Get the WhatsApp template configured for every portal event, plus the drive fields usable as parameters
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/whatsapp/templates [get]
+func ListWhatsAppTemplates(c *fiber.Ctx) error {
+	repo := repository.NewWhatsAppTemplateRepository(database.DB)
+	configured, err := repo.ListTemplates(c.Context())
+	if err != nil {
+		fmt.Printf("Error fetching whatsapp templates: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch templates"})
+	}
+
+	// Fill in defaults for events an admin hasn't configured yet
+	byEvent := map[string]models.WhatsAppTemplate{}
+	for _, t := range configured {
+		byEvent[t.Event] = t
+	}
+	templates := []models.WhatsAppTemplate{}
+	for _, event := range []string{
+		models.WhatsAppEventNewDrive, models.WhatsAppEventDeadlineReminder,
+		models.WhatsAppEventShortlisted, models.WhatsAppEventOffer,
+	} {
+		if t, ok := byEvent[event]; ok {
+			templates = append(templates, t)
+		} else {
+			templates = append(templates, services.DefaultWhatsAppTemplates[event])
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"templates":            templates,
+		"available_parameters": services.TemplateParameterKeys(),
+	})
+}
+
+// UpdateWhatsAppTemplate maps a portal event to an approved template
+// @Summary Update WhatsApp Template
+// @Description Configure which approved template (and which drive fields as parameters) is sent for an event
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param event path string true "Event" Enums(new_drive, deadline_reminder, shortlisted, offer)
+// @Param template body models.UpdateWhatsAppTemplateInput true "Template Mapping"
+// @Success 200 {object} models.WhatsAppTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/whatsapp/templates/{event} [put]
+func UpdateWhatsAppTemplate(c *fiber.Ctx) error {
+	event := c.Params("event")
+	if !services.IsValidWhatsAppEvent(event) {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid event. Must be one of: new_drive, deadline_reminder, shortlisted, offer"})
+	}
+
+	var input models.UpdateWhatsAppTemplateInput
+	if err := c.BodyParser(&input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid input"})
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(input); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Validation failed", "details": err.Error()})
+	}
+
+	if err := services.ValidateTemplateParameters(input.Parameters); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error(), "available_parameters": services.TemplateParameterKeys()})
+	}
+
+	tpl := models.WhatsAppTemplate{
+		Event:        event,
+		TemplateName: input.TemplateName,
+		LanguageCode: input.LanguageCode,
+		Parameters:   input.Parameters,
+		IsActive:     true,
+	}
+	if tpl.LanguageCode == "" {
+		tpl.LanguageCode = "en_US"
+	}
+	if input.IsActive != nil {
+		tpl.IsActive = *input.IsActive
+	}
+
+	repo := repository.NewWhatsAppTemplateRepository(database.DB)
+	saved, err := repo.UpsertTemplate(c.Context(), tpl)
+	if err != nil {
+		fmt.Printf("Error saving whatsapp template: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to save template"})
+	}
+
+	return c.JSON(saved)
+}
+
+// notifyApplicationStatusWhatsApp sends the shortlist/offer template to a single student (Async helper)
+func notifyApplicationStatusWhatsApp(driveID, studentID int64, status string) {
+	event := ""
+	switch status {
+	case "shortlisted":
+		event = models.WhatsAppEventShortlisted
+	case "placed":
+		event = models.WhatsAppEventOffer
+	default:
+		return
+	}
+
+	ctx := context.Background()
+	tpl := worker.ResolveWhatsAppTemplate(ctx, event)
+	if tpl == nil {
+		return
+	}
+
+	drive, err := repository.NewDriveRepository(database.DB).GetDriveByID(ctx, driveID)
+	if err != nil {
+		fmt.Printf("WhatsApp Error: Drive %d not found: %v\n", driveID, err)
+		return
+	}
+
+	mobile, err := repository.NewUserRepository(database.DB).GetStudentMobileNumber(ctx, studentID)
+	if err != nil {
+		fmt.Printf("WhatsApp: Skipping student %d: %v\n", studentID, err)
+		return
+	}
+
+	count, results := services.NewWhatsAppService().BroadcastDriveTemplate([]string{mobile}, *tpl, *drive)
+	fmt.Printf("WhatsApp Cloud: Sent %s template to %d/1 students.\n", tpl.TemplateName, count)
+	recordWhatsAppResults(event, tpl.TemplateName, driveID, results)
+}
+
+// recordWhatsAppResults stores delivery records for a broadcast so status webhooks can update them
+func recordWhatsAppResults(event, templateName string, driveID int64, results []services.BroadcastResult) {
+	repo := repository.NewWhatsAppMessageRepository(database.DB)
+	messages := services.BroadcastMessages(event, templateName, &driveID, results)
+	if err := repo.RecordMessages(context.Background(), messages); err != nil {
+		fmt.Printf("WhatsApp Error: Failed to record %d delivery records: %v\n", len(messages), err)
+	}
+}
+
+// GetDriveWhatsAppDeliveries lists per-recipient WhatsApp delivery status for a drive
+// @Summary Get Drive WhatsApp Deliveries
+// @Description List WhatsApp messages sent for a drive with their latest delivery status (sent/delivered/read/failed)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Drive ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /v1/admin/drives/{id}/whatsapp-deliveries [get]
+func GetDriveWhatsAppDeliveries(c *fiber.Ctx) error {
+	driveID, err := strconv.ParseInt(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Invalid Drive ID"})
+	}
+
+	repo := repository.NewWhatsAppMessageRepository(database.DB)
+	messages, summary, err := repo.GetDriveDeliveries(c.Context(), driveID)
+	if err != nil {
+		fmt.Printf("Error fetching whatsapp deliveries: %v\n", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to fetch deliveries"})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":    messages,
+		"summary": summary,
+	})
+}
@@ -1,14 +1,21 @@
 package middleware
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
 	"github.com/SysSyncer/placement-portal-kec/internal/utils"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// Protected ensures the user is logged in
+// recruiterPathPrefix is the only part of the API a recruiter token may reach
+const recruiterPathPrefix = "/api/v1/recruiter/"
+
+// Protected ensures the user is logged in.
+// Recruiter tokens are only accepted under /api/v1/recruiter, so they can't reach student or shared routes.
 func Protected(c *fiber.Ctx) error {
 	// 1. Get Token from Header (Authorization: Bearer <token>)
 	authHeader := c.Get("Authorization")
@@ -32,6 +39,10 @@ func Protected(c *fiber.Ctx) error {
 	c.Locals("user_id", claims["user_id"])
 	c.Locals("role", claims["role"])
 
+	if role, _ := claims["role"].(string); role == "recruiter" && !strings.HasPrefix(c.Path(), recruiterPathPrefix) {
+		return c.Status(403).JSON(fiber.Map{"error": "Forbidden: Recruiters can only use the recruiter portal"})
+	}
+
 	return c.Next()
 }
 
@@ -43,3 +54,35 @@ func AdminOnly(c *fiber.Ctx) error {
 	}
 	return c.Next()
 }
+
+// CoordinatorOnly allows drive-day staff: coordinators and admins
+func CoordinatorOnly(c *fiber.Ctx) error {
+	role := c.Locals("role").(string)
+	if role != "coordinator" && role != "admin" {
+		return c.Status(403).JSON(fiber.Map{"error": "Forbidden: Coordinators only"})
+	}
+	return c.Next()
+}
+
+// RecruiterOnly allows company HR accounts and stores their company in c.Locals("company_id").
+// The company is looked up per request, so moving or deleting a recruiter takes effect immediately.
+func RecruiterOnly(c *fiber.Ctx) error {
+	role := c.Locals("role").(string)
+	if role != "recruiter" {
+		return c.Status(403).JSON(fiber.Map{"error": "Forbidden: Recruiters only"})
+	}
+
+	userID := int64(c.Locals("user_id").(float64))
+	recruiter, err := repository.NewRecruiterRepository(database.DB).GetRecruiter(c.Context(), userID)
+	if err != nil {
+		fmt.Printf("Error fetching recruiter %d: %v\n", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Failed to load recruiter account"})
+	}
+	if recruiter == nil || recruiter.IsBlocked {
+		return c.Status(403).JSON(fiber.Map{"error": "Forbidden: Recruiter account is not active"})
+	}
+
+	c.Locals("company_id", recruiter.CompanyID)
+	c.Locals("recruiter", recruiter)
+	return c.Next()
+}
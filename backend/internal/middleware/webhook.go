@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// WebhookSource describes how a webhook sender authenticates its requests
+type WebhookSource struct {
+	Name            string // Used as the replay-protection namespace
+	SecretEnv       string // Env var holding the shared HMAC secret (or URL token)
+	SignatureHeader string // Header carrying the hex HMAC-SHA256 signature
+	SignaturePrefix string // e.g. "sha256=" (stripped before comparing)
+	TimestampHeader string // Unix seconds; empty = sender doesn't send one (signature covers body only)
+	TokenQuery      string // For senders that can't sign: query parameter carrying the secret instead
+}
+
+var (
+	// WhatsAppWebhookSource: Meta signs the raw body with the App Secret
+	WhatsAppWebhookSource = WebhookSource{
+		Name:            "whatsapp",
+		SecretEnv:       "WHATSAPP_APP_SECRET",
+		SignatureHeader: "X-Hub-Signature-256",
+		SignaturePrefix: "sha256=",
+	}
+	// RailwayWebhookSource: Railway doesn't sign webhooks, so the secret goes in the
+	// webhook URL configured in the Railway dashboard (.../webhooks/railway?token=<secret>)
+	RailwayWebhookSource = WebhookSource{
+		Name:       "railway",
+		SecretEnv:  "RAILWAY_WEBHOOK_SECRET",
+		TokenQuery: "token",
+	}
+	// GenericWebhookSource: signature over "<timestamp>.<body>" for any other integration
+	GenericWebhookSource = WebhookSource{
+		Name:            "generic",
+		SecretEnv:       "GENERIC_WEBHOOK_SECRET",
+		SignatureHeader: "X-Webhook-Signature",
+		SignaturePrefix: "sha256=",
+		TimestampHeader: "X-Webhook-Timestamp",
+	}
+)
+
+// webhookSources is every source VerifyWebhook is used with
+var webhookSources = []WebhookSource{WhatsAppWebhookSource, RailwayWebhookSource, GenericWebhookSource}
+
+// WebhookTimestampTolerance is how far a signed timestamp may drift from our clock
+// (WEBHOOK_TIMESTAMP_TOLERANCE_SECONDS, default 5 minutes)
+func WebhookTimestampTolerance() time.Duration {
+	if v, err := strconv.Atoi(os.Getenv("WEBHOOK_TIMESTAMP_TOLERANCE_SECONDS")); err == nil && v > 0 {
+		return time.Duration(v) * time.Second
+	}
+	return 5 * time.Minute
+}
+
+// ExpiringWebhookSources names the sources whose delivery keys can be purged: their signed
+// timestamp stops a replay once it is outside the tolerance. Senders without one (WhatsApp,
+// Railway) could replay a captured payload at any time, so their keys are never purged.
+func ExpiringWebhookSources() []string {
+	var names []string
+	for _, src := range webhookSources {
+		if src.TimestampHeader != "" {
+			names = append(names, src.Name)
+		}
+	}
+	return names
+}
+
+// VerifyWebhook rejects webhook requests that aren't signed with (or don't carry) the source's
+// secret, are outside the timestamp tolerance, or replay an already processed delivery.
+// GET requests pass through (WhatsApp's subscription handshake is checked by the handler).
+func VerifyWebhook(src WebhookSource) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() == fiber.MethodGet {
+			return c.Next()
+		}
+
+		// 1. Fail closed when the secret isn't configured
+		secret := os.Getenv(src.SecretEnv)
+		if secret == "" {
+			fmt.Printf("Webhook [%s]: %s is not set, rejecting request\n", src.Name, src.SecretEnv)
+			return c.Status(503).JSON(fiber.Map{"error": "Webhook verification not configured"})
+		}
+
+		body := c.Body()
+
+		// 2. Timestamp tolerance (blocks replays of old captured requests)
+		timestamp := ""
+		if src.TimestampHeader != "" {
+			timestamp = c.Get(src.TimestampHeader)
+			secs, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				return c.Status(401).JSON(fiber.Map{"error": "Unauthorized: Missing or invalid webhook timestamp"})
+			}
+			if math.Abs(time.Since(time.Unix(secs, 0)).Seconds()) > WebhookTimestampTolerance().Seconds() {
+				return c.Status(401).JSON(fiber.Map{"error": "Unauthorized: Webhook timestamp outside tolerance"})
+			}
+		}
+
+		// 3. Signature (or URL token for senders that can't sign)
+		if src.TokenQuery != "" {
+			if subtle.ConstantTimeCompare([]byte(c.Query(src.TokenQuery)), []byte(secret)) != 1 {
+				fmt.Printf("Webhook [%s]: Invalid token from %s\n", src.Name, c.IP())
+				return c.Status(401).JSON(fiber.Map{"error": "Unauthorized: Invalid webhook token"})
+			}
+		} else {
+			signature := strings.TrimPrefix(strings.TrimSpace(c.Get(src.SignatureHeader)), src.SignaturePrefix)
+			if signature == "" {
+				return c.Status(401).JSON(fiber.Map{"error": "Unauthorized: Missing webhook signature"})
+			}
+			if !validWebhookSignature(secret, timestamp, body, signature) {
+				fmt.Printf("Webhook [%s]: Invalid signature from %s\n", src.Name, c.IP())
+				return c.Status(401).JSON(fiber.Map{"error": "Unauthorized: Invalid webhook signature"})
+			}
+		}
+
+		// 4. Replay protection: every signed payload is processed at most once. The key is
+		// derived only from what the secret covers, so unsigned headers can't mint new keys.
+		deliveryID := webhookDeliveryKey(timestamp, body)
+
+		repo := repository.NewWebhookDeliveryRepository(database.DB)
+		isNew, err := repo.RecordDelivery(c.Context(), src.Name, deliveryID)
+		if err != nil {
+			fmt.Printf("Webhook [%s]: Failed to record delivery %s: %v\n", src.Name, deliveryID, err)
+			return c.Status(500).JSON(fiber.Map{"error": "Failed to record webhook delivery"})
+		}
+		if !isNew {
+			// 200 so the sender stops retrying, but don't process it again
+			return c.Status(200).JSON(fiber.Map{"message": "Duplicate delivery ignored"})
+		}
+
+		// 5. Let the sender retry if we failed to process it
+		err = c.Next()
+		if err != nil || c.Response().StatusCode() >= 500 {
+			if relErr := repo.ReleaseDelivery(c.Context(), src.Name, deliveryID); relErr != nil {
+				fmt.Printf("Webhook [%s]: Failed to release delivery %s: %v\n", src.Name, deliveryID, relErr)
+			}
+		}
+		return err
+	}
+}
+
+// webhookDeliveryKey is the SHA-256 of the signed payload, "<timestamp>.<body>" (or just body)
+func webhookDeliveryKey(timestamp string, body []byte) string {
+	h := sha256.New()
+	if timestamp != "" {
+		h.Write([]byte(timestamp + "."))
+	}
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// validWebhookSignature compares the hex HMAC-SHA256 of "<timestamp>.<body>" (or just body) in constant time
+func validWebhookSignature(secret, timestamp string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(strings.ToLower(signature))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	if timestamp != "" {
+		mac.Write([]byte(timestamp + "."))
+	}
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
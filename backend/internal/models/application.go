@@ -0,0 +1,195 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ApplicationStatus is drive_applications.status
+type ApplicationStatus string
+
+const (
+	StatusEligible    ApplicationStatus = "eligible"    // Notified, hasn't responded
+	StatusOptedIn     ApplicationStatus = "opted_in"    // Applied
+	StatusOptedOut    ApplicationStatus = "opted_out"   // Withdrew
+	StatusShortlisted ApplicationStatus = "shortlisted" // Cleared a round, still in the process
+	StatusRejected    ApplicationStatus = "rejected"
+	StatusPlaced      ApplicationStatus = "placed"
+	StatusRemoved     ApplicationStatus = "removed" // Taken off the drive by an admin
+)
+
+// applicationTransitions lists the statuses each status may move to. "" is a new
+// application. Anything else (e.g. reopening a rejection) needs an admin override.
+var applicationTransitions = map[ApplicationStatus][]ApplicationStatus{
+	"":                {StatusEligible, StatusOptedIn, StatusOptedOut},
+	StatusEligible:    {StatusOptedIn, StatusOptedOut, StatusRemoved},
+	StatusOptedIn:     {StatusOptedOut, StatusShortlisted, StatusRejected, StatusPlaced, StatusRemoved},
+	StatusOptedOut:    {StatusOptedIn, StatusRemoved},                // Re-applying, only while the drive is open (see ApplyForDrive)
+	StatusShortlisted: {StatusRejected, StatusPlaced, StatusRemoved}, // No withdrawing once shortlisted
+	StatusRejected:    {},
+	StatusPlaced:      {},
+	StatusRemoved:     {},
+}
+
+// Valid reports whether s is a known status
+func (s ApplicationStatus) Valid() bool {
+	_, ok := applicationTransitions[s]
+	return ok && s != ""
+}
+
+// CanMoveTo reports whether the transition graph allows s -> to. Staying put is always allowed.
+func (s ApplicationStatus) CanMoveTo(to ApplicationStatus) bool {
+	if s == to {
+		return true
+	}
+	for _, next := range applicationTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplicationStatusesTo lists the existing statuses that may move to to
+func ApplicationStatusesTo(to ApplicationStatus) []string {
+	var from []string
+	for s := range applicationTransitions {
+		if s != "" && s.CanMoveTo(to) {
+			from = append(from, string(s))
+		}
+	}
+	return from
+}
+
+// Round progress states on the student dashboard
+const (
+	RoundPending   = "pending"   // Nothing scheduled yet
+	RoundScheduled = "scheduled" // Interview slot booked
+	RoundAttended  = "attended"  // Checked in on drive day
+	RoundMissed    = "missed"    // Recorded as a no-show
+)
+
+// Timeline event types
+const (
+	ApplicationEventApplied       = "applied"
+	ApplicationEventSlotBooked    = "slot_booked"
+	ApplicationEventCheckedIn     = "checked_in"
+	ApplicationEventNoShow        = "no_show"
+	ApplicationEventStatusChanged = "status_changed"
+)
+
+// ApplicationRound is the student's progress through one round of a drive
+type ApplicationRound struct {
+	RoundIndex  int        `json:"round_index"` // Position in PlacementDrive.Rounds
+	Name        string     `json:"name"`
+	Date        string     `json:"date"`
+	State       string     `json:"state"` // pending, scheduled, attended, missed
+	SlotStart   *time.Time `json:"slot_start,omitempty"`
+	SlotEnd     *time.Time `json:"slot_end,omitempty"`
+	Location    string     `json:"location,omitempty"`
+	MeetingLink string     `json:"meeting_link,omitempty"`
+	CheckedInAt *time.Time `json:"checked_in_at,omitempty"`
+}
+
+// ApplicationOffer is set once a student is placed or an offer is recorded
+type ApplicationOffer struct {
+	PackageOffered int64  `json:"package_offered"` // 0 = not recorded, see the drive's ctc_display
+	CtcDisplay     string `json:"ctc_display"`
+	OfferLetterURL string `json:"offer_letter_url"` // Presigned in the student response
+}
+
+// Who changed an application's status
+const (
+	StatusActorStudent     = "student"
+	StatusActorAdmin       = "admin"
+	StatusActorCoordinator = "coordinator"
+	StatusActorRecruiter   = "recruiter"
+	StatusActorSystem      = "system" // Jobs and backfills
+)
+
+// StatusActor is the user behind a status change; UserID 0 means the system
+type StatusActor struct {
+	UserID int64
+	Role   string
+}
+
+// ApplicationStatusChange is one row of application_status_history
+type ApplicationStatusChange struct {
+	ID             int64     `json:"id"`
+	DriveID        int64     `json:"drive_id"`
+	StudentID      int64     `json:"student_id"`
+	FromStatus     string    `json:"from_status"` // "" for the first application
+	ToStatus       string    `json:"to_status"`
+	ChangedBy      *int64    `json:"changed_by"`
+	ChangedByEmail string    `json:"changed_by_email,omitempty"` // Admin view only
+	ActorRole      string    `json:"actor_role"`                 // student, admin, coordinator, recruiter, system
+	Remark         string    `json:"remark"`
+	IsOverride     bool      `json:"is_override"` // Admin forced a move the transition graph doesn't allow
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ApplicationEvent is one entry of an application's timeline
+type ApplicationEvent struct {
+	Type       string    `json:"type"` // applied, slot_booked, checked_in, no_show, status_changed
+	Status     string    `json:"status,omitempty"`
+	RoundIndex *int      `json:"round_index,omitempty"`
+	Label      string    `json:"label"`
+	ActorRole  string    `json:"actor_role,omitempty"` // Status changes only
+	Remark     string    `json:"remark,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// StudentApplication is one drive on a student's placement dashboard
+type StudentApplication struct {
+	DriveID         int64       `json:"drive_id"`
+	CompanyName     string      `json:"company_name"`
+	JobRole         string      `json:"job_role"`
+	Location        string      `json:"location"`
+	DriveType       string      `json:"drive_type"`
+	CompanyCategory string      `json:"company_category"`
+	LogoURL         string      `json:"logo_url"`
+	DriveDate       pgtype.Date `json:"drive_date" swaggertype:"string" example:"2026-05-20"`
+	DeadlineDate    time.Time   `json:"deadline_date"`
+	DriveStatus     string      `json:"drive_status"`
+
+	Status    string    `json:"status"` // Application status: opted_in, shortlisted, placed, ...
+	AppliedAt time.Time `json:"applied_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Rounds   []ApplicationRound `json:"rounds"`
+	Offer    *ApplicationOffer  `json:"offer"`
+	Timeline []ApplicationEvent `json:"timeline"` // Oldest first
+}
+
+// ApplicationSummary counts a student's applications by outcome
+type ApplicationSummary struct {
+	Applied     int `json:"applied"` // Every application the student hasn't withdrawn
+	Shortlisted int `json:"shortlisted"`
+	Placed      int `json:"placed"`
+	Rejected    int `json:"rejected"`
+	Withdrawn   int `json:"withdrawn"`
+}
+
+// StudentApplicationDashboard is the student's placement journey in one response
+type StudentApplicationDashboard struct {
+	Summary      ApplicationSummary   `json:"summary"`
+	Applications []StudentApplication `json:"applications"`
+}
+
+// BulkStatusInput moves many applicants of one drive to the same status.
+// Students are identified by student_ids and/or register_numbers.
+type BulkStatusInput struct {
+	Status          string   `json:"status" validate:"required,oneof=shortlisted rejected placed"`
+	StudentIDs      []int64  `json:"student_ids"`
+	RegisterNumbers []string `json:"register_numbers"`
+	Remark          string   `json:"remark" validate:"max=500"` // Recorded in the status history
+	Notify          bool     `json:"notify"`                    // WhatsApp + email the students whose status changed
+	AllOrNothing    bool     `json:"all_or_nothing"`            // Change nothing if any row is rejected
+	Override        bool     `json:"override"`                  // Allow moves outside the transition graph; needs a remark
+}
+
+// WithdrawInput is a student's opt-out from a drive
+type WithdrawInput struct {
+	Reason string `json:"reason" validate:"required,min=5,max=500"` // Recorded in the status history
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// CheckInInput is a QR scan by a coordinator
+type CheckInInput struct {
+	Token      string `json:"token" validate:"required"`
+	DriveID    int64  `json:"drive_id" validate:"required"` // The drive being run, so a QR for another drive is rejected
+	RoundIndex int    `json:"round_index" validate:"min=0"` // Position in PlacementDrive.Rounds
+}
+
+// AttendanceRecord is a student's presence (or absence) at a drive round
+type AttendanceRecord struct {
+	StudentID      int64      `json:"student_id"`
+	FullName       string     `json:"full_name"`
+	RegisterNumber string     `json:"register_number"`
+	Department     string     `json:"department"`
+	Status         string     `json:"status"` // Application status
+	Present        bool       `json:"present"`
+	CheckedInAt    *time.Time `json:"checked_in_at"`
+	CheckedInBy    *int64     `json:"checked_in_by"`
+}
+
+// NoShow is a recorded absence; while not waived it blocks the student from the next PenaltyDrives drives
+type NoShow struct {
+	DriveID        int64     `json:"drive_id"`
+	CompanyName    string    `json:"company_name"`
+	StudentID      int64     `json:"student_id"`
+	FullName       string    `json:"full_name"`
+	RegisterNumber string    `json:"register_number"`
+	RoundIndex     int       `json:"round_index"`
+	PenaltyDrives  int       `json:"penalty_drives"`
+	Waived         bool      `json:"waived"`
+	RecordedAt     time.Time `json:"recorded_at"`
+}
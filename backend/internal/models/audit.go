@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Audit actions
+const (
+	AuditRecruiterLogin          = "recruiter.login"
+	AuditRecruiterApplicantsView = "recruiter.applicants_view"
+	AuditRecruiterApplicantsCSV  = "recruiter.applicants_download"
+	AuditRecruiterResumeView     = "recruiter.resume_view"
+	AuditRecruiterResultsUpload  = "recruiter.results_upload"
+	AuditRecruiterOfferUpload    = "recruiter.offer_upload"
+	AuditRecruiterCreated        = "admin.recruiter_create"
+	AuditRecruiterDeleted        = "admin.recruiter_delete"
+	AuditWithdrawBlocked         = "student.withdraw_blocked" // Shortlisted student tried to withdraw
+)
+
+// AuditLog records who did what to which drive/student
+type AuditLog struct {
+	ID        int64                  `json:"id"`
+	ActorID   int64                  `json:"actor_id"`
+	ActorRole string                 `json:"actor_role"`
+	ActorName string                 `json:"actor_name,omitempty"` // Email, for display
+	CompanyID *int64                 `json:"company_id"`
+	Action    string                 `json:"action"`
+	DriveID   *int64                 `json:"drive_id"`
+	StudentID *int64                 `json:"student_id"`
+	Details   map[string]interface{} `json:"details"`
+	IPAddress string                 `json:"ip_address"`
+	CreatedAt time.Time              `json:"created_at"`
+}
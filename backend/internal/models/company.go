@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Company is a recruiter. Drives keep the company name as typed and point here through CompanyID.
+type Company struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Domain    string    `json:"domain"` // Normalized, e.g. "tcs.com"
+	LogoURL   string    `json:"logo_url"`
+	Category  string    `json:"category"` // 'Core', 'IT', 'Service', 'Product', 'Start-up', 'MNC'
+	Aliases   []string  `json:"aliases"`  // Normalized spellings that resolve to this company
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	DriveCount    int         `json:"drive_count"`                                               // Computed
+	LastDriveDate pgtype.Date `json:"last_drive_date" swaggertype:"string" example:"2026-05-20"` // Computed
+	Spocs         []Spoc      `json:"spocs,omitempty"`                                           // Detail view only
+}
+
+// CompanyInput creates or updates a company. Nil slices leave aliases / SPOCs unchanged on update.
+type CompanyInput struct {
+	Name     string   `json:"name" validate:"required,max=150"`
+	Domain   string   `json:"domain"`
+	LogoURL  string   `json:"logo_url"`
+	Category string   `json:"category" validate:"omitempty,oneof=Core IT Service Non-Tech Product Start-up MNC"`
+	Aliases  []string `json:"aliases"`  // Other spellings, e.g. "TCS"
+	SpocIDs  []int64  `json:"spoc_ids"` // Replaces the linked SPOCs
+}
+
+// MergeCompaniesInput folds duplicate companies into the one in the URL
+type MergeCompaniesInput struct {
+	SourceIDs []int64 `json:"source_ids" validate:"required,min=1"`
+}
+
+// CompanyDriveStats is one drive in a company's history
+type CompanyDriveStats struct {
+	DriveID     int64       `json:"drive_id"`
+	CompanyName string      `json:"company_name"` // As typed on the drive
+	JobRole     string      `json:"job_role"`
+	DriveType   string      `json:"drive_type"`
+	Status      string      `json:"status"`
+	DriveDate   pgtype.Date `json:"drive_date" swaggertype:"string" example:"2026-05-20"`
+	Year        int         `json:"year"` // Drive date year, or posting year when the date is unset
+	CtcMin      int64       `json:"ctc_min"`
+	CtcMax      int64       `json:"ctc_max"`
+	CtcDisplay  string      `json:"ctc_display"`
+	Applicants  int         `json:"applicants"`
+	Shortlisted int         `json:"shortlisted"`
+	Offers      int         `json:"offers"`    // Students placed
+	AvgOffer    int64       `json:"avg_offer"` // Mean package_offered (falls back to ctc_max) of placed students
+	MaxOffer    int64       `json:"max_offer"`
+}
+
+// CompanyYearStats aggregates a company's drives in one year (the CTC trend)
+type CompanyYearStats struct {
+	Year       int   `json:"year"`
+	Drives     int   `json:"drives"`
+	Applicants int   `json:"applicants"`
+	Offers     int   `json:"offers"`
+	AvgCtc     int64 `json:"avg_ctc"`   // Mean ctc_max of drives that published one
+	AvgOffer   int64 `json:"avg_offer"` // Mean package of offers made
+	MaxOffer   int64 `json:"max_offer"`
+}
+
+// CompanyHistory is everything a company has done with the college
+type CompanyHistory struct {
+	Company     *Company            `json:"company"`
+	TotalDrives int                 `json:"total_drives"`
+	TotalOffers int                 `json:"total_offers"`
+	Years       []CompanyYearStats  `json:"years"`  // Oldest first
+	Drives      []CompanyDriveStats `json:"drives"` // Newest first
+}
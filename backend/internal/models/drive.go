@@ -10,6 +10,7 @@ type PlacementDrive struct {
 	ID             int64  `json:"id"`
 	PostedBy       int64  `json:"posted_by"`
 	CompanyName    string `json:"company_name"`
+	CompanyID      *int64 `json:"company_id"` // Companies master, see Company
 	JobRole        string `json:"job_role"`
 	JobDescription string `json:"job_description"`
 	Location       string `json:"location"`
@@ -19,7 +20,9 @@ type PlacementDrive struct {
 	// Filters & Categories
 	DriveType       string `json:"drive_type"`       // 'Full-Time', 'Internship', 'Freelance', 'Part-Time'
 	CompanyCategory string `json:"company_category"` // 'Core', 'IT', 'Service', 'Product', 'Start-up', 'MNC'
-	SpocID          int64  `json:"spoc_id"`          // [NEW] Single Point Of Contact ID
+	SpocID          int64  `json:"spoc_id"`          // [NEW] Single Point Of Contact ID (the primary contact)
+
+	Spocs []DriveSpocDetail `json:"spocs"` // All SPOCs on the drive (drive_spocs)
 
 	// Financials
 	CtcMin     int64  `json:"ctc_min"`
@@ -70,7 +73,8 @@ type Attachment struct {
 
 // Input struct for Creating a Drive
 type CreateDriveInput struct {
-	CompanyName     string `json:"company_name" validate:"required"`
+	CompanyName     string `json:"company_name" validate:"required_without=CompanyID"`
+	CompanyID       int64  `json:"company_id"` // Optional: pick an existing company instead of matching by name
 	JobRole         string `json:"job_role" validate:"required"`
 	JobDescription  string `json:"job_description"`
 	Location        string `json:"location"`
@@ -78,7 +82,10 @@ type CreateDriveInput struct {
 	LogoURL         string `json:"logo_url"` // [NEW]
 	DriveType       string `json:"drive_type"`
 	CompanyCategory string `json:"company_category"`
-	SpocID          int64  `json:"spoc_id"` // [NEW]
+	SpocID          int64  `json:"spoc_id"` // [NEW] Single SPOC; ignored when Spocs is sent
+
+	// SPOCs on the drive, at most one with is_primary_contact (defaults to the first)
+	Spocs []DriveSpoc `json:"spocs"`
 
 	CtcMin     int64  `json:"ctc_min"`
 	CtcMax     int64  `json:"ctc_max"`
@@ -111,7 +118,9 @@ type CreateDriveInput struct {
 
 // ManualRegisterInput defines the input for admin adding a student to a drive
 type ManualRegisterInput struct {
-	StudentID int64 `json:"student_id"`
+	StudentID int64  `json:"student_id"`
+	Remark    string `json:"remark"`   // Recorded in the status history
+	Override  bool   `json:"override"` // Reactivate an application the transition graph won't move to opted_in; needs a remark
 }
 
 type DriveApplicant struct {
@@ -125,3 +134,51 @@ type DriveApplicant struct {
 	ResumeURL      string  `json:"resume_url"`
 	AppliedAt      string  `json:"applied_at"`
 }
+
+// DriveSearchQuery filters a drive search. List filters match any of their values;
+// different filters are ANDed.
+type DriveSearchQuery struct {
+	Q                 string   // Full-text over company, role, description and location
+	DriveTypes        []string // e.g. "Full-Time", "Internship"
+	CompanyCategories []string // e.g. "IT", "Core"
+	Locations         []string // Case-insensitive
+	CtcRanges         []string // Keys of DriveCtcRanges, e.g. "6-10"
+	CtcMin            int64    // ctc_max at least this (per annum)
+	Statuses          []string // Admin only; students always see open drives
+	Sort              string   // 'relevance' (default with Q), 'deadline' (default), 'ctc', 'newest'
+	Page              int
+	Limit             int
+}
+
+// DriveCtcRange is a CTC facet bucket in rupees per annum; Max 0 means no upper bound
+type DriveCtcRange struct {
+	Key   string
+	Label string
+	Min   int64
+	Max   int64
+}
+
+// DriveCtcRanges are the CTC facet buckets, matched on a drive's ctc_max
+var DriveCtcRanges = []DriveCtcRange{
+	{Key: "0-3", Label: "Up to 3 LPA", Min: 0, Max: 300000},
+	{Key: "3-6", Label: "3 - 6 LPA", Min: 300000, Max: 600000},
+	{Key: "6-10", Label: "6 - 10 LPA", Min: 600000, Max: 1000000},
+	{Key: "10-20", Label: "10 - 20 LPA", Min: 1000000, Max: 2000000},
+	{Key: "20+", Label: "20 LPA +", Min: 2000000},
+}
+
+// FacetCount is one value of a search facet with the number of matching drives
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// DriveSearchFacets counts each facet with every other filter applied, so selecting
+// a value doesn't hide the alternatives in the same facet
+type DriveSearchFacets struct {
+	DriveType       []FacetCount `json:"drive_type"`
+	CompanyCategory []FacetCount `json:"company_category"`
+	Location        []FacetCount `json:"location"`
+	CtcRange        []FacetCount `json:"ctc_range"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+// How a student ended up in an interview slot
+const (
+	BookingSourceAuto    = "auto"    // Auto-allocated by an admin
+	BookingSourceStudent = "student" // Picked by the student
+)
+
+// InterviewSlot is a time window in which one panel interviews up to Capacity students for a drive round
+type InterviewSlot struct {
+	ID          int64     `json:"id"`
+	DriveID     int64     `json:"drive_id"`
+	RoundIndex  int       `json:"round_index"` // Position in PlacementDrive.Rounds
+	RoundName   string    `json:"round_name"`
+	PanelName   string    `json:"panel_name"`
+	PanelEmails []string  `json:"panel_emails"` // JSONB, panel members notified of their schedule
+	Location    string    `json:"location"`     // Room, e.g. "IT Park Hall 2"
+	MeetingLink string    `json:"meeting_link"` // For online interviews
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	Capacity    int       `json:"capacity"`
+	BookedCount int       `json:"booked_count"` // Computed
+
+	Bookings []InterviewBooking `json:"bookings,omitempty"` // Admin view only
+	IsMine   bool               `json:"is_mine,omitempty"`  // Student view: the student is booked in this slot
+}
+
+// InterviewBooking is a student assigned to a slot
+type InterviewBooking struct {
+	SlotID         int64     `json:"slot_id"`
+	StudentID      int64     `json:"student_id"`
+	FullName       string    `json:"full_name"`
+	RegisterNumber string    `json:"register_number"`
+	Email          string    `json:"email"`
+	Source         string    `json:"source"` // auto, student
+	BookedAt       time.Time `json:"booked_at"`
+}
+
+// StudentInterview is a booked slot as seen by the student
+type StudentInterview struct {
+	SlotID      int64     `json:"slot_id"`
+	DriveID     int64     `json:"drive_id"`
+	CompanyName string    `json:"company_name"`
+	JobRole     string    `json:"job_role"`
+	RoundIndex  int       `json:"round_index"`
+	RoundName   string    `json:"round_name"`
+	PanelName   string    `json:"panel_name"`
+	Location    string    `json:"location"`
+	MeetingLink string    `json:"meeting_link"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	Source      string    `json:"source"`
+	BookedAt    time.Time `json:"booked_at"`
+}
+
+// InterviewSlotInput defines a slot to create or update
+type InterviewSlotInput struct {
+	RoundIndex  int       `json:"round_index" validate:"min=0"`
+	PanelName   string    `json:"panel_name" validate:"required"`
+	PanelEmails []string  `json:"panel_emails" validate:"dive,email"`
+	Location    string    `json:"location"`
+	MeetingLink string    `json:"meeting_link" validate:"omitempty,url"`
+	StartTime   time.Time `json:"start_time" validate:"required"`
+	EndTime     time.Time `json:"end_time" validate:"required,gtfield=StartTime"`
+	Capacity    int       `json:"capacity" validate:"required,min=1"`
+}
+
+// CreateInterviewSlotsInput creates several slots at once (e.g. one per panel per hour)
+type CreateInterviewSlotsInput struct {
+	Slots []InterviewSlotInput `json:"slots" validate:"required,min=1,dive"`
+}
+
+// RoundInput selects a drive round by its position in PlacementDrive.Rounds
+type RoundInput struct {
+	RoundIndex int `json:"round_index" validate:"min=0"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Background job run outcomes
+const (
+	JobRunRunning   = "running"
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed" // Returned an error, panicked or timed out (see Error)
+)
+
+// How a job run was started
+const (
+	JobTriggerSchedule = "schedule"
+	JobTriggerManual   = "manual"
+)
+
+// JobRun is one execution of a scheduled background job
+type JobRun struct {
+	ID         int64      `json:"id"`
+	JobName    string     `json:"job_name"`
+	Trigger    string     `json:"trigger"` // schedule, manual
+	Status     string     `json:"status"`  // running, succeeded, failed
+	Result     string     `json:"result,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	Instance   string     `json:"instance"` // Host that ran the job
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	DurationMs *int64     `json:"duration_ms"`
+}
+
+// JobInfo describes a registered job for the admin API
+type JobInfo struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Schedule    string    `json:"schedule"` // Cron expression, e.g. "*/5 * * * *"
+	Timeout     string    `json:"timeout"`
+	NextRun     time.Time `json:"next_run"`
+	Running     bool      `json:"running"` // Running on this instance
+	LastRun     *JobRun   `json:"last_run"`
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Portal events that can be sent to outgoing webhook subscribers
+const (
+	PortalEventDrivePublished           = "drive.published"
+	PortalEventApplicationStatusChanged = "application.status_changed"
+	PortalEventStudentPlaced            = "student.placed"
+)
+
+// PortalEventTypes lists every event a subscription may listen to
+var PortalEventTypes = []string{
+	PortalEventDrivePublished,
+	PortalEventApplicationStatusChanged,
+	PortalEventStudentPlaced,
+}
+
+// Outgoing delivery states
+const (
+	WebhookDeliveryPending   = "pending"   // Waiting for its first attempt or a retry
+	WebhookDeliveryDelivered = "delivered" // Subscriber answered 2xx
+	WebhookDeliveryDead      = "dead"      // Gave up after MaxAttempts (dead letter), can be retried manually
+)
+
+// OutgoingWebhook is a subscription registered by an admin for another internal tool
+type OutgoingWebhook struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"` // e.g. "Alumni Tracker"
+	URL        string    `json:"url"`
+	Secret     string    `json:"-"` // HMAC key, never returned after creation
+	EventTypes []string  `json:"event_types"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type CreateOutgoingWebhookInput struct {
+	Name       string   `json:"name" validate:"required"`
+	URL        string   `json:"url" validate:"required,url"`
+	Secret     string   `json:"secret"` // Optional, generated if empty
+	EventTypes []string `json:"event_types" validate:"required,min=1"`
+	IsActive   *bool    `json:"is_active"`
+}
+
+// OutgoingWebhookDelivery is one event sent (or to be sent) to one subscription
+type OutgoingWebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhook_id"`
+	EventID        string          `json:"event_id"` // Shared by all deliveries of the same event
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status"` // pending, delivered, dead
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+
+	// Joined from the subscription when claimed for sending
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// SkillProficiencies is the ordered scale, lowest first. Admin search treats a
+// proficiency filter as a minimum.
+var SkillProficiencies = []string{"beginner", "intermediate", "advanced", "expert"}
+
+// StudentSkill is one skill on a student's profile
+type StudentSkill struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	Proficiency string    `json:"proficiency"` // 'beginner', 'intermediate', 'advanced', 'expert'
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type StudentSkillInput struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Proficiency string `json:"proficiency" validate:"omitempty,oneof=beginner intermediate advanced expert"` // Defaults to intermediate
+}
+
+// StudentProject is a personal, academic or hackathon project
+type StudentProject struct {
+	ID          int64       `json:"id"`
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	TechStack   []string    `json:"tech_stack"`
+	ProjectURL  string      `json:"project_url"`
+	RepoURL     string      `json:"repo_url"`
+	StartDate   pgtype.Date `json:"start_date" swaggertype:"string" example:"2025-01-15"`
+	EndDate     pgtype.Date `json:"end_date" swaggertype:"string" example:"2025-04-30"` // null while ongoing
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+type StudentProjectInput struct {
+	Title       string   `json:"title" validate:"required,max=150"`
+	Description string   `json:"description"`
+	TechStack   []string `json:"tech_stack" validate:"max=30,dive,required,max=50"`
+	ProjectURL  string   `json:"project_url" validate:"omitempty,url"`
+	RepoURL     string   `json:"repo_url" validate:"omitempty,url"`
+	StartDate   string   `json:"start_date"` // YYYY-MM-DD, optional
+	EndDate     string   `json:"end_date"`   // YYYY-MM-DD, empty while ongoing
+}
+
+// StudentInternship is an internship with its company and duration
+type StudentInternship struct {
+	ID             int64       `json:"id"`
+	CompanyName    string      `json:"company_name"`
+	Role           string      `json:"role"`
+	Location       string      `json:"location"`
+	StartDate      pgtype.Date `json:"start_date" swaggertype:"string" example:"2025-05-01"`
+	EndDate        pgtype.Date `json:"end_date" swaggertype:"string" example:"2025-07-31"` // null while ongoing
+	DurationMonths int         `json:"duration_months"`                                    // Whole months, up to today when ongoing
+	Stipend        float64     `json:"stipend"`
+	Description    string      `json:"description"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+type StudentInternshipInput struct {
+	CompanyName string  `json:"company_name" validate:"required,max=150"`
+	Role        string  `json:"role" validate:"required,max=150"`
+	Location    string  `json:"location" validate:"max=100"`
+	StartDate   string  `json:"start_date" validate:"required"` // YYYY-MM-DD
+	EndDate     string  `json:"end_date"`                       // YYYY-MM-DD, empty while ongoing
+	Stipend     float64 `json:"stipend" validate:"min=0"`
+	Description string  `json:"description"`
+}
+
+// StudentCertification is a certificate; the proof file is uploaded separately
+type StudentCertification struct {
+	ID            int64       `json:"id"`
+	Name          string      `json:"name"`
+	Issuer        string      `json:"issuer"`
+	IssueDate     pgtype.Date `json:"issue_date" swaggertype:"string" example:"2025-03-10"`
+	ExpiryDate    pgtype.Date `json:"expiry_date" swaggertype:"string" example:"2028-03-10"`
+	CredentialID  string      `json:"credential_id"`
+	CredentialURL string      `json:"credential_url"`
+	ProofURL      string      `json:"proof_url"` // Empty until a proof is uploaded
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+type StudentCertificationInput struct {
+	Name          string `json:"name" validate:"required,max=150"`
+	Issuer        string `json:"issuer" validate:"required,max=150"`
+	IssueDate     string `json:"issue_date"`  // YYYY-MM-DD, optional
+	ExpiryDate    string `json:"expiry_date"` // YYYY-MM-DD, optional
+	CredentialID  string `json:"credential_id" validate:"max=100"`
+	CredentialURL string `json:"credential_url" validate:"omitempty,url"`
+}
+
+// StudentProfileSections groups the structured sections shown on a full profile
+type StudentProfileSections struct {
+	Skills         []StudentSkill         `json:"skills"`
+	Projects       []StudentProject       `json:"projects"`
+	Internships    []StudentInternship    `json:"internships"`
+	Certifications []StudentCertification `json:"certifications"`
+}
+
+// StudentSectionFilter narrows the admin student list by profile sections. Zero values don't filter.
+type StudentSectionFilter struct {
+	Skills            []string // Student must have every skill (case-insensitive)
+	MinProficiency    string   // Applies to Skills, e.g. "advanced" also matches "expert"
+	HasInternship     bool
+	InternshipCompany string // Substring of an internship's company name
+	Certification     string // Substring of a certification's name or issuer
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Recruiter is a company HR account (users.role = 'recruiter'), scoped to one company
+type Recruiter struct {
+	UserID       int64      `json:"user_id"`
+	CompanyID    int64      `json:"company_id"`
+	CompanyName  string     `json:"company_name"`
+	Email        string     `json:"email"`
+	FullName     string     `json:"full_name"`
+	Designation  string     `json:"designation"`
+	MobileNumber string     `json:"mobile_number"`
+	IsBlocked    bool       `json:"is_blocked"`
+	LastLogin    *time.Time `json:"last_login"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// CreateRecruiterInput is sent by an admin to give a company HR a login
+type CreateRecruiterInput struct {
+	FullName     string `json:"full_name" validate:"required"`
+	Email        string `json:"email" validate:"required,email"`
+	Password     string `json:"password" validate:"required,min=8"`
+	Designation  string `json:"designation"`
+	MobileNumber string `json:"mobile_number"`
+}
+
+// RecruiterResult is one student's outcome in an uploaded round result.
+// Students are identified by student_id or register_number.
+type RecruiterResult struct {
+	StudentID      int64  `json:"student_id"`
+	RegisterNumber string `json:"register_number"`
+	Status         string `json:"status" validate:"required,oneof=shortlisted rejected placed"`
+	PackageOffered int64  `json:"package_offered"` // Offers only, annual CTC in rupees
+}
+
+// RecruiterResultsInput uploads a round's results for a drive
+type RecruiterResultsInput struct {
+	RoundName string            `json:"round_name"` // Free text for the audit log, e.g. "Technical Interview"
+	Results   []RecruiterResult `json:"results" validate:"required,min=1,dive"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// Deadline reminder kinds (hours before deadline_date)
+const (
+	ReminderType24h = "24h"
+	ReminderType2h  = "2h"
+)
+
+// NotificationPreferences are the channels a student wants reminders on.
+// Students without a row get every channel.
+type NotificationPreferences struct {
+	UserID          int64     `json:"user_id"`
+	PushEnabled     bool      `json:"push_enabled"`
+	EmailEnabled    bool      `json:"email_enabled"`
+	WhatsAppEnabled bool      `json:"whatsapp_enabled"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type UpdateNotificationPreferencesInput struct {
+	PushEnabled     *bool `json:"push_enabled"`
+	EmailEnabled    *bool `json:"email_enabled"`
+	WhatsAppEnabled *bool `json:"whatsapp_enabled"`
+}
+
+// ReminderTarget is an eligible student who hasn't responded to a drive closing soon
+type ReminderTarget struct {
+	DriveID      int64
+	StudentID    int64
+	FullName     string
+	Email        string
+	MobileNumber string
+	FCMToken     string
+	Preferences  NotificationPreferences
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// ParsedResume is what the resume parser detected. Fields it couldn't find are left empty.
+type ParsedResume struct {
+	Email       string            `json:"email"`
+	Phone       string            `json:"phone"`
+	Links       map[string]string `json:"links"`     // linkedin, github, leetcode, hackerrank, codechef
+	Skills      []string          `json:"skills"`    // Technical skills matched against a known list
+	Languages   []string          `json:"languages"` // Spoken languages
+	CGPA        float64           `json:"cgpa"`      // First CGPA seen when no degree line carries one
+	Education   []ResumeEducation `json:"education"`
+	TextLength  int               `json:"text_length"` // 0 usually means a scanned (image-only) resume
+	ExtractedAt time.Time         `json:"extracted_at"`
+}
+
+// ResumeEducation is one education line: a degree, 12th, 10th or diploma
+type ResumeEducation struct {
+	Level       string  `json:"level"` // 'pg', 'ug', 'diploma', 'twelfth', 'tenth'
+	Title       string  `json:"title"`
+	Institution string  `json:"institution"`
+	Board       string  `json:"board,omitempty"`
+	Year        int     `json:"year,omitempty"`
+	CGPA        float64 `json:"cgpa,omitempty"`
+	Percentage  float64 `json:"percentage,omitempty"`
+}
+
+// ProfileSuggestion is one field of the suggested profile diff.
+// Field uses the UpdateProfileInput JSON names; social links are "social_links.<key>".
+type ProfileSuggestion struct {
+	Field     string      `json:"field"`
+	Current   interface{} `json:"current"`
+	Suggested interface{} `json:"suggested"`
+}
+
+// ResumeSuggestions is the parsed resume plus the profile changes it implies
+type ResumeSuggestions struct {
+	ResumeURL   string              `json:"resume_url"`
+	Parsed      ParsedResume        `json:"parsed"`
+	Suggestions []ProfileSuggestion `json:"suggestions"`
+}
+
+// ApplyResumeSuggestionsInput lists the suggested fields the student accepted
+type ApplyResumeSuggestionsInput struct {
+	Fields []string `json:"fields" validate:"required,min=1"`
+}
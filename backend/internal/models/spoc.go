@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
 
 // Spoc represents a Single Point of Contact
 type Spoc struct {
@@ -20,9 +24,55 @@ type DriveSpoc struct {
 	IsPrimaryContact bool  `json:"is_primary_contact"`
 }
 
+// DriveSpocDetail is a SPOC as listed on a drive
+type DriveSpocDetail struct {
+	Spoc
+	IsPrimaryContact bool `json:"is_primary_contact"`
+}
+
+// SpocDrive is a drive as listed for one of its SPOCs
+type SpocDrive struct {
+	DriveID          int64       `json:"drive_id"`
+	CompanyName      string      `json:"company_name"`
+	JobRole          string      `json:"job_role"`
+	Status           string      `json:"status"`
+	DriveDate        pgtype.Date `json:"drive_date" swaggertype:"string" example:"2026-05-20"`
+	DeadlineDate     time.Time   `json:"deadline_date"`
+	IsPrimaryContact bool        `json:"is_primary_contact"`
+}
+
 type CreateSpocInput struct {
 	Name         string `json:"name" validate:"required"`
 	Designation  string `json:"designation" validate:"required"`
 	MobileNumber string `json:"mobile_number" validate:"required"`
 	Email        string `json:"email" validate:"required,email"`
 }
+
+// SpocContactLog is one interaction with a SPOC (call, email or visit), kept so the relationship
+// history survives staff changes on either side
+type SpocContactLog struct {
+	ID            int64       `json:"id"`
+	SpocID        int64       `json:"spoc_id"`
+	SpocName      string      `json:"spoc_name,omitempty"`
+	ContactType   string      `json:"contact_type"` // 'call', 'email', 'visit'
+	CompanyID     *int64      `json:"company_id"`
+	CompanyName   string      `json:"company_name,omitempty"`
+	DriveID       *int64      `json:"drive_id"`
+	Notes         string      `json:"notes"`
+	ContactedAt   time.Time   `json:"contacted_at"`
+	FollowUpDate  pgtype.Date `json:"follow_up_date" swaggertype:"string" example:"2026-05-20"`
+	FollowUpDone  bool        `json:"follow_up_done"`
+	LoggedBy      *int64      `json:"logged_by"`
+	LoggedByEmail string      `json:"logged_by_email,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+type SpocContactLogInput struct {
+	ContactType  string `json:"contact_type" validate:"required,oneof=call email visit"`
+	CompanyID    *int64 `json:"company_id"`
+	DriveID      *int64 `json:"drive_id"`
+	Notes        string `json:"notes" validate:"required"`
+	ContactedAt  string `json:"contacted_at"`   // RFC3339, defaults to now
+	FollowUpDate string `json:"follow_up_date"` // YYYY-MM-DD, optional
+	FollowUpDone bool   `json:"follow_up_done"`
+}
@@ -97,4 +97,7 @@ type StudentFullProfile struct {
 	ProfilePhotoURL string `json:"profile_photo_url"`
 	AadharCardURL   string `json:"aadhar_card_url"`
 	PanCardURL      string `json:"pan_card_url"`
+
+	// Skills, projects, internships and certifications
+	StudentProfileSections
 }
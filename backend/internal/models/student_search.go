@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// NumberRange bounds a numeric column; either end may be left open
+type NumberRange struct {
+	Min *float64 `json:"min"`
+	Max *float64 `json:"max"`
+}
+
+// StudentSearchFilters are ANDed together. Empty lists and nil pointers don't filter.
+type StudentSearchFilters struct {
+	Search               string   `json:"search"` // Name, register number or email contains
+	Departments          []string `json:"departments"`
+	BatchYears           []int    `json:"batch_years"`
+	Genders              []string `json:"genders" validate:"dive,oneof=Male Female Other"`
+	StudentTypes         []string `json:"student_types" validate:"dive,oneof=Regular Lateral"`
+	PlacementWillingness []string `json:"placement_willingness"` // e.g. ["Interested"]
+
+	// Academics
+	UgCgpa      NumberRange `json:"ug_cgpa"`
+	PgCgpa      NumberRange `json:"pg_cgpa"`
+	TenthMark   NumberRange `json:"tenth_mark"`
+	TwelfthMark NumberRange `json:"twelfth_mark"`
+	DiplomaMark NumberRange `json:"diploma_mark"`
+
+	MaxCurrentBacklogs *int `json:"max_current_backlogs"` // 0 = no standing arrears
+	MaxHistoryBacklogs *int `json:"max_history_of_backlogs"`
+
+	// "placed" = has a placed application, "not_placed" = has none
+	PlacementStatus string `json:"placement_status" validate:"omitempty,oneof=placed not_placed"`
+
+	// Profile sections
+	Skills            []string `json:"skills"` // Must have every skill
+	MinProficiency    string   `json:"min_proficiency" validate:"omitempty,oneof=beginner intermediate advanced expert"`
+	HasInternship     bool     `json:"has_internship"`
+	InternshipCompany string   `json:"internship_company"`
+	Certification     string   `json:"certification"`
+
+	IncludeBlocked bool `json:"include_blocked"` // Blocked students are hidden unless set
+}
+
+// StudentSearchQuery is a full search: filters, sort order and a page of results.
+// Cursor is the next_cursor of the previous page and must come from the same sort.
+type StudentSearchQuery struct {
+	Filters StudentSearchFilters `json:"filters"`
+	Sort    string               `json:"sort"` // Column, e.g. "ug_cgpa"; defaults to register_number
+	Order   string               `json:"order" validate:"omitempty,oneof=asc desc"`
+	Cursor  string               `json:"cursor"`
+	Limit   int                  `json:"limit" validate:"omitempty,min=1,max=500"` // Defaults to 50
+}
+
+// StudentSearchResult is one row of an admin student search
+type StudentSearchResult struct {
+	ID                   int64   `json:"id"`
+	Email                string  `json:"email"`
+	FullName             string  `json:"full_name"`
+	RegisterNumber       string  `json:"register_number"`
+	Department           string  `json:"department"`
+	BatchYear            int     `json:"batch_year"`
+	Gender               string  `json:"gender"`
+	StudentType          string  `json:"student_type"`
+	PlacementWillingness string  `json:"placement_willingness"`
+	MobileNumber         string  `json:"mobile_number"`
+	UgCgpa               float64 `json:"ug_cgpa"`
+	PgCgpa               float64 `json:"pg_cgpa"`
+	TenthMark            float64 `json:"tenth_mark"`
+	TwelfthMark          float64 `json:"twelfth_mark"`
+	DiplomaMark          float64 `json:"diploma_mark"`
+	CurrentBacklogs      int     `json:"current_backlogs"`
+	HistoryBacklogs      int     `json:"history_of_backlogs"`
+	IsPlaced             bool    `json:"is_placed"`
+	IsBlocked            bool    `json:"is_blocked"`
+	ProfilePhotoURL      string  `json:"profile_photo_url"`
+}
+
+// SavedStudentSearch is a named search an admin can re-run. Shared searches are visible to every admin.
+type SavedStudentSearch struct {
+	ID           int64              `json:"id"`
+	Name         string             `json:"name"`
+	Query        StudentSearchQuery `json:"query"` // Cursor and limit are not stored
+	IsShared     bool               `json:"is_shared"`
+	CreatedBy    int64              `json:"created_by"`
+	CreatedEmail string             `json:"created_by_email"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+type SavedStudentSearchInput struct {
+	Name     string             `json:"name" validate:"required,max=100"`
+	Query    StudentSearchQuery `json:"query"`
+	IsShared bool               `json:"is_shared"`
+}
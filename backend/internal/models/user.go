@@ -17,6 +17,13 @@ type User struct {
 	UpdatedAt time.Time  `json:"updated_at"`
 }
 
+// StudentContact is the minimal student info needed to send a personal notification
+type StudentContact struct {
+	UserID   int64  `json:"user_id"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+}
+
 // RegisterInput defines what the frontend sends us to create a user
 type RegisterInput struct {
 	FullName string `json:"full_name" validate:"required"`
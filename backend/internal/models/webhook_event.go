@@ -0,0 +1,28 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook event processing outcomes
+const (
+	WebhookEventReceived  = "received"  // Stored, not dispatched yet
+	WebhookEventProcessed = "processed" // Handler ran successfully
+	WebhookEventFailed    = "failed"    // Handler returned an error (see Error)
+	WebhookEventIgnored   = "ignored"   // No handler registered for the type
+)
+
+// WebhookEvent is an incoming webhook as received, plus the outcome of processing it
+type WebhookEvent struct {
+	ID          int64             `json:"id"`
+	Source      string            `json:"source"`     // 'railway', 'generic'
+	EventType   string            `json:"event_type"` // e.g. 'deployment.failed'
+	Headers     map[string]string `json:"headers"`
+	Body        json.RawMessage   `json:"body" swaggertype:"object"`
+	Status      string            `json:"status"` // received, processed, failed, ignored
+	Error       string            `json:"error,omitempty"`
+	Attempts    int               `json:"attempts"` // 1 + number of replays
+	ReceivedAt  time.Time         `json:"received_at"`
+	ProcessedAt *time.Time        `json:"processed_at"`
+}
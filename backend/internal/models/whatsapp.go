@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Portal events that can trigger a WhatsApp template message
+const (
+	WhatsAppEventNewDrive         = "new_drive"
+	WhatsAppEventDeadlineReminder = "deadline_reminder"
+	WhatsAppEventShortlisted      = "shortlisted"
+	WhatsAppEventOffer            = "offer"
+)
+
+// WhatsAppTemplate maps a portal event to a template approved in Meta Business Manager
+type WhatsAppTemplate struct {
+	Event        string    `json:"event"`         // 'new_drive', 'deadline_reminder', 'shortlisted', 'offer'
+	TemplateName string    `json:"template_name"` // Name as approved by Meta, e.g. "new_drive_alert"
+	LanguageCode string    `json:"language_code"` // e.g. "en_US"
+	Parameters   []string  `json:"parameters"`    // Ordered drive fields for body variables {{1}}, {{2}}, ...
+	IsActive     bool      `json:"is_active"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// UpdateWhatsAppTemplateInput is the admin payload for (re)configuring an event's template
+type UpdateWhatsAppTemplateInput struct {
+	TemplateName string   `json:"template_name" validate:"required"`
+	LanguageCode string   `json:"language_code"`
+	Parameters   []string `json:"parameters"`
+	IsActive     *bool    `json:"is_active"` // Optional, defaults to true
+}
+
+// WhatsAppMessage is the per-recipient delivery record of a business-initiated message.
+// Status moves accepted -> sent -> delivered -> read as Meta's status webhooks arrive, or to failed.
+type WhatsAppMessage struct {
+	ID           int64     `json:"id"`
+	MessageID    string    `json:"message_id"` // "wamid..." from the Cloud API; empty if the send was rejected
+	Recipient    string    `json:"recipient"`
+	StudentID    *int64    `json:"student_id"`
+	DriveID      *int64    `json:"drive_id"`
+	Event        string    `json:"event"`
+	TemplateName string    `json:"template_name"`
+	Status       string    `json:"status"` // 'accepted', 'sent', 'delivered', 'read', 'failed'
+	Error        string    `json:"error,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// WhatsAppStatusUpdate is one entry of the "statuses" array in a Cloud API webhook
+type WhatsAppStatusUpdate struct {
+	MessageID   string
+	Status      string // 'sent', 'delivered', 'read', 'failed'
+	RecipientID string
+	Timestamp   time.Time
+	Error       string
+}
@@ -2,11 +2,24 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
 	"time"
 
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+var (
+	ErrApplicationNotFound = errors.New("student has not applied to this drive")
+	ErrDriveNotFound       = errors.New("drive not found")
+	ErrUnknownStatus       = errors.New("unknown application status")
+	ErrInvalidTransition   = errors.New("status change not allowed")             // Wrapped with the from/to statuses
+	ErrWithdrawalClosed    = errors.New("withdrawals are closed for this drive") // Drive not open or past its deadline
+)
+
 type ApplicationRepository struct {
 	DB *pgxpool.Pool
 }
@@ -15,39 +28,289 @@ func NewApplicationRepository(db *pgxpool.Pool) *ApplicationRepository {
 	return &ApplicationRepository{DB: db}
 }
 
-// ApplyForDrive calls our Stored Procedure or uses direct logic
-func (r *ApplicationRepository) ApplyForDrive(ctx context.Context, studentID, driveID int64) (bool, string, error) {
-	// UPSERT to handle re-application
+// ApplyForDrive calls our Stored Procedure or uses direct logic.
+// Like withdrawals, applying (or re-applying after a withdrawal) needs the drive open and before
+// its deadline. It also returns the status the application had before ("" if it is new); applying
+// again while already opted in succeeds with from = opted_in and changes nothing.
+func (r *ApplicationRepository) ApplyForDrive(ctx context.Context, studentID, driveID int64) (bool, string, models.ApplicationStatus, error) {
+	// No-show penalty: missing a drive blocks the next few drives
+	noShow, err := NewAttendanceRepository(r.DB).GetBlockingNoShow(ctx, studentID, driveID)
+	if err != nil {
+		return false, err.Error(), "", err
+	}
+	if noShow != nil {
+		return false, fmt.Sprintf("You missed the %s drive on %s and are blocked from the next %d drives",
+			noShow.CompanyName, noShow.RecordedAt.Format("02 Jan 2006"), noShow.PenaltyDrives), "", nil
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return false, err.Error(), "", err
+	}
+	defer tx.Rollback(ctx)
+
+	// Same check as WithdrawApplication: FOR SHARE holds the drive's status and deadline until we commit
+	var open bool
+	var current string
+	err = tx.QueryRow(ctx, `
+        SELECT pd.status = 'open' AND pd.deadline_date >= CURRENT_TIMESTAMP, COALESCE(da.status, '')
+        FROM placement_drives pd
+        LEFT JOIN drive_applications da ON da.drive_id = pd.id AND da.student_id = $2
+        WHERE pd.id = $1
+        FOR SHARE OF pd
+    `, driveID, studentID).Scan(&open, &current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, "Drive not found", "", nil
+	}
+	if err != nil {
+		return false, err.Error(), "", err
+	}
+	if !open && models.ApplicationStatus(current) != models.StatusOptedIn {
+		return false, "This drive is closed or its deadline has passed", models.ApplicationStatus(current), nil
+	}
+
+	// Re-applying after a withdrawal reactivates the same row
+	actor := models.StatusActor{UserID: studentID, Role: models.StatusActorStudent}
+	from, err := setStatusTx(ctx, tx, driveID, studentID, models.StatusOptedIn, actor, "", false, true)
+	if errors.Is(err, ErrInvalidTransition) {
+		return false, fmt.Sprintf("Your application can't be reopened (current status: %s)", applicationStatusLabels[string(from)]), from, nil
+	}
+	if err != nil {
+		return false, err.Error(), from, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, err.Error(), from, err
+	}
+	return true, "Successfully applied", from, nil
+}
+
+// WithdrawApplication opts a student out of a drive they applied to. Withdrawal is only
+// allowed while the drive is open, before its deadline and before the student is shortlisted;
+// the reason is recorded in the status history. It returns the student's current status,
+// which is set even when the withdrawal is refused (e.g. ErrInvalidTransition for shortlisted).
+func (r *ApplicationRepository) WithdrawApplication(ctx context.Context, studentID, driveID int64, reason string) (models.ApplicationStatus, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	// FOR SHARE keeps the drive from being closed or its deadline moved until we commit,
+	// and the deadline is checked against the database clock like AutoCloseExpiredDrives
+	var open bool
+	var current string
+	err = tx.QueryRow(ctx, `
+        SELECT pd.status = 'open' AND pd.deadline_date >= CURRENT_TIMESTAMP, COALESCE(da.status, '')
+        FROM placement_drives pd
+        LEFT JOIN drive_applications da ON da.drive_id = pd.id AND da.student_id = $2
+        WHERE pd.id = $1
+        FOR SHARE OF pd
+    `, driveID, studentID).Scan(&open, &current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrDriveNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	from := models.ApplicationStatus(current)
+	switch {
+	case from == "":
+		return "", ErrApplicationNotFound
+	case !from.CanMoveTo(models.StatusOptedOut):
+		return from, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, models.StatusOptedOut)
+	case !open:
+		return from, ErrWithdrawalClosed
+	}
+
+	actor := models.StatusActor{UserID: studentID, Role: models.StatusActorStudent}
+	from, err = setStatusTx(ctx, tx, driveID, studentID, models.StatusOptedOut, actor, reason, false, false)
+	if err != nil {
+		return from, err
+	}
+	return from, tx.Commit(ctx)
+}
+
+// TransitionStatus moves an existing application to status and records the change in
+// application_status_history. It returns the previous status; setting the current status
+// again records nothing. Moves the transition graph doesn't allow fail with ErrInvalidTransition
+// unless override is set (admins only, for exceptional cases). Returns ErrApplicationNotFound
+// if the student hasn't applied.
+func (r *ApplicationRepository) TransitionStatus(ctx context.Context, driveID, studentID int64, status models.ApplicationStatus, actor models.StatusActor, remark string, override bool) (models.ApplicationStatus, error) {
+	return r.setStatus(ctx, driveID, studentID, status, actor, remark, override, false)
+}
+
+// setStatus is TransitionStatus that can also create the application (create = true)
+func (r *ApplicationRepository) setStatus(ctx context.Context, driveID, studentID int64, status models.ApplicationStatus, actor models.StatusActor, remark string, override, create bool) (models.ApplicationStatus, error) {
+	if !status.Valid() {
+		return "", fmt.Errorf("%w %q", ErrUnknownStatus, status)
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	from, err := setStatusTx(ctx, tx, driveID, studentID, status, actor, remark, override, create)
+	if err != nil {
+		return from, err
+	}
+	return from, tx.Commit(ctx)
+}
+
+// setStatusTx is setStatus inside the caller's transaction, which it doesn't commit
+func setStatusTx(ctx context.Context, tx pgx.Tx, driveID, studentID int64, status models.ApplicationStatus, actor models.StatusActor, remark string, override, create bool) (models.ApplicationStatus, error) {
+	var err error
+	created := false
+	if create && (override || models.ApplicationStatus("").CanMoveTo(status)) {
+		tag, err := tx.Exec(ctx, `
+            INSERT INTO drive_applications (drive_id, student_id, status, applied_at)
+            VALUES ($1, $2, $3, NOW())
+            ON CONFLICT (drive_id, student_id) DO NOTHING
+        `, driveID, studentID, status)
+		if err != nil {
+			return "", err
+		}
+		created = tag.RowsAffected() == 1
+	}
+
+	var from models.ApplicationStatus
+	if !created {
+		err = tx.QueryRow(ctx, `
+            SELECT status FROM drive_applications WHERE drive_id = $1 AND student_id = $2 FOR UPDATE
+        `, driveID, studentID).Scan(&from)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrApplicationNotFound
+		}
+		if err != nil {
+			return "", err
+		}
+		if from == status {
+			return from, nil
+		}
+		if !override && !from.CanMoveTo(status) {
+			return from, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, status)
+		}
+		_, err = tx.Exec(ctx, `
+            UPDATE drive_applications SET status = $3, updated_at = NOW()
+            WHERE drive_id = $1 AND student_id = $2
+        `, driveID, studentID, status)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var changedBy *int64
+	if actor.UserID > 0 {
+		changedBy = &actor.UserID
+	}
+	_, err = tx.Exec(ctx, `
+        INSERT INTO application_status_history (drive_id, student_id, from_status, to_status, changed_by, actor_role, remark, is_override)
+        VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, NULLIF($7, ''), $8)
+    `, driveID, studentID, string(from), status, changedBy, actor.Role, remark, override && !from.CanMoveTo(status))
+	if err != nil {
+		return "", err
+	}
+	return from, nil
+}
+
+// BulkTransitionStatus moves the given applicants of a drive to status in one statement,
+// recording each change in application_status_history. Students already at status, or whose
+// status can't move to it (unless override), are left alone; only the applications that
+// changed are returned.
+func (r *ApplicationRepository) BulkTransitionStatus(ctx context.Context, driveID int64, studentIDs []int64, status models.ApplicationStatus, actor models.StatusActor, remark string, override bool) ([]models.ApplicationStatusChange, error) {
+	if !status.Valid() {
+		return nil, fmt.Errorf("%w %q", ErrUnknownStatus, status)
+	}
+	var changedBy *int64
+	if actor.UserID > 0 {
+		changedBy = &actor.UserID
+	}
 	query := `
-		INSERT INTO drive_applications (drive_id, student_id, status, applied_at)
-		VALUES ($1, $2, 'opted_in', NOW())
-		ON CONFLICT (drive_id, student_id)
-		DO UPDATE SET status = 'opted_in', updated_at = NOW()
-	`
-	_, err := r.DB.Exec(ctx, query, driveID, studentID)
+        WITH cur AS (
+            SELECT student_id, status, status = ANY($7::text[]) AS allowed
+            FROM drive_applications
+            WHERE drive_id = $1 AND student_id = ANY($2::bigint[]) AND status <> $3
+            FOR UPDATE
+        ), upd AS (
+            UPDATE drive_applications da SET status = $3, updated_at = NOW()
+            FROM cur
+            WHERE da.drive_id = $1 AND da.student_id = cur.student_id AND (cur.allowed OR $8::boolean)
+            RETURNING da.student_id, cur.status AS from_status, NOT cur.allowed AS is_override
+        )
+        INSERT INTO application_status_history (drive_id, student_id, from_status, to_status, changed_by, actor_role, remark, is_override)
+        SELECT $1, student_id, from_status, $3, $4, $5, NULLIF($6, ''), is_override FROM upd
+        RETURNING id, student_id, COALESCE(from_status, ''), to_status, actor_role, COALESCE(remark, ''), is_override, created_at
+    `
+	rows, err := r.DB.Query(ctx, query, driveID, studentIDs, status, changedBy, actor.Role, remark,
+		models.ApplicationStatusesTo(status), override)
 	if err != nil {
-		return false, err.Error(), err
+		return nil, err
 	}
-	return true, "Successfully applied", nil
+	defer rows.Close()
+
+	changes := []models.ApplicationStatusChange{}
+	for rows.Next() {
+		h := models.ApplicationStatusChange{DriveID: driveID, ChangedBy: changedBy}
+		if err := rows.Scan(&h.ID, &h.StudentID, &h.FromStatus, &h.ToStatus, &h.ActorRole, &h.Remark, &h.IsOverride, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, h)
+	}
+	return changes, rows.Err()
 }
 
-// WithdrawApplication removes or updates the application status to 'withdrawn' or 'opted_out'
-func (r *ApplicationRepository) WithdrawApplication(ctx context.Context, studentID, driveID int64) error {
-	// UPSERT: If exists update, if not insert 'opted_out'
+// GetStatusHistory lists an application's status changes, oldest first, with who made them
+func (r *ApplicationRepository) GetStatusHistory(ctx context.Context, driveID, studentID int64) ([]models.ApplicationStatusChange, error) {
 	query := `
-		INSERT INTO drive_applications (drive_id, student_id, status, applied_at)
-		VALUES ($1, $2, 'opted_out', NOW())
-		ON CONFLICT (drive_id, student_id) 
-		DO UPDATE SET status = 'opted_out', updated_at = NOW()
-	`
-	_, err := r.DB.Exec(ctx, query, driveID, studentID)
-	return err
+        SELECT h.id, h.drive_id, h.student_id, COALESCE(h.from_status, ''), h.to_status,
+               h.changed_by, COALESCE(u.email, ''), h.actor_role, COALESCE(h.remark, ''), h.is_override, h.created_at
+        FROM application_status_history h
+        LEFT JOIN users u ON u.id = h.changed_by
+        WHERE h.drive_id = $1 AND h.student_id = $2
+        ORDER BY h.created_at, h.id
+    `
+	rows, err := r.DB.Query(ctx, query, driveID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := []models.ApplicationStatusChange{}
+	for rows.Next() {
+		var h models.ApplicationStatusChange
+		if err := rows.Scan(&h.ID, &h.DriveID, &h.StudentID, &h.FromStatus, &h.ToStatus,
+			&h.ChangedBy, &h.ChangedByEmail, &h.ActorRole, &h.Remark, &h.IsOverride, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, h)
+	}
+	return history, rows.Err()
+}
+
+// applicationStatusLabels describe a status change on the student timeline
+var applicationStatusLabels = map[string]string{
+	"eligible":    "Eligible to apply",
+	"opted_in":    "Applied",
+	"opted_out":   "Withdrew application",
+	"shortlisted": "Shortlisted",
+	"rejected":    "Not selected",
+	"placed":      "Placed",
+	"removed":     "Removed from drive",
 }
 
-// GetStudentApplications lists what a student has applied to
-func (r *ApplicationRepository) GetStudentApplications(ctx context.Context, studentID int64) ([]map[string]interface{}, error) {
+// GetStudentApplications lists every drive the student has an application for, newest first,
+// with round progress, offer details and a timeline of status changes, bookings, check-ins and no-shows
+func (r *ApplicationRepository) GetStudentApplications(ctx context.Context, studentID int64) ([]models.StudentApplication, error) {
 	query := `
-        SELECT da.drive_id, pd.company_name, pd.job_role, da.status, da.applied_at
+        SELECT
+            da.drive_id, pd.company_name, pd.job_role, COALESCE(pd.location, ''),
+            COALESCE(pd.drive_type, ''), COALESCE(pd.company_category, ''), COALESCE(pd.logo_url, ''),
+            COALESCE(pd.ctc_display, ''), pd.drive_date, pd.deadline_date, pd.status,
+            COALESCE(pd.rounds, '[]'::jsonb),
+            da.status, COALESCE(da.package_offered, 0), COALESCE(da.offer_letter_url, ''),
+            COALESCE(da.applied_at, NOW()), COALESCE(da.updated_at, da.applied_at, NOW())
         FROM drive_applications da
         JOIN placement_drives pd ON da.drive_id = pd.id
         WHERE da.student_id = $1
@@ -59,20 +322,272 @@ func (r *ApplicationRepository) GetStudentApplications(ctx context.Context, stud
 	}
 	defer rows.Close()
 
-	var apps []map[string]interface{}
+	apps := []models.StudentApplication{}
 	for rows.Next() {
-		var driveID int64
-		var company, role, status string
-		var appliedAt time.Time
-		rows.Scan(&driveID, &company, &role, &status, &appliedAt)
-
-		apps = append(apps, map[string]interface{}{
-			"drive_id": driveID,
-			"company":  company,
-			"role":     role,
-			"status":   status,
-			"date":     appliedAt,
+		var a models.StudentApplication
+		var rounds []models.Round
+		var ctcDisplay string
+		offer := models.ApplicationOffer{}
+		err := rows.Scan(
+			&a.DriveID, &a.CompanyName, &a.JobRole, &a.Location,
+			&a.DriveType, &a.CompanyCategory, &a.LogoURL,
+			&ctcDisplay, &a.DriveDate, &a.DeadlineDate, &a.DriveStatus,
+			&rounds,
+			&a.Status, &offer.PackageOffered, &offer.OfferLetterURL,
+			&a.AppliedAt, &a.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		a.Rounds = make([]models.ApplicationRound, len(rounds))
+		for i, rd := range rounds {
+			a.Rounds[i] = models.ApplicationRound{RoundIndex: i, Name: rd.Name, Date: rd.Date, State: models.RoundPending}
+		}
+		if a.Status == "placed" || offer.PackageOffered > 0 || offer.OfferLetterURL != "" {
+			offer.CtcDisplay = ctcDisplay
+			a.Offer = &offer
+		}
+		a.Timeline = []models.ApplicationEvent{}
+		apps = append(apps, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.attachApplicationProgress(ctx, studentID, apps); err != nil {
+		return nil, err
+	}
+	for i := range apps {
+		sort.SliceStable(apps[i].Timeline, func(x, y int) bool {
+			return apps[i].Timeline[x].At.Before(apps[i].Timeline[y].At)
 		})
 	}
 	return apps, nil
 }
+
+// attachApplicationProgress fills round states and timeline events from the status history,
+// interview bookings, drive-day check-ins and no-shows
+func (r *ApplicationRepository) attachApplicationProgress(ctx context.Context, studentID int64, apps []models.StudentApplication) error {
+	if len(apps) == 0 {
+		return nil
+	}
+	byDrive := make(map[int64]*models.StudentApplication, len(apps))
+	for i := range apps {
+		byDrive[apps[i].DriveID] = &apps[i]
+	}
+	// round returns the app's round at idx, or nil if the drive's rounds have since changed
+	round := func(a *models.StudentApplication, idx int) *models.ApplicationRound {
+		if idx < 0 || idx >= len(a.Rounds) {
+			return nil
+		}
+		return &a.Rounds[idx]
+	}
+	roundName := func(a *models.StudentApplication, idx int, fallback string) string {
+		if rd := round(a, idx); rd != nil && rd.Name != "" {
+			return rd.Name
+		}
+		if fallback != "" {
+			return fallback
+		}
+		return fmt.Sprintf("Round %d", idx+1)
+	}
+
+	// 1. Status changes
+	rows, err := r.DB.Query(ctx, `
+        SELECT drive_id, COALESCE(from_status, ''), to_status, actor_role, COALESCE(remark, ''), created_at
+        FROM application_status_history
+        WHERE student_id = $1
+    `, studentID)
+	if err != nil {
+		return err
+	}
+	hasHistory := map[int64]bool{}
+	for rows.Next() {
+		var driveID int64
+		var from, to, actorRole, remark string
+		var at time.Time
+		if err := rows.Scan(&driveID, &from, &to, &actorRole, &remark, &at); err != nil {
+			rows.Close()
+			return err
+		}
+		a := byDrive[driveID]
+		if a == nil {
+			continue
+		}
+		hasHistory[driveID] = true
+		event := models.ApplicationEvent{
+			Type: models.ApplicationEventStatusChanged, Status: to, Label: applicationStatusLabels[to],
+			ActorRole: actorRole, Remark: remark, At: at,
+		}
+		if to == "opted_in" {
+			event.Type = models.ApplicationEventApplied
+			if from == "opted_out" {
+				event.Label = "Re-applied"
+			}
+		}
+		a.Timeline = append(a.Timeline, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	// Applications from before the history table only have their first and latest status
+	for i := range apps {
+		a := &apps[i]
+		if hasHistory[a.DriveID] {
+			continue
+		}
+		if a.Status != "eligible" {
+			a.Timeline = append(a.Timeline, models.ApplicationEvent{
+				Type: models.ApplicationEventApplied, Status: "opted_in", Label: applicationStatusLabels["opted_in"], At: a.AppliedAt,
+			})
+		}
+		if a.Status != "opted_in" {
+			a.Timeline = append(a.Timeline, models.ApplicationEvent{
+				Type: models.ApplicationEventStatusChanged, Status: a.Status, Label: applicationStatusLabels[a.Status], At: a.UpdatedAt,
+			})
+		}
+	}
+
+	// 2. Interview slots
+	rows, err = r.DB.Query(ctx, `
+        SELECT ib.drive_id, ib.round_index, COALESCE(s.round_name, ''), s.start_time, s.end_time,
+               COALESCE(s.location, ''), COALESCE(s.meeting_link, ''), COALESCE(ib.booked_at, NOW())
+        FROM interview_bookings ib
+        JOIN interview_slots s ON s.id = ib.slot_id
+        WHERE ib.student_id = $1
+    `, studentID)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var driveID int64
+		var idx int
+		var name, location, link string
+		var start, end, bookedAt time.Time
+		if err := rows.Scan(&driveID, &idx, &name, &start, &end, &location, &link, &bookedAt); err != nil {
+			rows.Close()
+			return err
+		}
+		a := byDrive[driveID]
+		if a == nil {
+			continue
+		}
+		if rd := round(a, idx); rd != nil {
+			rd.State = models.RoundScheduled
+			rd.SlotStart, rd.SlotEnd = &start, &end
+			rd.Location, rd.MeetingLink = location, link
+		}
+		roundIndex := idx
+		a.Timeline = append(a.Timeline, models.ApplicationEvent{
+			Type:       models.ApplicationEventSlotBooked,
+			RoundIndex: &roundIndex,
+			Label:      fmt.Sprintf("%s slot booked for %s", roundName(a, idx, name), start.Format("02 Jan, 03:04 PM")),
+			At:         bookedAt,
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// 3. Drive-day check-ins
+	rows, err = r.DB.Query(ctx, `
+        SELECT drive_id, round_index, COALESCE(checked_in_at, NOW())
+        FROM drive_attendance
+        WHERE student_id = $1
+    `, studentID)
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+		var driveID int64
+		var idx int
+		var at time.Time
+		if err := rows.Scan(&driveID, &idx, &at); err != nil {
+			rows.Close()
+			return err
+		}
+		a := byDrive[driveID]
+		if a == nil {
+			continue
+		}
+		if rd := round(a, idx); rd != nil {
+			rd.State = models.RoundAttended
+			rd.CheckedInAt = &at
+		}
+		roundIndex := idx
+		a.Timeline = append(a.Timeline, models.ApplicationEvent{
+			Type:       models.ApplicationEventCheckedIn,
+			RoundIndex: &roundIndex,
+			Label:      "Checked in for " + roundName(a, idx, ""),
+			At:         at,
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// 4. No-shows (waived ones are dropped, as if never recorded)
+	rows, err = r.DB.Query(ctx, `
+        SELECT drive_id, round_index, COALESCE(recorded_at, NOW())
+        FROM drive_no_shows
+        WHERE student_id = $1 AND waived = false
+    `, studentID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var driveID int64
+		var idx int
+		var at time.Time
+		if err := rows.Scan(&driveID, &idx, &at); err != nil {
+			return err
+		}
+		a := byDrive[driveID]
+		if a == nil {
+			continue
+		}
+		if rd := round(a, idx); rd != nil && rd.State != models.RoundAttended {
+			rd.State = models.RoundMissed
+		}
+		roundIndex := idx
+		a.Timeline = append(a.Timeline, models.ApplicationEvent{
+			Type:       models.ApplicationEventNoShow,
+			RoundIndex: &roundIndex,
+			Label:      "Missed " + roundName(a, idx, ""),
+			At:         at,
+		})
+	}
+	return rows.Err()
+}
+
+// GetStudentDashboard returns the student's applications with counts by outcome
+func (r *ApplicationRepository) GetStudentDashboard(ctx context.Context, studentID int64) (*models.StudentApplicationDashboard, error) {
+	apps, err := r.GetStudentApplications(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	dash := &models.StudentApplicationDashboard{Applications: apps}
+	for _, a := range apps {
+		switch a.Status {
+		case "eligible":
+			continue
+		case "opted_out":
+			dash.Summary.Withdrawn++
+			continue
+		case "shortlisted":
+			dash.Summary.Shortlisted++
+		case "placed":
+			dash.Summary.Placed++
+		case "rejected":
+			dash.Summary.Rejected++
+		}
+		dash.Summary.Applied++
+	}
+	return dash, nil
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AttendanceRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewAttendanceRepository(db *pgxpool.Pool) *AttendanceRepository {
+	return &AttendanceRepository{DB: db}
+}
+
+// attendanceExpected: who should turn up for a round. Everyone who applied attends the first round,
+// later rounds are for shortlisted students (a student rejected after attending keeps their record).
+const attendanceExpected = `
+        (
+            (da.status IN ('opted_in', 'shortlisted', 'placed', 'rejected') AND $2::int = 0)
+            OR da.status IN ('shortlisted', 'placed')
+        )`
+
+// GetCheckInCandidate returns the student's name, register number and application status for a drive.
+// Returns nil if the student has no application for it.
+func (r *AttendanceRepository) GetCheckInCandidate(ctx context.Context, driveID, studentID int64) (*models.AttendanceRecord, error) {
+	query := `
+        SELECT da.student_id, COALESCE(sp.full_name, ''), COALESCE(sp.register_number, ''), COALESCE(sp.department, ''), da.status
+        FROM drive_applications da
+        LEFT JOIN student_personal sp ON sp.user_id = da.student_id
+        WHERE da.drive_id = $1 AND da.student_id = $2
+    `
+	var a models.AttendanceRecord
+	err := r.DB.QueryRow(ctx, query, driveID, studentID).Scan(&a.StudentID, &a.FullName, &a.RegisterNumber, &a.Department, &a.Status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// RecordCheckIn marks a student present for a round (clearing a no-show recorded before a late arrival).
+// Returns the check-in time and whether they were already checked in.
+func (r *AttendanceRepository) RecordCheckIn(ctx context.Context, driveID, studentID int64, roundIndex int, checkedInBy int64) (time.Time, bool, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var at time.Time
+	err = tx.QueryRow(ctx, `
+        INSERT INTO drive_attendance (drive_id, student_id, round_index, checked_in_at, checked_in_by)
+        VALUES ($1, $2, $3, NOW(), $4)
+        ON CONFLICT (drive_id, student_id, round_index) DO NOTHING
+        RETURNING checked_in_at
+    `, driveID, studentID, roundIndex, checkedInBy).Scan(&at)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = tx.QueryRow(ctx, `
+            SELECT checked_in_at FROM drive_attendance
+            WHERE drive_id = $1 AND student_id = $2 AND round_index = $3
+        `, driveID, studentID, roundIndex).Scan(&at)
+		if err != nil {
+			return time.Time{}, false, err
+		}
+		return at, true, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	if _, err := tx.Exec(ctx, `
+        DELETE FROM drive_no_shows WHERE drive_id = $1 AND student_id = $2 AND round_index = $3
+    `, driveID, studentID, roundIndex); err != nil {
+		return time.Time{}, false, err
+	}
+
+	return at, false, tx.Commit(ctx)
+}
+
+// GetAttendance lists everyone expected at a round with whether they checked in
+func (r *AttendanceRepository) GetAttendance(ctx context.Context, driveID int64, roundIndex int) ([]models.AttendanceRecord, error) {
+	query := `
+        SELECT da.student_id, COALESCE(sp.full_name, ''), COALESCE(sp.register_number, ''), COALESCE(sp.department, ''), da.status,
+               att.checked_in_at, att.checked_in_by
+        FROM drive_applications da
+        LEFT JOIN student_personal sp ON sp.user_id = da.student_id
+        LEFT JOIN drive_attendance att ON att.drive_id = da.drive_id AND att.student_id = da.student_id AND att.round_index = $2
+        WHERE da.drive_id = $1
+        AND (` + attendanceExpected + ` OR att.student_id IS NOT NULL)
+        ORDER BY sp.register_number, da.student_id
+    `
+	rows, err := r.DB.Query(ctx, query, driveID, roundIndex)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []models.AttendanceRecord{}
+	for rows.Next() {
+		var a models.AttendanceRecord
+		if err := rows.Scan(&a.StudentID, &a.FullName, &a.RegisterNumber, &a.Department, &a.Status, &a.CheckedInAt, &a.CheckedInBy); err != nil {
+			return nil, err
+		}
+		a.Present = a.CheckedInAt != nil
+		records = append(records, a)
+	}
+	return records, nil
+}
+
+// FinalizeNoShows records every expected student who didn't check in as a no-show with the given penalty.
+// Safe to run again: students already recorded keep their original penalty.
+func (r *AttendanceRepository) FinalizeNoShows(ctx context.Context, driveID int64, roundIndex, penaltyDrives int) (int64, error) {
+	query := `
+        INSERT INTO drive_no_shows (drive_id, student_id, round_index, penalty_drives, recorded_at)
+        SELECT da.drive_id, da.student_id, $2, $3, NOW()
+        FROM drive_applications da
+        WHERE da.drive_id = $1
+        AND ` + attendanceExpected + `
+        AND NOT EXISTS (
+            SELECT 1 FROM drive_attendance att
+            WHERE att.drive_id = da.drive_id AND att.student_id = da.student_id AND att.round_index = $2
+        )
+        ON CONFLICT (drive_id, student_id, round_index) DO NOTHING
+    `
+	tag, err := r.DB.Exec(ctx, query, driveID, roundIndex, penaltyDrives)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ListNoShows returns recorded no-shows, newest first, optionally for one drive and/or student
+func (r *AttendanceRepository) ListNoShows(ctx context.Context, driveID, studentID int64, limit, offset int) ([]models.NoShow, int64, error) {
+	where := `WHERE ($1::bigint = 0 OR ns.drive_id = $1) AND ($2::bigint = 0 OR ns.student_id = $2)`
+
+	var total int64
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM drive_no_shows ns `+where, driveID, studentID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+        SELECT ns.drive_id, pd.company_name, ns.student_id, COALESCE(sp.full_name, ''), COALESCE(sp.register_number, ''),
+               ns.round_index, ns.penalty_drives, ns.waived, ns.recorded_at
+        FROM drive_no_shows ns
+        JOIN placement_drives pd ON pd.id = ns.drive_id
+        LEFT JOIN student_personal sp ON sp.user_id = ns.student_id
+        ` + where + `
+        ORDER BY ns.recorded_at DESC
+        LIMIT $3 OFFSET $4
+    `
+	rows, err := r.DB.Query(ctx, query, driveID, studentID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	noShows := []models.NoShow{}
+	for rows.Next() {
+		var n models.NoShow
+		if err := rows.Scan(&n.DriveID, &n.CompanyName, &n.StudentID, &n.FullName, &n.RegisterNumber,
+			&n.RoundIndex, &n.PenaltyDrives, &n.Waived, &n.RecordedAt); err != nil {
+			return nil, 0, err
+		}
+		noShows = append(noShows, n)
+	}
+	return noShows, total, nil
+}
+
+// WaiveNoShow lifts the penalty of a no-show (e.g. medical leave). Returns false if there is no such record.
+func (r *AttendanceRepository) WaiveNoShow(ctx context.Context, driveID, studentID int64, roundIndex int) (bool, error) {
+	tag, err := r.DB.Exec(ctx, `
+        UPDATE drive_no_shows SET waived = true
+        WHERE drive_id = $1 AND student_id = $2 AND round_index = $3
+    `, driveID, studentID, roundIndex)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetBlockingNoShow returns the no-show that bars a student from a drive, or nil.
+// A no-show with penalty N blocks the first N published drives posted after it was recorded
+// that the student is eligible for, so drafts, cancelled drives and drives they could never
+// apply to don't use up the penalty.
+func (r *AttendanceRepository) GetBlockingNoShow(ctx context.Context, studentID, driveID int64) (*models.NoShow, error) {
+	query := `
+        WITH target AS (SELECT created_at FROM placement_drives WHERE id = $2)
+        SELECT ns.drive_id, nd.company_name, ns.student_id, ns.round_index, ns.penalty_drives, ns.recorded_at
+        FROM drive_no_shows ns
+        JOIN placement_drives nd ON nd.id = ns.drive_id
+        CROSS JOIN target
+        WHERE ns.student_id = $1
+        AND ns.waived = false
+        AND ns.penalty_drives > 0
+        AND target.created_at > ns.recorded_at
+        AND (
+            SELECT COUNT(*) FROM placement_drives pd
+            WHERE pd.created_at > ns.recorded_at AND pd.created_at <= target.created_at
+            AND (pd.status NOT IN ('cancelled', 'draft') OR pd.id = $2)
+            AND ` + studentEligibleSQL("ns.student_id") + `
+        ) <= ns.penalty_drives
+        ORDER BY ns.recorded_at DESC
+        LIMIT 1
+    `
+	var n models.NoShow
+	err := r.DB.QueryRow(ctx, query, studentID, driveID).Scan(
+		&n.DriveID, &n.CompanyName, &n.StudentID, &n.RoundIndex, &n.PenaltyDrives, &n.RecordedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
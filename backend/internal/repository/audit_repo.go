@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewAuditRepository(db *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{DB: db}
+}
+
+// Log appends an audit entry
+func (r *AuditRepository) Log(ctx context.Context, entry models.AuditLog) error {
+	if entry.Details == nil {
+		entry.Details = map[string]interface{}{}
+	}
+	_, err := r.DB.Exec(ctx, `
+        INSERT INTO audit_logs (actor_id, actor_role, company_id, action, drive_id, student_id, details, ip_address)
+        VALUES (NULLIF($1::bigint, 0), $2, $3, $4, $5, $6, $7, $8)
+    `, entry.ActorID, entry.ActorRole, entry.CompanyID, entry.Action, entry.DriveID, entry.StudentID, entry.Details, entry.IPAddress)
+	return err
+}
+
+// ListAuditLogs returns entries newest first. Zero / empty filters are ignored.
+func (r *AuditRepository) ListAuditLogs(ctx context.Context, companyID, actorID, driveID int64, action string, limit, offset int) ([]models.AuditLog, int64, error) {
+	where := `
+        WHERE ($1::bigint = 0 OR l.company_id = $1)
+          AND ($2::bigint = 0 OR l.actor_id = $2)
+          AND ($3::bigint = 0 OR l.drive_id = $3)
+          AND ($4::text = '' OR l.action = $4)`
+
+	var total int64
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM audit_logs l `+where, companyID, actorID, driveID, action).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+        SELECT l.id, COALESCE(l.actor_id, 0), l.actor_role, COALESCE(u.email, ''), l.company_id, l.action,
+               l.drive_id, l.student_id, COALESCE(l.details, '{}'::jsonb), COALESCE(l.ip_address, ''), l.created_at
+        FROM audit_logs l
+        LEFT JOIN users u ON u.id = l.actor_id ` + where + `
+        ORDER BY l.created_at DESC, l.id DESC
+        LIMIT $5 OFFSET $6`
+	rows, err := r.DB.Query(ctx, query, companyID, actorID, driveID, action, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	logs := []models.AuditLog{}
+	for rows.Next() {
+		var l models.AuditLog
+		err := rows.Scan(
+			&l.ID, &l.ActorID, &l.ActorRole, &l.ActorName, &l.CompanyID, &l.Action,
+			&l.DriveID, &l.StudentID, &l.Details, &l.IPAddress, &l.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		logs = append(logs, l)
+	}
+	return logs, total, rows.Err()
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CalendarRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewCalendarRepository(db *pgxpool.Pool) *CalendarRepository {
+	return &CalendarRepository{DB: db}
+}
+
+// GetToken returns the user's calendar feed token, or "" if they don't have one yet
+func (r *CalendarRepository) GetToken(ctx context.Context, userID int64) (string, error) {
+	var token string
+	err := r.DB.QueryRow(ctx, `SELECT token FROM calendar_tokens WHERE user_id = $1`, userID).Scan(&token)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return token, err
+}
+
+// SetToken creates or replaces the user's token. Replacing it breaks existing subscriptions (used to revoke a leaked URL).
+func (r *CalendarRepository) SetToken(ctx context.Context, userID int64, token string) error {
+	query := `
+        INSERT INTO calendar_tokens (user_id, token, created_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT (user_id) DO UPDATE SET token = EXCLUDED.token, created_at = NOW()
+    `
+	_, err := r.DB.Exec(ctx, query, userID, token)
+	return err
+}
+
+// GetStudentIDByToken resolves a feed token to an active, unblocked student. Returns 0 if there is none.
+func (r *CalendarRepository) GetStudentIDByToken(ctx context.Context, token string) (int64, error) {
+	query := `
+        SELECT u.id
+        FROM calendar_tokens ct
+        JOIN users u ON u.id = ct.user_id
+        WHERE ct.token = $1 AND u.role = 'student' AND u.is_active = true AND COALESCE(u.is_blocked, false) = false
+    `
+	var id int64
+	err := r.DB.QueryRow(ctx, query, token).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return id, err
+}
+
+// GetAppliedDrives lists drives the student is still in the running for (applied, shortlisted or placed),
+// including closed ones whose drive date or rounds are still ahead.
+func (r *CalendarRepository) GetAppliedDrives(ctx context.Context, studentID int64) ([]models.PlacementDrive, error) {
+	query := `
+        SELECT pd.id, pd.company_name, pd.job_role, COALESCE(pd.location, ''), COALESCE(pd.rounds, '[]'::jsonb),
+               pd.drive_date, pd.deadline_date, pd.status, da.status
+        FROM drive_applications da
+        JOIN placement_drives pd ON pd.id = da.drive_id
+        WHERE da.student_id = $1
+        AND da.status IN ('opted_in', 'shortlisted', 'placed')
+        AND pd.status NOT IN ('cancelled', 'draft')
+        ORDER BY pd.deadline_date
+    `
+	rows, err := r.DB.Query(ctx, query, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drives []models.PlacementDrive
+	for rows.Next() {
+		var d models.PlacementDrive
+		if err := rows.Scan(&d.ID, &d.CompanyName, &d.JobRole, &d.Location, &d.Rounds,
+			&d.DriveDate, &d.DeadlineDate, &d.Status, &d.UserStatus); err != nil {
+			return nil, err
+		}
+		drives = append(drives, d)
+	}
+	return drives, nil
+}
@@ -0,0 +1,476 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrCompanyNotFound     = errors.New("company not found")
+	ErrCompanyExists       = errors.New("a company with this name or domain already exists")
+	ErrAliasTaken          = errors.New("alias already belongs to another company")
+	ErrCompanyMergeToSelf  = errors.New("cannot merge a company into itself")
+	ErrCompanySpocNotFound = errors.New("spoc not found")
+)
+
+type CompanyRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewCompanyRepository(db *pgxpool.Pool) *CompanyRepository {
+	return &CompanyRepository{DB: db}
+}
+
+const companyColumns = `
+            c.id, c.name, COALESCE(c.domain, ''), COALESCE(c.logo_url, ''), COALESCE(c.category, ''),
+            COALESCE((SELECT jsonb_agg(a.alias ORDER BY a.alias) FROM company_aliases a WHERE a.company_id = c.id), '[]'::jsonb),
+            c.created_at, c.updated_at,
+            (SELECT COUNT(*) FROM placement_drives pd WHERE pd.company_id = c.id),
+            (SELECT MAX(COALESCE(pd.drive_date, pd.created_at::date)) FROM placement_drives pd WHERE pd.company_id = c.id)`
+
+func scanCompany(row pgx.Row) (*models.Company, error) {
+	var co models.Company
+	err := row.Scan(
+		&co.ID, &co.Name, &co.Domain, &co.LogoURL, &co.Category,
+		&co.Aliases, &co.CreatedAt, &co.UpdatedAt,
+		&co.DriveCount, &co.LastDriveDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &co, nil
+}
+
+// ListCompanies searches by display name or any alias
+func (r *CompanyRepository) ListCompanies(ctx context.Context, search string, limit, offset int) ([]models.Company, int64, error) {
+	where := `
+        WHERE $1::text = ''
+           OR c.name ILIKE '%' || $1::text || '%'
+           OR c.domain ILIKE '%' || $1::text || '%'
+           OR EXISTS (SELECT 1 FROM company_aliases a WHERE a.company_id = c.id AND a.alias LIKE '%' || normalize_company_name($1::text) || '%')`
+
+	var total int64
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM companies c `+where, search).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT ` + companyColumns + `
+        FROM companies c ` + where + `
+        ORDER BY c.name
+        LIMIT $2 OFFSET $3`
+	rows, err := r.DB.Query(ctx, query, search, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	companies := []models.Company{}
+	for rows.Next() {
+		co, err := scanCompany(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		companies = append(companies, *co)
+	}
+	return companies, total, rows.Err()
+}
+
+// GetCompany returns a company with its SPOCs, or nil if it doesn't exist
+func (r *CompanyRepository) GetCompany(ctx context.Context, id int64) (*models.Company, error) {
+	co, err := scanCompany(r.DB.QueryRow(ctx, `SELECT `+companyColumns+` FROM companies c WHERE c.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+        SELECT s.id, s.name, COALESCE(s.designation, ''), s.mobile_number, COALESCE(s.email, ''), s.is_active, s.created_at
+        FROM company_spocs cs
+        JOIN spocs s ON s.id = cs.spoc_id
+        WHERE cs.company_id = $1
+        ORDER BY s.name
+    `
+	rows, err := r.DB.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	co.Spocs = []models.Spoc{}
+	for rows.Next() {
+		var s models.Spoc
+		if err := rows.Scan(&s.ID, &s.Name, &s.Designation, &s.MobileNumber, &s.Email, &s.IsActive, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		co.Spocs = append(co.Spocs, s)
+	}
+	return co, rows.Err()
+}
+
+// CreateCompany inserts a company with its own normalized name as the first alias
+func (r *CompanyRepository) CreateCompany(ctx context.Context, input models.CompanyInput) (int64, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int64
+	err = tx.QueryRow(ctx, `
+        INSERT INTO companies (name, normalized_name, domain, logo_url, category)
+        VALUES ($1, normalize_company_name($1), NULLIF(normalize_company_domain($2), ''), NULLIF($3, ''), NULLIF($4, ''))
+        ON CONFLICT DO NOTHING
+        RETURNING id
+    `, input.Name, input.Domain, input.LogoURL, input.Category).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrCompanyExists
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := addCompanyAliases(ctx, tx, id, append([]string{input.Name}, input.Aliases...)); err != nil {
+		return 0, err
+	}
+	if err := setCompanySpocs(ctx, tx, id, input.SpocIDs); err != nil {
+		return 0, err
+	}
+	return id, tx.Commit(ctx)
+}
+
+// UpdateCompany changes the display fields. Earlier spellings stay as aliases unless input.Aliases replaces them.
+func (r *CompanyRepository) UpdateCompany(ctx context.Context, id int64, input models.CompanyInput) (bool, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `
+        UPDATE companies
+        SET name = $1, normalized_name = normalize_company_name($1),
+            domain = NULLIF(normalize_company_domain($2), ''), logo_url = NULLIF($3, ''), category = NULLIF($4, ''),
+            updated_at = NOW()
+        WHERE id = $5
+    `, input.Name, input.Domain, input.LogoURL, input.Category, id)
+	if isUniqueViolation(err) {
+		return false, ErrCompanyExists
+	}
+	if err != nil {
+		return false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	aliases := []string{input.Name}
+	if input.Aliases != nil {
+		// The given list replaces the extra spellings; the current name always stays
+		if _, err := tx.Exec(ctx, `DELETE FROM company_aliases WHERE company_id = $1`, id); err != nil {
+			return false, err
+		}
+		aliases = append(aliases, input.Aliases...)
+	}
+	if err := addCompanyAliases(ctx, tx, id, aliases); err != nil {
+		return false, err
+	}
+	if input.SpocIDs != nil {
+		if err := setCompanySpocs(ctx, tx, id, input.SpocIDs); err != nil {
+			return false, err
+		}
+	}
+	return true, tx.Commit(ctx)
+}
+
+// SetBrand stores a fetched logo (and domain if the company had none)
+func (r *CompanyRepository) SetBrand(ctx context.Context, id int64, domain, logoURL string) error {
+	_, err := r.DB.Exec(ctx, `
+        UPDATE companies
+        SET domain = COALESCE(domain, NULLIF(normalize_company_domain($2), '')),
+            logo_url = COALESCE(NULLIF($3, ''), logo_url),
+            updated_at = NOW()
+        WHERE id = $1
+    `, id, domain, logoURL)
+	if isUniqueViolation(err) {
+		return ErrCompanyExists
+	}
+	return err
+}
+
+// ResolveCompany finds the company for a drive's free-text name and website, creating it if new.
+// The website wins over the name, so a new spelling on a known domain is learned as an alias.
+func (r *CompanyRepository) ResolveCompany(ctx context.Context, name, website, logoURL, category string) (int64, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int64
+	err = tx.QueryRow(ctx, `
+        SELECT id FROM companies
+        WHERE normalize_company_domain($1) <> '' AND domain = normalize_company_domain($1)
+    `, website).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = tx.QueryRow(ctx, `SELECT company_id FROM company_aliases WHERE alias = normalize_company_name($1)`, name).Scan(&id)
+	}
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		err = tx.QueryRow(ctx, `
+            INSERT INTO companies (name, normalized_name, domain, logo_url, category)
+            VALUES (trim($1), normalize_company_name($1), NULLIF(normalize_company_domain($2), ''), NULLIF($3, ''), NULLIF($4, ''))
+            ON CONFLICT (normalized_name) DO UPDATE SET updated_at = NOW()
+            RETURNING id
+        `, name, website, logoURL, category).Scan(&id)
+		if err != nil {
+			return 0, err
+		}
+	case err != nil:
+		return 0, err
+	default:
+		// Known company: fill in what it was missing
+		_, err = tx.Exec(ctx, `
+            UPDATE companies
+            SET domain = COALESCE(domain, NULLIF(normalize_company_domain($2), '')),
+                logo_url = COALESCE(logo_url, NULLIF($3, '')),
+                category = COALESCE(category, NULLIF($4, ''))
+            WHERE id = $1
+              AND NOT EXISTS (SELECT 1 FROM companies o WHERE o.id <> $1 AND o.domain = normalize_company_domain($2))
+        `, id, website, logoURL, category)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if err := addCompanyAliases(ctx, tx, id, []string{name}); err != nil && !errors.Is(err, ErrAliasTaken) {
+		return 0, err
+	}
+	return id, tx.Commit(ctx)
+}
+
+// LinkSpoc records that a SPOC handled one of the company's drives
+func (r *CompanyRepository) LinkSpoc(ctx context.Context, companyID, spocID int64) error {
+	_, err := r.DB.Exec(ctx, `
+        INSERT INTO company_spocs (company_id, spoc_id) VALUES ($1, $2)
+        ON CONFLICT DO NOTHING
+    `, companyID, spocID)
+	return err
+}
+
+// MergeCompanies moves the drives, aliases and SPOCs of sourceIDs onto targetID and deletes the sources.
+// The target keeps its own display fields; missing ones are taken from the sources.
+func (r *CompanyRepository) MergeCompanies(ctx context.Context, targetID int64, sourceIDs []int64) (int64, error) {
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return 0, ErrCompanyMergeToSelf
+		}
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the target so concurrent merges into it serialise
+	var exists bool
+	err = tx.QueryRow(ctx, `SELECT true FROM companies WHERE id = $1 FOR UPDATE`, targetID).Scan(&exists)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrCompanyNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var domain, logoURL, category *string
+	err = tx.QueryRow(ctx, `
+        SELECT
+            (SELECT domain FROM companies WHERE id = ANY($1) AND domain IS NOT NULL ORDER BY updated_at DESC LIMIT 1),
+            (SELECT logo_url FROM companies WHERE id = ANY($1) AND logo_url IS NOT NULL ORDER BY updated_at DESC LIMIT 1),
+            (SELECT category FROM companies WHERE id = ANY($1) AND category IS NOT NULL ORDER BY updated_at DESC LIMIT 1)
+    `, sourceIDs).Scan(&domain, &logoURL, &category)
+	if err != nil {
+		return 0, err
+	}
+
+	steps := []string{
+		`UPDATE placement_drives SET company_id = $1 WHERE company_id = ANY($2)`,
+		`UPDATE company_aliases SET company_id = $1 WHERE company_id = ANY($2)`,
+		`INSERT INTO company_spocs (company_id, spoc_id)
+         SELECT DISTINCT $1::bigint, spoc_id FROM company_spocs WHERE company_id = ANY($2)
+         ON CONFLICT DO NOTHING`,
+	}
+	for _, q := range steps {
+		if _, err := tx.Exec(ctx, q, targetID, sourceIDs); err != nil {
+			return 0, err
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM companies WHERE id = ANY($1)`, sourceIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(ctx, `
+        UPDATE companies
+        SET domain = COALESCE(domain, $2), logo_url = COALESCE(logo_url, $3), category = COALESCE(category, $4),
+            updated_at = NOW()
+        WHERE id = $1
+    `, targetID, domain, logoURL, category)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), tx.Commit(ctx)
+}
+
+// GetCompanyHistory returns per-drive and per-year outcomes, or nil if the company doesn't exist.
+// Offers are placed applications; an offer's value is package_offered, falling back to the drive's ctc_max.
+func (r *CompanyRepository) GetCompanyHistory(ctx context.Context, id int64) (*models.CompanyHistory, error) {
+	company, err := r.GetCompany(ctx, id)
+	if err != nil || company == nil {
+		return nil, err
+	}
+
+	query := `
+        SELECT
+            pd.id, pd.company_name, pd.job_role, COALESCE(pd.drive_type, ''), COALESCE(pd.status, ''), pd.drive_date,
+            EXTRACT(YEAR FROM COALESCE(pd.drive_date, pd.created_at::date))::int,
+            COALESCE(pd.ctc_min, 0), COALESCE(pd.ctc_max, 0), COALESCE(pd.ctc_display, ''),
+            COUNT(da.student_id) FILTER (WHERE da.status NOT IN ('eligible', 'opted_out')),
+            COUNT(da.student_id) FILTER (WHERE da.status IN ('shortlisted', 'placed')),
+            COUNT(da.student_id) FILTER (WHERE da.status = 'placed'),
+            COALESCE(ROUND(AVG(COALESCE(da.package_offered, pd.ctc_max)) FILTER (WHERE da.status = 'placed')), 0)::bigint,
+            COALESCE(MAX(COALESCE(da.package_offered, pd.ctc_max)) FILTER (WHERE da.status = 'placed'), 0)
+        FROM placement_drives pd
+        LEFT JOIN drive_applications da ON da.drive_id = pd.id
+        WHERE pd.company_id = $1
+        GROUP BY pd.id
+        ORDER BY COALESCE(pd.drive_date, pd.created_at::date) DESC, pd.id DESC
+    `
+	rows, err := r.DB.Query(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := &models.CompanyHistory{
+		Company: company,
+		Years:   []models.CompanyYearStats{},
+		Drives:  []models.CompanyDriveStats{},
+	}
+	for rows.Next() {
+		var d models.CompanyDriveStats
+		err := rows.Scan(
+			&d.DriveID, &d.CompanyName, &d.JobRole, &d.DriveType, &d.Status, &d.DriveDate,
+			&d.Year, &d.CtcMin, &d.CtcMax, &d.CtcDisplay,
+			&d.Applicants, &d.Shortlisted, &d.Offers, &d.AvgOffer, &d.MaxOffer,
+		)
+		if err != nil {
+			return nil, err
+		}
+		history.Drives = append(history.Drives, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Roll drives up per year (drives are newest first, so years are built newest first and reversed)
+	type yearTotals struct {
+		stats            models.CompanyYearStats
+		ctcSum, offerSum int64
+		ctcDrives        int64
+	}
+	var years []*yearTotals
+	for _, d := range history.Drives {
+		if len(years) == 0 || years[len(years)-1].stats.Year != d.Year {
+			years = append(years, &yearTotals{stats: models.CompanyYearStats{Year: d.Year}})
+		}
+		y := years[len(years)-1]
+		y.stats.Drives++
+		y.stats.Applicants += d.Applicants
+		y.stats.Offers += d.Offers
+		y.offerSum += d.AvgOffer * int64(d.Offers)
+		if d.MaxOffer > y.stats.MaxOffer {
+			y.stats.MaxOffer = d.MaxOffer
+		}
+		if d.CtcMax > 0 {
+			y.ctcSum += d.CtcMax
+			y.ctcDrives++
+		}
+
+		history.TotalDrives++
+		history.TotalOffers += d.Offers
+	}
+	for i := len(years) - 1; i >= 0; i-- {
+		y := years[i]
+		if y.ctcDrives > 0 {
+			y.stats.AvgCtc = y.ctcSum / y.ctcDrives
+		}
+		if y.stats.Offers > 0 {
+			y.stats.AvgOffer = y.offerSum / int64(y.stats.Offers)
+		}
+		history.Years = append(history.Years, y.stats)
+	}
+	return history, nil
+}
+
+// addCompanyAliases maps each spelling's normalized form to the company.
+// Returns ErrAliasTaken if a spelling already resolves to a different company.
+func addCompanyAliases(ctx context.Context, tx pgx.Tx, companyID int64, names []string) error {
+	for _, name := range names {
+		var owner int64
+		err := tx.QueryRow(ctx, `
+            INSERT INTO company_aliases (alias, company_id)
+            VALUES (normalize_company_name($1), $2)
+            ON CONFLICT (alias) DO UPDATE SET alias = EXCLUDED.alias -- No-op update so RETURNING yields the current owner
+            RETURNING company_id
+        `, name, companyID).Scan(&owner)
+		if err != nil {
+			return err
+		}
+		if owner != companyID {
+			return ErrAliasTaken
+		}
+	}
+	return nil
+}
+
+// setCompanySpocs replaces the SPOCs linked to a company
+func setCompanySpocs(ctx context.Context, tx pgx.Tx, companyID int64, spocIDs []int64) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM company_spocs WHERE company_id = $1`, companyID); err != nil {
+		return err
+	}
+	if len(spocIDs) == 0 {
+		return nil
+	}
+	tag, err := tx.Exec(ctx, `
+        INSERT INTO company_spocs (company_id, spoc_id)
+        SELECT $1::bigint, id FROM spocs WHERE id = ANY($2)
+        ON CONFLICT DO NOTHING
+    `, companyID, spocIDs)
+	if err != nil {
+		return err
+	}
+	unique := make(map[int64]bool, len(spocIDs))
+	for _, id := range spocIDs {
+		unique[id] = true
+	}
+	if int(tag.RowsAffected()) != len(unique) {
+		return ErrCompanySpocNotFound
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is a Postgres unique_violation (23505)
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -18,7 +19,9 @@ func NewDriveRepository(db *pgxpool.Pool) *DriveRepository {
 }
 
 // 1. Create Drive (Admin Only)
-func (r *DriveRepository) CreateDrive(ctx context.Context, drive models.PlacementDrive) error { // Changed drive to value type
+// Returns the ID of the newly created drive. The drive and its SPOCs are saved in one
+// transaction, so a bad SPOC list leaves nothing behind.
+func (r *DriveRepository) CreateDrive(ctx context.Context, drive models.PlacementDrive, spocs []models.DriveSpoc) (int64, error) { // Changed drive to value type
 	query := `
         INSERT INTO placement_drives (
             posted_by, company_name, job_role, job_description, location,
@@ -28,7 +31,7 @@ func (r *DriveRepository) CreateDrive(ctx context.Context, drive models.Placemen
             eligible_batches, eligible_departments, 
             rounds, attachments,
             drive_date, deadline_date,
-            website, logo_url,
+            website, logo_url, company_id,
             status, created_at
         ) VALUES (
             $1, $2, $3, $4, $5,
@@ -37,13 +40,21 @@ func (r *DriveRepository) CreateDrive(ctx context.Context, drive models.Placemen
             $14, $15, $16, $17,
             $18, $19,
             $20, $21, 
-            $22, $23,
+            $22, $23, $24,
             'open', NOW()
         )
+        RETURNING id
     `
 	// Note: 'drive.DriveDate' needs careful handling, simplified here
 
-	_, err := r.DB.Exec(ctx, query, // Changed to Exec
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int64
+	err = tx.QueryRow(ctx, query,
 		drive.PostedBy, drive.CompanyName, drive.JobRole, drive.JobDescription, drive.Location,
 		drive.DriveType, drive.CompanyCategory, drive.SpocID,
 		drive.CtcMin, drive.CtcMax, drive.CtcDisplay, drive.StipendMin, drive.StipendMax,
@@ -51,10 +62,18 @@ func (r *DriveRepository) CreateDrive(ctx context.Context, drive models.Placemen
 		drive.EligibleBatches, drive.EligibleDepartments, // Note: eligible_branches maps to eligible_departments in query
 		drive.Rounds, drive.Attachments, // Note: Rounds maps to rounds in query
 		drive.DriveDate, drive.DeadlineDate,
-		drive.Website, drive.LogoURL,
-	)
+		drive.Website, drive.LogoURL, drive.CompanyID,
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
 
-	return err
+	if len(spocs) > 0 {
+		if err := setDriveSpocs(ctx, tx, id, spocs); err != nil {
+			return 0, err
+		}
+	}
+	return id, tx.Commit(ctx)
 }
 
 // 2. List Drives (With Dynamic Filters!)
@@ -114,6 +133,13 @@ func (r *DriveRepository) GetDrives(ctx context.Context, filters map[string]inte
 		argCounter++
 	}
 
+	// Filter by Company (Recruiter scope)
+	if val, ok := filters["company_id"]; ok {
+		query += fmt.Sprintf(" AND pd.company_id = $%d", argCounter)
+		args = append(args, val)
+		argCounter++
+	}
+
 	// Always sort by deadline (Urgency)
 	query += ` ORDER BY pd.deadline_date ASC`
 
@@ -144,33 +170,15 @@ func (r *DriveRepository) GetDrives(ctx context.Context, filters map[string]inte
 
 		drives = append(drives, d)
 	}
-	return drives, nil
-
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return drives, r.attachDriveSpocs(ctx, drives)
 }
 
 // 2.5 Get Eligible Drives (For Students)
 func (r *DriveRepository) GetEligibleDrives(ctx context.Context, studentID int64) ([]models.PlacementDrive, error) {
-	// A. First, fetch the student's academic and personal profile
-	// We need: Department, Batch Year, CGPA, Backlogs
-	queryStudent := `
-        SELECT 
-            sp.department, sp.batch_year, 
-            COALESCE(sa.ug_cgpa, 0.0), COALESCE(sa.current_backlogs, 0)
-        FROM student_personal sp
-        LEFT JOIN student_academics sa ON sp.user_id = sa.user_id
-        WHERE sp.user_id = $1
-    `
-	var dept string
-	var batch int
-	var cgpa float64
-	var backlogs int
-
-	err := r.DB.QueryRow(ctx, queryStudent, studentID).Scan(&dept, &batch, &cgpa, &backlogs)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch student profile: %v", err)
-	}
-
-	// B. Query Drives with Eligibility Filters
+	// Query Drives with Eligibility Filters (against the student's department, batch, CGPA and backlogs)
 	// [NEW] Joined with drive_applications to get status for THIS student
 	queryDrives := `
         SELECT 
@@ -186,21 +194,11 @@ func (r *DriveRepository) GetEligibleDrives(ctx context.Context, studentID int64
 		LEFT JOIN drive_applications da ON pd.id = da.drive_id AND da.student_id = $1
         WHERE pd.status = 'open'
         AND pd.deadline_date > NOW()
-        AND pd.min_cgpa <= $2
-        AND pd.max_backlogs_allowed >= $3
-        AND (pd.eligible_departments IS NULL OR pd.eligible_departments = 'null' OR pd.eligible_departments @> jsonb_build_array($4::text))
-        AND (pd.eligible_batches IS NULL OR pd.eligible_batches = 'null' OR pd.eligible_batches @> jsonb_build_array($5::int))
+        AND ` + studentEligibleSQL("$1") + `
         ORDER BY pd.deadline_date ASC
     `
 
-	// Note: Params shifted because $1 is now studentID for the JOIN
-	// $1 = studentID
-	// $2 = cgpa
-	// $3 = backlogs
-	// $4 = dept
-	// $5 = batch
-
-	rows, err := r.DB.Query(ctx, queryDrives, studentID, cgpa, backlogs, dept, batch)
+	rows, err := r.DB.Query(ctx, queryDrives, studentID)
 	if err != nil {
 		return nil, err
 	}
@@ -224,11 +222,15 @@ func (r *DriveRepository) GetEligibleDrives(ctx context.Context, studentID int64
 		}
 		drives = append(drives, d)
 	}
-	return drives, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return drives, r.attachDriveSpocs(ctx, drives)
 }
 
 // 3. Update Drive (Admin: Extend Deadline, Change CTC, etc.)
-func (r *DriveRepository) UpdateDrive(ctx context.Context, id int64, drive *models.PlacementDrive) error {
+// spocs replaces the drive's SPOCs in the same transaction; nil leaves them as they are.
+func (r *DriveRepository) UpdateDrive(ctx context.Context, id int64, drive *models.PlacementDrive, spocs []models.DriveSpoc) error {
 	query := `
         UPDATE placement_drives 
         SET company_name=$1, job_role=$2, job_description=$3, location=$4,
@@ -239,11 +241,17 @@ func (r *DriveRepository) UpdateDrive(ctx context.Context, id int64, drive *mode
 
             rounds=$17, attachments=$18,
             drive_date=$19, deadline_date=$20,
-            website=$21, logo_url=$22
-        WHERE id = $23
+            website=$21, logo_url=$22, company_id=$23
+        WHERE id = $24
     `
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	// Note: We don't update 'posted_by' or 'created_at'
-	_, err := r.DB.Exec(ctx, query,
+	_, err = tx.Exec(ctx, query,
 		drive.CompanyName, drive.JobRole, drive.JobDescription, drive.Location,
 		drive.DriveType, drive.CompanyCategory, drive.SpocID,
 		drive.CtcMin, drive.CtcMax, drive.CtcDisplay, drive.StipendMin, drive.StipendMax,
@@ -251,10 +259,19 @@ func (r *DriveRepository) UpdateDrive(ctx context.Context, id int64, drive *mode
 		drive.EligibleBatches, drive.EligibleDepartments,
 		drive.Rounds, drive.Attachments,
 		drive.DriveDate, drive.DeadlineDate,
-		drive.Website, drive.LogoURL,
+		drive.Website, drive.LogoURL, drive.CompanyID,
 		id,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	if spocs != nil {
+		if err := setDriveSpocs(ctx, tx, id, spocs); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
 }
 
 // 3.5 Get Drive By ID (Internal use for deletion/updates)
@@ -267,7 +284,7 @@ func (r *DriveRepository) GetDriveByID(ctx context.Context, id int64) (*models.P
             min_cgpa, max_backlogs_allowed,
             COALESCE(eligible_batches, '[]'::jsonb), COALESCE(eligible_departments, '[]'::jsonb),
             COALESCE(rounds, '[]'::jsonb), COALESCE(attachments, '[]'::jsonb),
-            drive_date, deadline_date, website, logo_url, status, created_at
+            drive_date, deadline_date, website, logo_url, status, created_at, company_id
         FROM placement_drives
         WHERE id = $1
     `
@@ -279,14 +296,144 @@ func (r *DriveRepository) GetDriveByID(ctx context.Context, id int64) (*models.P
 		&d.MinCgpa, &d.MaxBacklogsAllowed,
 		&d.EligibleBatches, &d.EligibleDepartments,
 		&d.Rounds, &d.Attachments,
-		&d.DriveDate, &d.DeadlineDate, &d.Website, &d.LogoURL, &d.Status, &d.CreatedAt,
+		&d.DriveDate, &d.DeadlineDate, &d.Website, &d.LogoURL, &d.Status, &d.CreatedAt, &d.CompanyID,
 	)
 	if err != nil {
 		return nil, err
 	}
+	spocs, err := r.GetDriveSpocs(ctx, []int64{d.ID})
+	if err != nil {
+		return nil, err
+	}
+	d.Spocs = spocs[d.ID]
+	if d.Spocs == nil {
+		d.Spocs = []models.DriveSpocDetail{}
+	}
 	return &d, nil
 }
 
+// 3.6 Set Drive SPOCs
+// Replaces the drive's SPOC list inside the caller's drive transaction. spocs must be de-duplicated
+// with at most one primary contact; placement_drives.spoc_id follows the primary so older clients
+// keep seeing a single SPOC.
+func setDriveSpocs(ctx context.Context, tx pgx.Tx, driveID int64, spocs []models.DriveSpoc) error {
+	ids := make([]int64, 0, len(spocs))
+	var primaryID *int64
+	for i := range spocs {
+		ids = append(ids, spocs[i].SpocID)
+		if spocs[i].IsPrimaryContact {
+			primaryID = &spocs[i].SpocID
+		}
+	}
+
+	var found int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM spocs WHERE id = ANY($1::bigint[])`, ids).Scan(&found); err != nil {
+		return err
+	}
+	if found != len(ids) {
+		return ErrSpocNotFound
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM drive_spocs WHERE drive_id = $1`, driveID); err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		_, err := tx.Exec(ctx, `
+        INSERT INTO drive_spocs (drive_id, spoc_id, is_primary_contact)
+        SELECT $1::bigint, s.id, COALESCE(s.id = $3::bigint, false)
+        FROM unnest($2::bigint[]) AS s(id)
+    `, driveID, ids, primaryID)
+		if err != nil {
+			return err
+		}
+	}
+	if primaryID != nil {
+		if _, err := tx.Exec(ctx, `UPDATE placement_drives SET spoc_id = $1 WHERE id = $2`, *primaryID, driveID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 3.7 Get Drive SPOCs (primary contact first), keyed by drive ID
+func (r *DriveRepository) GetDriveSpocs(ctx context.Context, driveIDs []int64) (map[int64][]models.DriveSpocDetail, error) {
+	query := `
+        SELECT ds.drive_id, s.id, s.name, COALESCE(s.designation, ''), s.mobile_number, COALESCE(s.email, ''),
+               COALESCE(s.is_active, true), s.created_at, COALESCE(ds.is_primary_contact, false)
+        FROM drive_spocs ds
+        JOIN spocs s ON s.id = ds.spoc_id
+        WHERE ds.drive_id = ANY($1::bigint[])
+        ORDER BY ds.drive_id, ds.is_primary_contact DESC NULLS LAST, s.name
+    `
+	rows, err := r.DB.Query(ctx, query, driveIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	spocs := make(map[int64][]models.DriveSpocDetail)
+	for rows.Next() {
+		var driveID int64
+		var s models.DriveSpocDetail
+		if err := rows.Scan(&driveID, &s.ID, &s.Name, &s.Designation, &s.MobileNumber, &s.Email,
+			&s.IsActive, &s.CreatedAt, &s.IsPrimaryContact); err != nil {
+			return nil, err
+		}
+		spocs[driveID] = append(spocs[driveID], s)
+	}
+	return spocs, rows.Err()
+}
+
+// attachDriveSpocs fills Spocs on a page of drives with one query
+func (r *DriveRepository) attachDriveSpocs(ctx context.Context, drives []models.PlacementDrive) error {
+	if len(drives) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(drives))
+	for i := range drives {
+		ids[i] = drives[i].ID
+	}
+	spocs, err := r.GetDriveSpocs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range drives {
+		drives[i].Spocs = spocs[drives[i].ID]
+		if drives[i].Spocs == nil {
+			drives[i].Spocs = []models.DriveSpocDetail{}
+		}
+	}
+	return nil
+}
+
+// 3.8 Get SPOC Drives (every drive a SPOC is attached to, latest first)
+func (r *DriveRepository) GetSpocDrives(ctx context.Context, spocID int64) ([]models.SpocDrive, error) {
+	query := `
+        SELECT pd.id, pd.company_name, pd.job_role, pd.status, pd.drive_date, pd.deadline_date,
+               COALESCE(ds.is_primary_contact, false)
+        FROM drive_spocs ds
+        JOIN placement_drives pd ON pd.id = ds.drive_id
+        WHERE ds.spoc_id = $1
+        ORDER BY COALESCE(pd.drive_date, pd.deadline_date::date) DESC, pd.id DESC
+    `
+	rows, err := r.DB.Query(ctx, query, spocID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	drives := []models.SpocDrive{}
+	for rows.Next() {
+		var d models.SpocDrive
+		if err := rows.Scan(&d.DriveID, &d.CompanyName, &d.JobRole, &d.Status, &d.DriveDate, &d.DeadlineDate,
+			&d.IsPrimaryContact); err != nil {
+			return nil, err
+		}
+		drives = append(drives, d)
+	}
+	return drives, rows.Err()
+}
+
 // 4. Delete Drive (Hard Delete - Cascades to applications if configured in DB)
 func (r *DriveRepository) DeleteDrive(ctx context.Context, id int64) error {
 	query := `DELETE FROM placement_drives WHERE id = $1`
@@ -356,17 +503,13 @@ func (r *DriveRepository) GetDrivesByIDs(ctx context.Context, ids []int64) ([]mo
 }
 
 // 5. Admin Force Add (Bypasses Deadline & Eligibility Checks)
-func (r *DriveRepository) AdminForceRegister(ctx context.Context, driveID, studentID int64) error {
-	// We insert directly, ignoring the Stored Procedure checks
-	// ON CONFLICT: If they are already applied, do nothing (idempotent)
-	query := `
-        INSERT INTO drive_applications (drive_id, student_id, status, applied_at)
-        VALUES ($1, $2, 'opted_in', NOW())
-        ON CONFLICT (drive_id, student_id) 
-        DO UPDATE SET status = 'opted_in' -- Reactivate if they were 'withdrawn'
-    `
-	_, err := r.DB.Exec(ctx, query, driveID, studentID)
-	return err
+// Idempotent: an existing application is moved back to 'opted_in' (reactivates a withdrawal) if the
+// transition graph allows it, or with override. Returns the previous status ("" for a new application).
+func (r *DriveRepository) AdminForceRegister(ctx context.Context, driveID, studentID int64, actor models.StatusActor, remark string, override bool) (models.ApplicationStatus, error) {
+	if remark == "" {
+		remark = "Added by admin"
+	}
+	return NewApplicationRepository(r.DB).setStatus(ctx, driveID, studentID, models.StatusOptedIn, actor, remark, override, true)
 }
 
 // AutoCloseExpiredDrives checks for any drives past their deadline and closes them.
@@ -447,13 +590,16 @@ func (r *DriveRepository) GetHomePageDrives(ctx context.Context) (map[string][]m
 	return categories, nil
 }
 
-func (r *DriveRepository) UpdateApplicationStatus(ctx context.Context, driveID, studentID int64, status string) error {
+// SetApplicationOffer records the package and/or offer letter of a placed student; zero values keep what's stored
+func (r *DriveRepository) SetApplicationOffer(ctx context.Context, driveID, studentID, packageOffered int64, offerLetterURL string) error {
 	query := `
-        UPDATE drive_applications 
-        SET status = $1, updated_at = NOW() 
-        WHERE drive_id = $2 AND student_id = $3
+        UPDATE drive_applications
+        SET package_offered = COALESCE(NULLIF($1::bigint, 0), package_offered),
+            offer_letter_url = COALESCE(NULLIF($2::text, ''), offer_letter_url),
+            updated_at = NOW()
+        WHERE drive_id = $3 AND student_id = $4
     `
-	_, err := r.DB.Exec(ctx, query, status, driveID, studentID)
+	_, err := r.DB.Exec(ctx, query, packageOffered, offerLetterURL, driveID, studentID)
 	return err
 }
 
@@ -579,3 +725,41 @@ func (r *DriveRepository) GetEligibleStudentPhoneNumbers(ctx context.Context, dr
 	}
 	return numbers, nil
 }
+
+// GetEligibleStudentContacts fetches name and email of students matching drive criteria (for email alerts)
+func (r *DriveRepository) GetEligibleStudentContacts(ctx context.Context, drive models.PlacementDrive) ([]models.StudentContact, error) {
+	query := `
+		SELECT u.id, sp.full_name, u.email
+		FROM users u
+		JOIN student_personal sp ON u.id = sp.user_id
+		LEFT JOIN student_academics sa ON u.id = sa.user_id
+		WHERE u.role = 'student'
+		AND u.is_active = true
+		AND COALESCE(u.is_blocked, false) = false
+		AND COALESCE(sa.ug_cgpa, 0) >= $1
+		AND COALESCE(sa.current_backlogs, 0) <= $2
+		AND (sp.placement_willingness IS NULL OR sp.placement_willingness = 'Interested')
+		AND ($3::jsonb IS NULL OR $3::jsonb = '[]'::jsonb OR (sp.department IS NOT NULL AND $3::jsonb @> jsonb_build_array(sp.department)))
+		AND ($4::jsonb IS NULL OR $4::jsonb = '[]'::jsonb OR (sp.batch_year IS NOT NULL AND $4::jsonb @> jsonb_build_array(sp.batch_year)))
+	`
+
+	rows, err := r.DB.Query(ctx, query,
+		drive.MinCgpa,
+		drive.MaxBacklogsAllowed,
+		drive.EligibleDepartments,
+		drive.EligibleBatches,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contacts []models.StudentContact
+	for rows.Next() {
+		var sc models.StudentContact
+		if err := rows.Scan(&sc.UserID, &sc.FullName, &sc.Email); err == nil {
+			contacts = append(contacts, sc)
+		}
+	}
+	return contacts, nil
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+)
+
+// driveCtcRangeExpr buckets pd.ctc_max into the DriveCtcRanges keys
+func driveCtcRangeExpr() string {
+	var b strings.Builder
+	b.WriteString("CASE WHEN COALESCE(pd.ctc_max, 0) = 0 THEN 'undisclosed'")
+	for _, rg := range models.DriveCtcRanges {
+		if rg.Max > 0 {
+			fmt.Fprintf(&b, " WHEN pd.ctc_max < %d THEN '%s'", rg.Max, rg.Key)
+		} else {
+			fmt.Fprintf(&b, " ELSE '%s'", rg.Key)
+		}
+	}
+	b.WriteString(" END")
+	return b.String()
+}
+
+const driveLocationExpr = `COALESCE(NULLIF(lower(trim(pd.location)), ''), 'not specified')`
+
+// driveSearchWhere builds the WHERE clause for a drive search. studentID > 0 scopes it to the
+// student's eligible open drives (0 for admins). skip leaves out one facet's own filter so that
+// facet can be counted across all of its values.
+func driveSearchWhere(q models.DriveSearchQuery, studentID int64, skip string) (string, []interface{}) {
+	where := []string{"1=1"}
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if studentID > 0 {
+		// Same rules as GetEligibleDrives
+		where = append(where,
+			"pd.status = 'open'",
+			"pd.deadline_date > NOW()",
+			studentEligibleSQL(arg(studentID)+"::bigint"),
+		)
+	} else if len(q.Statuses) > 0 {
+		where = append(where, "pd.status = ANY("+arg(q.Statuses)+"::text[])")
+	}
+
+	if q.Q != "" {
+		p := arg(q.Q)
+		where = append(where, fmt.Sprintf("(pd.search_vector @@ websearch_to_tsquery('english', %s) OR pd.company_name ILIKE '%%' || %s || '%%')", p, p))
+	}
+	if skip != "drive_type" && len(q.DriveTypes) > 0 {
+		where = append(where, "pd.drive_type = ANY("+arg(q.DriveTypes)+"::text[])")
+	}
+	if skip != "company_category" && len(q.CompanyCategories) > 0 {
+		where = append(where, "pd.company_category = ANY("+arg(q.CompanyCategories)+"::text[])")
+	}
+	if skip != "location" && len(q.Locations) > 0 {
+		locations := make([]string, len(q.Locations))
+		for i, l := range q.Locations {
+			locations[i] = strings.ToLower(strings.TrimSpace(l))
+		}
+		where = append(where, driveLocationExpr+" = ANY("+arg(locations)+"::text[])")
+	}
+	if skip != "ctc_range" && len(q.CtcRanges) > 0 {
+		where = append(where, driveCtcRangeExpr()+" = ANY("+arg(q.CtcRanges)+"::text[])")
+	}
+	if q.CtcMin > 0 {
+		where = append(where, "pd.ctc_max >= "+arg(q.CtcMin)+"::bigint")
+	}
+
+	return "WHERE " + strings.Join(where, " AND "), args
+}
+
+// SearchDrives runs a full-text, faceted drive search. studentID > 0 limits it to the
+// student's eligible open drives and fills UserStatus; 0 searches every drive (admin).
+func (r *DriveRepository) SearchDrives(ctx context.Context, q models.DriveSearchQuery, studentID int64) ([]models.PlacementDrive, int64, *models.DriveSearchFacets, error) {
+	where, args := driveSearchWhere(q, studentID, "")
+
+	var total int64
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM placement_drives pd `+where, args...).Scan(&total); err != nil {
+		return nil, 0, nil, err
+	}
+
+	orderBy := "pd.deadline_date ASC, pd.id ASC"
+	sort := q.Sort
+	if sort == "" && q.Q != "" {
+		sort = "relevance"
+	}
+	switch sort {
+	case "relevance":
+		if q.Q != "" {
+			// q.Q is args[n] in the WHERE; repeat it rather than track its position
+			args = append(args, q.Q)
+			orderBy = fmt.Sprintf("ts_rank(pd.search_vector, websearch_to_tsquery('english', $%d)) DESC, pd.deadline_date ASC, pd.id ASC", len(args))
+		}
+	case "ctc":
+		orderBy = "pd.ctc_max DESC NULLS LAST, pd.deadline_date ASC, pd.id ASC"
+	case "newest":
+		orderBy = "pd.created_at DESC, pd.id DESC"
+	}
+
+	args = append(args, studentID, q.Limit, (q.Page-1)*q.Limit)
+	n := len(args)
+	query := fmt.Sprintf(`
+        SELECT
+            pd.id, pd.posted_by, pd.company_name, pd.job_role, pd.job_description, pd.location,
+            pd.drive_type, pd.company_category, pd.spoc_id,
+            pd.ctc_min, pd.ctc_max, pd.ctc_display, pd.stipend_min, pd.stipend_max,
+            pd.min_cgpa, pd.max_backlogs_allowed,
+            COALESCE(pd.eligible_batches, '[]'::jsonb), COALESCE(pd.eligible_departments, '[]'::jsonb),
+            COALESCE(pd.rounds, '[]'::jsonb), COALESCE(pd.attachments, '[]'::jsonb),
+            pd.drive_date, pd.deadline_date, pd.website, pd.logo_url, pd.status, pd.created_at,
+            (SELECT COUNT(*) FROM drive_applications a WHERE a.drive_id = pd.id) as applicant_count,
+            COALESCE(da.status, '') as user_status
+        FROM placement_drives pd
+        LEFT JOIN drive_applications da ON pd.id = da.drive_id AND da.student_id = $%d
+        %s
+        ORDER BY %s
+        LIMIT $%d OFFSET $%d`, n-2, where, orderBy, n-1, n)
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	defer rows.Close()
+
+	drives := []models.PlacementDrive{}
+	for rows.Next() {
+		var d models.PlacementDrive
+		err := rows.Scan(
+			&d.ID, &d.PostedBy, &d.CompanyName, &d.JobRole, &d.JobDescription, &d.Location,
+			&d.DriveType, &d.CompanyCategory, &d.SpocID,
+			&d.CtcMin, &d.CtcMax, &d.CtcDisplay, &d.StipendMin, &d.StipendMax,
+			&d.MinCgpa, &d.MaxBacklogsAllowed,
+			&d.EligibleBatches, &d.EligibleDepartments,
+			&d.Rounds, &d.Attachments,
+			&d.DriveDate, &d.DeadlineDate, &d.Website, &d.LogoURL, &d.Status, &d.CreatedAt,
+			&d.ApplicantCount, &d.UserStatus,
+		)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		if studentID > 0 {
+			d.ApplicantCount = 0 // Students don't see application counts
+		}
+		drives = append(drives, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, nil, err
+	}
+	if err := r.attachDriveSpocs(ctx, drives); err != nil {
+		return nil, 0, nil, err
+	}
+
+	facets, err := r.driveSearchFacets(ctx, q, studentID)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	return drives, total, facets, nil
+}
+
+// driveSearchFacets counts drives per facet value, each facet ignoring its own filter
+func (r *DriveRepository) driveSearchFacets(ctx context.Context, q models.DriveSearchQuery, studentID int64) (*models.DriveSearchFacets, error) {
+	// label is shown for the value, e.g. the most common spelling of a location
+	count := func(facet, expr, label string) ([]models.FacetCount, error) {
+		where, args := driveSearchWhere(q, studentID, facet)
+		rows, err := r.DB.Query(ctx, fmt.Sprintf(`
+            SELECT %s AS value, %s, COUNT(*)
+            FROM placement_drives pd
+            %s
+            GROUP BY 1
+            ORDER BY 3 DESC, 1 ASC`, expr, label, where), args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		counts := []models.FacetCount{}
+		for rows.Next() {
+			var fc models.FacetCount
+			if err := rows.Scan(&fc.Value, &fc.Label, &fc.Count); err != nil {
+				return nil, err
+			}
+			counts = append(counts, fc)
+		}
+		return counts, rows.Err()
+	}
+
+	var f models.DriveSearchFacets
+	var err error
+	if f.DriveType, err = count("drive_type", "COALESCE(pd.drive_type, 'Other')", "''"); err != nil {
+		return nil, err
+	}
+	if f.CompanyCategory, err = count("company_category", "COALESCE(pd.company_category, 'Other')", "''"); err != nil {
+		return nil, err
+	}
+	if f.Location, err = count("location", driveLocationExpr, "COALESCE(mode() WITHIN GROUP (ORDER BY NULLIF(trim(pd.location), '')), 'Not specified')"); err != nil {
+		return nil, err
+	}
+	ctc, err := count("ctc_range", driveCtcRangeExpr(), "''")
+	if err != nil {
+		return nil, err
+	}
+
+	// CTC buckets keep their natural order, with labels
+	byKey := map[string]int64{}
+	for _, fc := range ctc {
+		byKey[fc.Value] = fc.Count
+	}
+	f.CtcRange = []models.FacetCount{}
+	for _, rg := range models.DriveCtcRanges {
+		f.CtcRange = append(f.CtcRange, models.FacetCount{Value: rg.Key, Label: rg.Label, Count: byKey[rg.Key]})
+	}
+	if n := byKey["undisclosed"]; n > 0 {
+		f.CtcRange = append(f.CtcRange, models.FacetCount{Value: "undisclosed", Label: "Not disclosed", Count: n})
+	}
+	return &f, nil
+}
@@ -0,0 +1,23 @@
+package repository
+
+// driveEligibilitySQL is the one definition of "student is eligible for drive" shared by eligible
+// drives, drive search, reminders and the no-show penalty. It expects the drive as pd,
+// student_personal as sp and a LEFT JOINed student_academics as sa. An empty (or NULL)
+// department or batch list means every department or batch.
+const driveEligibilitySQL = `(
+            COALESCE(sa.ug_cgpa, 0) >= COALESCE(pd.min_cgpa, 0)
+            AND COALESCE(sa.current_backlogs, 0) <= COALESCE(pd.max_backlogs_allowed, 0)
+            AND (pd.eligible_departments IS NULL OR pd.eligible_departments IN ('null', '[]') OR pd.eligible_departments @> jsonb_build_array(sp.department))
+            AND (pd.eligible_batches IS NULL OR pd.eligible_batches IN ('null', '[]') OR pd.eligible_batches @> jsonb_build_array(sp.batch_year))
+        )`
+
+// studentEligibleSQL is driveEligibilitySQL for one student, given as a SQL expression
+// (a placeholder like "$1" or a column), for queries that only have the drive as pd
+func studentEligibleSQL(student string) string {
+	return `EXISTS (
+            SELECT 1 FROM student_personal sp
+            LEFT JOIN student_academics sa ON sa.user_id = sp.user_id
+            WHERE sp.user_id = ` + student + `
+            AND ` + driveEligibilitySQL + `
+        )`
+}
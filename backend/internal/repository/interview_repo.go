@@ -0,0 +1,430 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrSlotNotFound    = errors.New("interview slot not found")
+	ErrSlotFull        = errors.New("interview slot is full")
+	ErrSlotStarted     = errors.New("interview slot has already started")
+	ErrNotShortlisted  = errors.New("student is not shortlisted for this drive")
+	ErrSlotHasBookings = errors.New("interview slot has bookings")
+)
+
+type InterviewRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewInterviewRepository(db *pgxpool.Pool) *InterviewRepository {
+	return &InterviewRepository{DB: db}
+}
+
+const interviewSlotColumns = `
+            s.id, s.drive_id, s.round_index, COALESCE(s.round_name, ''), s.panel_name, COALESCE(s.panel_emails, '[]'::jsonb),
+            COALESCE(s.location, ''), COALESCE(s.meeting_link, ''), s.start_time, s.end_time, s.capacity,
+            (SELECT COUNT(*) FROM interview_bookings b WHERE b.slot_id = s.id)`
+
+func scanInterviewSlot(row pgx.Row) (*models.InterviewSlot, error) {
+	var s models.InterviewSlot
+	err := row.Scan(
+		&s.ID, &s.DriveID, &s.RoundIndex, &s.RoundName, &s.PanelName, &s.PanelEmails,
+		&s.Location, &s.MeetingLink, &s.StartTime, &s.EndTime, &s.Capacity, &s.BookedCount,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// CreateSlots inserts a batch of slots for a drive in one transaction
+func (r *InterviewRepository) CreateSlots(ctx context.Context, driveID int64, slots []models.InterviewSlot) ([]models.InterviewSlot, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+        INSERT INTO interview_slots (drive_id, round_index, round_name, panel_name, panel_emails, location, meeting_link, start_time, end_time, capacity)
+        VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9, $10)
+        RETURNING id
+    `
+	created := make([]models.InterviewSlot, 0, len(slots))
+	for _, s := range slots {
+		if s.PanelEmails == nil {
+			s.PanelEmails = []string{}
+		}
+		s.DriveID = driveID
+		err := tx.QueryRow(ctx, query,
+			driveID, s.RoundIndex, s.RoundName, s.PanelName, s.PanelEmails,
+			s.Location, s.MeetingLink, s.StartTime, s.EndTime, s.Capacity,
+		).Scan(&s.ID)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, s)
+	}
+
+	return created, tx.Commit(ctx)
+}
+
+// GetSlot fetches a slot (without bookings). Returns nil if not found.
+func (r *InterviewRepository) GetSlot(ctx context.Context, id int64) (*models.InterviewSlot, error) {
+	query := `SELECT ` + interviewSlotColumns + ` FROM interview_slots s WHERE s.id = $1`
+	slot, err := scanInterviewSlot(r.DB.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return slot, err
+}
+
+// UpdateSlot changes a slot's details. Capacity can't drop below the number of students already booked.
+func (r *InterviewRepository) UpdateSlot(ctx context.Context, s models.InterviewSlot) error {
+	if s.PanelEmails == nil {
+		s.PanelEmails = []string{}
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	booked, err := lockSlotAndCountBookings(ctx, tx, s.ID)
+	if err != nil {
+		return err
+	}
+	if s.Capacity < booked {
+		return ErrSlotFull
+	}
+
+	query := `
+        UPDATE interview_slots
+        SET round_index = $2, round_name = $3, panel_name = $4, panel_emails = $5, location = NULLIF($6, ''),
+            meeting_link = NULLIF($7, ''), start_time = $8, end_time = $9, capacity = $10
+        WHERE id = $1
+    `
+	if _, err := tx.Exec(ctx, query,
+		s.ID, s.RoundIndex, s.RoundName, s.PanelName, s.PanelEmails,
+		s.Location, s.MeetingLink, s.StartTime, s.EndTime, s.Capacity,
+	); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// lockSlotAndCountBookings locks a slot row, then counts its bookings in a separate statement.
+// Under READ COMMITTED a statement's snapshot is taken before it waits for the lock, so a count
+// in the locking statement itself could miss a booking committed while it waited.
+func lockSlotAndCountBookings(ctx context.Context, tx pgx.Tx, slotID int64) (int, error) {
+	err := tx.QueryRow(ctx, `SELECT id FROM interview_slots WHERE id = $1 FOR UPDATE`, slotID).Scan(&slotID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrSlotNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	var booked int
+	err = tx.QueryRow(ctx, `SELECT COUNT(*) FROM interview_bookings WHERE slot_id = $1`, slotID).Scan(&booked)
+	return booked, err
+}
+
+// DeleteSlot removes a slot that nobody is booked into
+func (r *InterviewRepository) DeleteSlot(ctx context.Context, id int64) error {
+	query := `
+        DELETE FROM interview_slots s
+        WHERE s.id = $1
+        AND NOT EXISTS (SELECT 1 FROM interview_bookings b WHERE b.slot_id = s.id)
+    `
+	tag, err := r.DB.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrSlotHasBookings
+	}
+	return nil
+}
+
+// ListSlots returns a drive's slots ordered by time, optionally for one round and with booked students
+func (r *InterviewRepository) ListSlots(ctx context.Context, driveID int64, roundIndex *int, withBookings bool) ([]models.InterviewSlot, error) {
+	query := `
+        SELECT ` + interviewSlotColumns + `
+        FROM interview_slots s
+        WHERE s.drive_id = $1 AND ($2::int IS NULL OR s.round_index = $2::int)
+        ORDER BY s.round_index, s.start_time, s.panel_name
+    `
+	rows, err := r.DB.Query(ctx, query, driveID, roundIndex)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	slots := []models.InterviewSlot{}
+	index := make(map[int64]int)
+	for rows.Next() {
+		s, err := scanInterviewSlot(rows)
+		if err != nil {
+			return nil, err
+		}
+		index[s.ID] = len(slots)
+		slots = append(slots, *s)
+	}
+	rows.Close()
+
+	if !withBookings || len(slots) == 0 {
+		return slots, nil
+	}
+
+	bookingRows, err := r.DB.Query(ctx, `
+        SELECT b.slot_id, b.student_id, COALESCE(sp.full_name, ''), COALESCE(sp.register_number, ''), u.email, b.source, b.booked_at
+        FROM interview_bookings b
+        JOIN interview_slots s ON s.id = b.slot_id
+        JOIN users u ON u.id = b.student_id
+        LEFT JOIN student_personal sp ON sp.user_id = b.student_id
+        WHERE s.drive_id = $1 AND ($2::int IS NULL OR s.round_index = $2::int)
+        ORDER BY b.slot_id, sp.register_number
+    `, driveID, roundIndex)
+	if err != nil {
+		return nil, err
+	}
+	defer bookingRows.Close()
+
+	for bookingRows.Next() {
+		var b models.InterviewBooking
+		if err := bookingRows.Scan(&b.SlotID, &b.StudentID, &b.FullName, &b.RegisterNumber, &b.Email, &b.Source, &b.BookedAt); err != nil {
+			return nil, err
+		}
+		if i, ok := index[b.SlotID]; ok {
+			slots[i].Bookings = append(slots[i].Bookings, b)
+		}
+	}
+	return slots, nil
+}
+
+// AllocateShortlisted fills a round's upcoming slots, earliest first, with shortlisted students who don't have
+// a slot in that round yet (in register number order). Returns the newly booked students and how many are left over.
+func (r *InterviewRepository) AllocateShortlisted(ctx context.Context, driveID int64, roundIndex int) ([]models.InterviewBooking, int, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Lock the round's slots so concurrent student bookings can't overfill them, then count
+	// free seats in a second statement that sees every booking committed before the lock
+	var slotIDs []int64
+	lockRows, err := tx.Query(ctx, `
+        SELECT id FROM interview_slots
+        WHERE drive_id = $1 AND round_index = $2 AND start_time > NOW()
+        ORDER BY id
+        FOR UPDATE
+    `, driveID, roundIndex)
+	if err != nil {
+		return nil, 0, err
+	}
+	for lockRows.Next() {
+		var id int64
+		if err := lockRows.Scan(&id); err != nil {
+			lockRows.Close()
+			return nil, 0, err
+		}
+		slotIDs = append(slotIDs, id)
+	}
+	lockRows.Close()
+	if err := lockRows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	slotRows, err := tx.Query(ctx, `
+        SELECT s.id, s.capacity - (SELECT COUNT(*) FROM interview_bookings b WHERE b.slot_id = s.id)
+        FROM interview_slots s
+        WHERE s.id = ANY($1)
+        ORDER BY s.start_time, s.id
+    `, slotIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	type freeSlot struct {
+		id   int64
+		free int
+	}
+	var free []freeSlot
+	for slotRows.Next() {
+		var fs freeSlot
+		if err := slotRows.Scan(&fs.id, &fs.free); err != nil {
+			slotRows.Close()
+			return nil, 0, err
+		}
+		if fs.free > 0 {
+			free = append(free, fs)
+		}
+	}
+	slotRows.Close()
+	if err := slotRows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// 2. Shortlisted students without a slot in this round
+	studentRows, err := tx.Query(ctx, `
+        SELECT da.student_id, COALESCE(sp.full_name, ''), COALESCE(sp.register_number, ''), u.email
+        FROM drive_applications da
+        JOIN users u ON u.id = da.student_id
+        LEFT JOIN student_personal sp ON sp.user_id = da.student_id
+        WHERE da.drive_id = $1 AND da.status = 'shortlisted'
+        AND NOT EXISTS (
+            SELECT 1 FROM interview_bookings b
+            WHERE b.drive_id = $1 AND b.round_index = $2 AND b.student_id = da.student_id
+        )
+        ORDER BY sp.register_number, da.student_id
+    `, driveID, roundIndex)
+	if err != nil {
+		return nil, 0, err
+	}
+	var pending []models.InterviewBooking
+	for studentRows.Next() {
+		var b models.InterviewBooking
+		if err := studentRows.Scan(&b.StudentID, &b.FullName, &b.RegisterNumber, &b.Email); err != nil {
+			studentRows.Close()
+			return nil, 0, err
+		}
+		pending = append(pending, b)
+	}
+	studentRows.Close()
+	if err := studentRows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// 3. Fill slots in order
+	insert := `
+        INSERT INTO interview_bookings (slot_id, drive_id, round_index, student_id, source, booked_at)
+        VALUES ($1, $2, $3, $4, 'auto', NOW())
+        RETURNING booked_at
+    `
+	var allocated []models.InterviewBooking
+	i := 0
+	for _, fs := range free {
+		for n := 0; n < fs.free && i < len(pending); n++ {
+			b := pending[i]
+			b.SlotID = fs.id
+			b.Source = models.BookingSourceAuto
+			if err := tx.QueryRow(ctx, insert, fs.id, driveID, roundIndex, b.StudentID).Scan(&b.BookedAt); err != nil {
+				return nil, 0, err
+			}
+			allocated = append(allocated, b)
+			i++
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, 0, err
+	}
+	return allocated, len(pending) - i, nil
+}
+
+// BookSlot books a shortlisted student into a slot, replacing any slot they held in the same round
+func (r *InterviewRepository) BookSlot(ctx context.Context, slotID, studentID int64) (*models.InterviewSlot, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 1. Lock the slot so two students can't take its last seat, then read it (and its booked
+	// count) after the lock is held
+	if _, err := lockSlotAndCountBookings(ctx, tx, slotID); err != nil {
+		return nil, err
+	}
+	slot, err := scanInterviewSlot(tx.QueryRow(ctx, `SELECT `+interviewSlotColumns+` FROM interview_slots s WHERE s.id = $1`, slotID))
+	if err != nil {
+		return nil, err
+	}
+
+	var shortlisted, started, alreadyHere bool
+	err = tx.QueryRow(ctx, `
+        SELECT
+            EXISTS (SELECT 1 FROM drive_applications WHERE drive_id = $1 AND student_id = $2 AND status = 'shortlisted'),
+            (SELECT start_time <= NOW() FROM interview_slots WHERE id = $3),
+            EXISTS (SELECT 1 FROM interview_bookings WHERE slot_id = $3 AND student_id = $2)
+    `, slot.DriveID, studentID, slotID).Scan(&shortlisted, &started, &alreadyHere)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !shortlisted:
+		return nil, ErrNotShortlisted
+	case started:
+		return nil, ErrSlotStarted
+	case alreadyHere:
+		return slot, nil
+	case slot.BookedCount >= slot.Capacity:
+		return nil, ErrSlotFull
+	}
+
+	// 2. Move the student: one slot per round
+	if _, err := tx.Exec(ctx, `
+        DELETE FROM interview_bookings
+        WHERE drive_id = $1 AND round_index = $2 AND student_id = $3
+    `, slot.DriveID, slot.RoundIndex, studentID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, `
+        INSERT INTO interview_bookings (slot_id, drive_id, round_index, student_id, source, booked_at)
+        VALUES ($1, $2, $3, $4, 'student', NOW())
+    `, slotID, slot.DriveID, slot.RoundIndex, studentID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	slot.BookedCount++
+	return slot, nil
+}
+
+// GetStudentInterviews lists a student's booked slots across drives, soonest first
+func (r *InterviewRepository) GetStudentInterviews(ctx context.Context, studentID int64) ([]models.StudentInterview, error) {
+	query := `
+        SELECT s.id, s.drive_id, pd.company_name, pd.job_role, s.round_index, COALESCE(s.round_name, ''), s.panel_name,
+               COALESCE(s.location, ''), COALESCE(s.meeting_link, ''), s.start_time, s.end_time, b.source, b.booked_at
+        FROM interview_bookings b
+        JOIN interview_slots s ON s.id = b.slot_id
+        JOIN placement_drives pd ON pd.id = s.drive_id
+        WHERE b.student_id = $1
+        ORDER BY s.start_time
+    `
+	rows, err := r.DB.Query(ctx, query, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	interviews := []models.StudentInterview{}
+	for rows.Next() {
+		var i models.StudentInterview
+		if err := rows.Scan(
+			&i.SlotID, &i.DriveID, &i.CompanyName, &i.JobRole, &i.RoundIndex, &i.RoundName, &i.PanelName,
+			&i.Location, &i.MeetingLink, &i.StartTime, &i.EndTime, &i.Source, &i.BookedAt,
+		); err != nil {
+			return nil, err
+		}
+		interviews = append(interviews, i)
+	}
+	return interviews, nil
+}
+
+// IsShortlisted reports whether a student is shortlisted for a drive
+func (r *InterviewRepository) IsShortlisted(ctx context.Context, driveID, studentID int64) (bool, error) {
+	var ok bool
+	err := r.DB.QueryRow(ctx, `
+        SELECT EXISTS (SELECT 1 FROM drive_applications WHERE drive_id = $1 AND student_id = $2 AND status = 'shortlisted')
+    `, driveID, studentID).Scan(&ok)
+	return ok, err
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type JobRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewJobRepository(db *pgxpool.Pool) *JobRepository {
+	return &JobRepository{DB: db}
+}
+
+// TryLockJob takes the cluster-wide advisory lock for a job so only one instance runs it at a time.
+// Advisory locks belong to a session, so the connection is held until UnlockJob is called.
+// Returns nil (and no error) if another instance holds the lock.
+func (r *JobRepository) TryLockJob(ctx context.Context, name string) (*pgxpool.Conn, error) {
+	conn, err := r.DB.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext('job:' || $1::text))`, name).Scan(&locked); err != nil {
+		conn.Release()
+		return nil, err
+	}
+	if !locked {
+		conn.Release()
+		return nil, nil
+	}
+	return conn, nil
+}
+
+// UnlockJob releases a lock taken by TryLockJob and returns the connection to the pool
+func (r *JobRepository) UnlockJob(conn *pgxpool.Conn, name string) error {
+	defer conn.Release()
+
+	// Use a fresh context: the job's own context may already be cancelled by its timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := conn.Exec(ctx, `SELECT pg_advisory_unlock(hashtext('job:' || $1::text))`, name)
+	if err != nil {
+		// Don't hand a connection that may still hold the lock back to the pool
+		conn.Conn().Close(ctx)
+	}
+	return err
+}
+
+// StartRun records the start of a job run. Scheduled runs pass the cron slot they are for, and
+// each slot is claimed once: returns nil (and no error) if another instance already ran it.
+func (r *JobRepository) StartRun(ctx context.Context, name, trigger, instance string, scheduledFor *time.Time) (*models.JobRun, error) {
+	query := `
+        INSERT INTO job_runs (job_name, trigger, status, instance, scheduled_for, started_at)
+        VALUES ($1, $2, 'running', $3, $4, NOW())
+        ON CONFLICT (job_name, scheduled_for) DO NOTHING
+        RETURNING id, job_name, trigger, status, instance, started_at
+    `
+	var run models.JobRun
+	err := r.DB.QueryRow(ctx, query, name, trigger, instance, scheduledFor).Scan(
+		&run.ID, &run.JobName, &run.Trigger, &run.Status, &run.Instance, &run.StartedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// FinishRun records the outcome of a job run
+func (r *JobRepository) FinishRun(ctx context.Context, id int64, status, result, errMsg string) error {
+	query := `
+        UPDATE job_runs
+        SET status = $2, result = NULLIF($3, ''), error = NULLIF($4, ''), finished_at = NOW(),
+            duration_ms = (EXTRACT(EPOCH FROM (NOW() - started_at)) * 1000)::BIGINT
+        WHERE id = $1
+    `
+	_, err := r.DB.Exec(ctx, query, id, status, result, errMsg)
+	return err
+}
+
+// GetLastRuns returns the most recent run of every job, keyed by job name
+func (r *JobRepository) GetLastRuns(ctx context.Context) (map[string]models.JobRun, error) {
+	query := `
+        SELECT DISTINCT ON (job_name)
+               id, job_name, trigger, status, COALESCE(result, ''), COALESCE(error, ''), instance,
+               started_at, finished_at, duration_ms
+        FROM job_runs
+        ORDER BY job_name, started_at DESC
+    `
+	rows, err := r.DB.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := make(map[string]models.JobRun)
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs[run.JobName] = *run
+	}
+	return runs, nil
+}
+
+// ListRuns returns the run history of a job, newest first
+func (r *JobRepository) ListRuns(ctx context.Context, name string, limit, offset int) ([]models.JobRun, int64, error) {
+	var total int64
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM job_runs WHERE job_name = $1`, name).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+        SELECT id, job_name, trigger, status, COALESCE(result, ''), COALESCE(error, ''), instance,
+               started_at, finished_at, duration_ms
+        FROM job_runs
+        WHERE job_name = $1
+        ORDER BY started_at DESC
+        LIMIT $2 OFFSET $3
+    `
+	rows, err := r.DB.Query(ctx, query, name, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	runs := []models.JobRun{}
+	for rows.Next() {
+		run, err := scanJobRun(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		runs = append(runs, *run)
+	}
+	return runs, total, nil
+}
+
+// GetRun fetches a single run
+func (r *JobRepository) GetRun(ctx context.Context, id int64) (*models.JobRun, error) {
+	query := `
+        SELECT id, job_name, trigger, status, COALESCE(result, ''), COALESCE(error, ''), instance,
+               started_at, finished_at, duration_ms
+        FROM job_runs
+        WHERE id = $1
+    `
+	run, err := scanJobRun(r.DB.QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return run, err
+}
+
+// CleanupRuns deletes old history and fails runs left 'running' by an instance that died mid-run
+func (r *JobRepository) CleanupRuns(ctx context.Context, retention, staleAfter time.Duration) (int64, error) {
+	_, err := r.DB.Exec(ctx, `
+        UPDATE job_runs
+        SET status = 'failed', error = 'abandoned: instance stopped before the run finished', finished_at = NOW()
+        WHERE status = 'running' AND started_at < $1
+    `, time.Now().Add(-staleAfter))
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := r.DB.Exec(ctx, `DELETE FROM job_runs WHERE started_at < $1`, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanJobRun(row pgx.Row) (*models.JobRun, error) {
+	var run models.JobRun
+	err := row.Scan(
+		&run.ID, &run.JobName, &run.Trigger, &run.Status, &run.Result, &run.Error, &run.Instance,
+		&run.StartedAt, &run.FinishedAt, &run.DurationMs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
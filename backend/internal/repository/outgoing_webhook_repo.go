@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutgoingWebhookRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewOutgoingWebhookRepository(db *pgxpool.Pool) *OutgoingWebhookRepository {
+	return &OutgoingWebhookRepository{DB: db}
+}
+
+// CreateWebhook registers a subscription and returns it with its ID
+func (r *OutgoingWebhookRepository) CreateWebhook(ctx context.Context, w models.OutgoingWebhook) (*models.OutgoingWebhook, error) {
+	query := `
+        INSERT INTO outgoing_webhooks (name, url, secret, event_types, is_active, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+        RETURNING id, created_at, updated_at
+    `
+	err := r.DB.QueryRow(ctx, query, w.Name, w.URL, w.Secret, w.EventTypes, w.IsActive).Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// ListWebhooks returns all subscriptions (secrets included, the JSON tag hides them)
+func (r *OutgoingWebhookRepository) ListWebhooks(ctx context.Context) ([]models.OutgoingWebhook, error) {
+	query := `
+        SELECT id, name, url, secret, event_types, is_active, created_at, updated_at
+        FROM outgoing_webhooks
+        ORDER BY id
+    `
+	rows, err := r.DB.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.OutgoingWebhook{}
+	for rows.Next() {
+		var w models.OutgoingWebhook
+		if err := rows.Scan(&w.ID, &w.Name, &w.URL, &w.Secret, &w.EventTypes, &w.IsActive, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhook changes a subscription. An empty secret keeps the current one.
+// Returns nil if the subscription doesn't exist.
+func (r *OutgoingWebhookRepository) UpdateWebhook(ctx context.Context, w models.OutgoingWebhook) (*models.OutgoingWebhook, error) {
+	query := `
+        UPDATE outgoing_webhooks
+        SET name = $2, url = $3, secret = COALESCE(NULLIF($4, ''), secret), event_types = $5, is_active = $6, updated_at = NOW()
+        WHERE id = $1
+        RETURNING created_at, updated_at
+    `
+	err := r.DB.QueryRow(ctx, query, w.ID, w.Name, w.URL, w.Secret, w.EventTypes, w.IsActive).Scan(&w.CreatedAt, &w.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// DeleteWebhook removes a subscription and its delivery log
+func (r *OutgoingWebhookRepository) DeleteWebhook(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM outgoing_webhooks WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// EnqueueEvent creates a pending delivery for every active subscription listening to the event type.
+// Returns the number of deliveries created.
+func (r *OutgoingWebhookRepository) EnqueueEvent(ctx context.Context, eventID, eventType string, payload []byte) (int64, error) {
+	query := `
+        INSERT INTO outgoing_webhook_deliveries (webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+        SELECT id, $1, $2::text, $3::jsonb, 'pending', 0, NOW(), NOW()
+        FROM outgoing_webhooks
+        WHERE is_active = true AND event_types ? $2::text
+    `
+	tag, err := r.DB.Exec(ctx, query, eventID, eventType, string(payload))
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ClaimDueDeliveries picks pending deliveries whose retry time has come and leases them for a few
+// minutes so a concurrent worker (or instance) doesn't send them twice.
+func (r *OutgoingWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int) ([]models.OutgoingWebhookDelivery, error) {
+	query := `
+        WITH due AS (
+            SELECT d.id
+            FROM outgoing_webhook_deliveries d
+            JOIN outgoing_webhooks w ON w.id = d.webhook_id
+            WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.is_active = true
+            ORDER BY d.next_attempt_at
+            LIMIT $1
+            FOR UPDATE OF d SKIP LOCKED
+        )
+        UPDATE outgoing_webhook_deliveries d
+        SET next_attempt_at = NOW() + INTERVAL '5 minutes'
+        FROM due, outgoing_webhooks w
+        WHERE d.id = due.id AND w.id = d.webhook_id
+        RETURNING d.id, d.webhook_id, d.event_id, d.event_type, d.payload::text, d.attempts, w.url, w.secret
+    `
+	rows, err := r.DB.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.OutgoingWebhookDelivery
+	for rows.Next() {
+		var d models.OutgoingWebhookDelivery
+		var payload string
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &payload, &d.Attempts, &d.URL, &d.Secret); err != nil {
+			return nil, err
+		}
+		d.Payload = []byte(payload)
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt stores the outcome of one send.
+// delivered -> 'delivered'; otherwise 'pending' until nextAttempt, or 'dead' when nextAttempt is nil.
+func (r *OutgoingWebhookRepository) RecordAttempt(ctx context.Context, id int64, statusCode int, errMsg string, delivered bool, nextAttempt *time.Time) error {
+	status := models.WebhookDeliveryPending
+	if delivered {
+		status = models.WebhookDeliveryDelivered
+	} else if nextAttempt == nil {
+		status = models.WebhookDeliveryDead
+	}
+
+	query := `
+        UPDATE outgoing_webhook_deliveries
+        SET status = $2::text,
+            attempts = attempts + 1,
+            last_status_code = NULLIF($3, 0),
+            last_error = NULLIF($4, ''),
+            next_attempt_at = $5,
+            delivered_at = CASE WHEN $2::text = 'delivered' THEN NOW() ELSE delivered_at END
+        WHERE id = $1
+    `
+	_, err := r.DB.Exec(ctx, query, id, status, statusCode, errMsg, nextAttempt)
+	return err
+}
+
+// ListDeliveries returns the delivery log of a subscription, newest first
+func (r *OutgoingWebhookRepository) ListDeliveries(ctx context.Context, webhookID int64, status string, limit, offset int) ([]models.OutgoingWebhookDelivery, int64, error) {
+	whereClause := "WHERE webhook_id = $1"
+	args := []interface{}{webhookID}
+	argCounter := 2
+
+	if status != "" {
+		whereClause += fmt.Sprintf(" AND status = $%d", argCounter)
+		args = append(args, status)
+		argCounter++
+	}
+
+	var total int64
+	if err := r.DB.QueryRow(ctx, "SELECT COUNT(*) FROM outgoing_webhook_deliveries "+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+        SELECT id, webhook_id, event_id, event_type, payload::text, status, attempts, next_attempt_at,
+               last_status_code, COALESCE(last_error, ''), created_at, delivered_at
+        FROM outgoing_webhook_deliveries
+        %s
+        ORDER BY created_at DESC, id DESC
+        LIMIT $%d OFFSET $%d
+    `, whereClause, argCounter, argCounter+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.OutgoingWebhookDelivery{}
+	for rows.Next() {
+		var d models.OutgoingWebhookDelivery
+		var payload string
+		if err := rows.Scan(
+			&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts, &d.NextAttemptAt,
+			&d.LastStatusCode, &d.LastError, &d.CreatedAt, &d.DeliveredAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		d.Payload = []byte(payload)
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, total, nil
+}
+
+// RetryDeadDelivery moves a dead-lettered delivery back to pending with a fresh attempt budget
+func (r *OutgoingWebhookRepository) RetryDeadDelivery(ctx context.Context, webhookID, deliveryID int64) (bool, error) {
+	query := `
+        UPDATE outgoing_webhook_deliveries
+        SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+        WHERE id = $1 AND webhook_id = $2 AND status = 'dead'
+    `
+	tag, err := r.DB.Exec(ctx, query, deliveryID, webhookID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
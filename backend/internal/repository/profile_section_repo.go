@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrSkillExists = errors.New("skill already on profile")
+
+// ProfileSectionRepository manages the structured profile sections: skills, projects,
+// internships and certifications. Every query is scoped to the owning student.
+type ProfileSectionRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewProfileSectionRepository(db *pgxpool.Pool) *ProfileSectionRepository {
+	return &ProfileSectionRepository{DB: db}
+}
+
+// GetSections loads all four sections for a profile view
+func (r *ProfileSectionRepository) GetSections(ctx context.Context, userID int64) (models.StudentProfileSections, error) {
+	var s models.StudentProfileSections
+	var err error
+	if s.Skills, err = r.ListSkills(ctx, userID); err != nil {
+		return s, err
+	}
+	if s.Projects, err = r.ListProjects(ctx, userID); err != nil {
+		return s, err
+	}
+	if s.Internships, err = r.ListInternships(ctx, userID); err != nil {
+		return s, err
+	}
+	s.Certifications, err = r.ListCertifications(ctx, userID)
+	return s, err
+}
+
+// deleteSectionRow removes one row of a section table owned by userID
+func (r *ProfileSectionRepository) deleteSectionRow(ctx context.Context, table string, userID, id int64) (bool, error) {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM `+table+` WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// --- Skills ---
+
+const skillColumns = `id, name, proficiency, created_at`
+
+func scanSkill(row pgx.Row) (*models.StudentSkill, error) {
+	var s models.StudentSkill
+	if err := row.Scan(&s.ID, &s.Name, &s.Proficiency, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListSkills returns a student's skills, strongest first
+func (r *ProfileSectionRepository) ListSkills(ctx context.Context, userID int64) ([]models.StudentSkill, error) {
+	rows, err := r.DB.Query(ctx, `
+        SELECT `+skillColumns+`
+        FROM student_skills
+        WHERE user_id = $1
+        ORDER BY array_position($2::text[], proficiency) DESC, name ASC
+    `, userID, models.SkillProficiencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	skills := []models.StudentSkill{}
+	for rows.Next() {
+		s, err := scanSkill(rows)
+		if err != nil {
+			return nil, err
+		}
+		skills = append(skills, *s)
+	}
+	return skills, rows.Err()
+}
+
+func (r *ProfileSectionRepository) CreateSkill(ctx context.Context, userID int64, input models.StudentSkillInput) (*models.StudentSkill, error) {
+	s, err := scanSkill(r.DB.QueryRow(ctx, `
+        INSERT INTO student_skills (user_id, name, proficiency)
+        VALUES ($1, $2, COALESCE(NULLIF($3, ''), 'intermediate'))
+        RETURNING `+skillColumns, userID, input.Name, input.Proficiency))
+	if isUniqueViolation(err) {
+		return nil, ErrSkillExists
+	}
+	return s, err
+}
+
+// UpdateSkill renames a skill or changes its proficiency. Returns nil if it doesn't exist.
+func (r *ProfileSectionRepository) UpdateSkill(ctx context.Context, userID, id int64, input models.StudentSkillInput) (*models.StudentSkill, error) {
+	s, err := scanSkill(r.DB.QueryRow(ctx, `
+        UPDATE student_skills
+        SET name = $1, proficiency = COALESCE(NULLIF($2, ''), proficiency)
+        WHERE id = $3 AND user_id = $4
+        RETURNING `+skillColumns, input.Name, input.Proficiency, id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if isUniqueViolation(err) {
+		return nil, ErrSkillExists
+	}
+	return s, err
+}
+
+func (r *ProfileSectionRepository) DeleteSkill(ctx context.Context, userID, id int64) (bool, error) {
+	return r.deleteSectionRow(ctx, "student_skills", userID, id)
+}
+
+// --- Projects ---
+
+const projectColumns = `
+            id, title, COALESCE(description, ''), COALESCE(tech_stack, '[]'::jsonb),
+            COALESCE(project_url, ''), COALESCE(repo_url, ''), start_date, end_date, updated_at`
+
+func scanProject(row pgx.Row) (*models.StudentProject, error) {
+	var p models.StudentProject
+	err := row.Scan(&p.ID, &p.Title, &p.Description, &p.TechStack,
+		&p.ProjectURL, &p.RepoURL, &p.StartDate, &p.EndDate, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if p.TechStack == nil {
+		p.TechStack = []string{}
+	}
+	return &p, nil
+}
+
+// ListProjects returns projects, ongoing and most recent first
+func (r *ProfileSectionRepository) ListProjects(ctx context.Context, userID int64) ([]models.StudentProject, error) {
+	rows, err := r.DB.Query(ctx, `
+        SELECT `+projectColumns+`
+        FROM student_projects
+        WHERE user_id = $1
+        ORDER BY end_date DESC NULLS FIRST, start_date DESC NULLS LAST, id DESC
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	projects := []models.StudentProject{}
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, *p)
+	}
+	return projects, rows.Err()
+}
+
+func (r *ProfileSectionRepository) CreateProject(ctx context.Context, userID int64, input models.StudentProjectInput, start, end pgtype.Date) (*models.StudentProject, error) {
+	return scanProject(r.DB.QueryRow(ctx, `
+        INSERT INTO student_projects (user_id, title, description, tech_stack, project_url, repo_url, start_date, end_date)
+        VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8)
+        RETURNING `+projectColumns,
+		userID, input.Title, input.Description, nonNilStrings(input.TechStack), input.ProjectURL, input.RepoURL, start, end))
+}
+
+// UpdateProject rewrites a project. Returns nil if it doesn't exist.
+func (r *ProfileSectionRepository) UpdateProject(ctx context.Context, userID, id int64, input models.StudentProjectInput, start, end pgtype.Date) (*models.StudentProject, error) {
+	p, err := scanProject(r.DB.QueryRow(ctx, `
+        UPDATE student_projects
+        SET title = $1, description = NULLIF($2, ''), tech_stack = $3, project_url = NULLIF($4, ''),
+            repo_url = NULLIF($5, ''), start_date = $6, end_date = $7, updated_at = NOW()
+        WHERE id = $8 AND user_id = $9
+        RETURNING `+projectColumns,
+		input.Title, input.Description, nonNilStrings(input.TechStack), input.ProjectURL, input.RepoURL, start, end, id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return p, err
+}
+
+func (r *ProfileSectionRepository) DeleteProject(ctx context.Context, userID, id int64) (bool, error) {
+	return r.deleteSectionRow(ctx, "student_projects", userID, id)
+}
+
+// --- Internships ---
+
+const internshipColumns = `
+            id, company_name, role, COALESCE(location, ''), start_date, end_date,
+            COALESCE(stipend, 0), COALESCE(description, ''), updated_at`
+
+func scanInternship(row pgx.Row) (*models.StudentInternship, error) {
+	var in models.StudentInternship
+	err := row.Scan(&in.ID, &in.CompanyName, &in.Role, &in.Location, &in.StartDate, &in.EndDate,
+		&in.Stipend, &in.Description, &in.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	in.DurationMonths = monthsBetween(in.StartDate, in.EndDate)
+	return &in, nil
+}
+
+// monthsBetween counts whole months from start to end, or to today when end is open
+func monthsBetween(start, end pgtype.Date) int {
+	if !start.Valid {
+		return 0
+	}
+	to := time.Now()
+	if end.Valid {
+		to = end.Time
+	}
+	months := (to.Year()-start.Time.Year())*12 + int(to.Month()-start.Time.Month())
+	if to.Day() < start.Time.Day() {
+		months--
+	}
+	if months < 0 {
+		return 0
+	}
+	return months
+}
+
+// ListInternships returns internships, ongoing and most recent first
+func (r *ProfileSectionRepository) ListInternships(ctx context.Context, userID int64) ([]models.StudentInternship, error) {
+	rows, err := r.DB.Query(ctx, `
+        SELECT `+internshipColumns+`
+        FROM student_internships
+        WHERE user_id = $1
+        ORDER BY end_date DESC NULLS FIRST, start_date DESC, id DESC
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	internships := []models.StudentInternship{}
+	for rows.Next() {
+		in, err := scanInternship(rows)
+		if err != nil {
+			return nil, err
+		}
+		internships = append(internships, *in)
+	}
+	return internships, rows.Err()
+}
+
+func (r *ProfileSectionRepository) CreateInternship(ctx context.Context, userID int64, input models.StudentInternshipInput, start, end pgtype.Date) (*models.StudentInternship, error) {
+	return scanInternship(r.DB.QueryRow(ctx, `
+        INSERT INTO student_internships (user_id, company_name, role, location, start_date, end_date, stipend, description)
+        VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, NULLIF($7::numeric, 0), NULLIF($8, ''))
+        RETURNING `+internshipColumns,
+		userID, input.CompanyName, input.Role, input.Location, start, end, input.Stipend, input.Description))
+}
+
+// UpdateInternship rewrites an internship. Returns nil if it doesn't exist.
+func (r *ProfileSectionRepository) UpdateInternship(ctx context.Context, userID, id int64, input models.StudentInternshipInput, start, end pgtype.Date) (*models.StudentInternship, error) {
+	in, err := scanInternship(r.DB.QueryRow(ctx, `
+        UPDATE student_internships
+        SET company_name = $1, role = $2, location = NULLIF($3, ''), start_date = $4, end_date = $5,
+            stipend = NULLIF($6::numeric, 0), description = NULLIF($7, ''), updated_at = NOW()
+        WHERE id = $8 AND user_id = $9
+        RETURNING `+internshipColumns,
+		input.CompanyName, input.Role, input.Location, start, end, input.Stipend, input.Description, id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return in, err
+}
+
+func (r *ProfileSectionRepository) DeleteInternship(ctx context.Context, userID, id int64) (bool, error) {
+	return r.deleteSectionRow(ctx, "student_internships", userID, id)
+}
+
+// --- Certifications ---
+
+const certificationColumns = `
+            id, name, issuer, issue_date, expiry_date, COALESCE(credential_id, ''),
+            COALESCE(credential_url, ''), COALESCE(proof_url, ''), updated_at`
+
+func scanCertification(row pgx.Row) (*models.StudentCertification, error) {
+	var cert models.StudentCertification
+	err := row.Scan(&cert.ID, &cert.Name, &cert.Issuer, &cert.IssueDate, &cert.ExpiryDate,
+		&cert.CredentialID, &cert.CredentialURL, &cert.ProofURL, &cert.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// ListCertifications returns certifications, most recently issued first
+func (r *ProfileSectionRepository) ListCertifications(ctx context.Context, userID int64) ([]models.StudentCertification, error) {
+	rows, err := r.DB.Query(ctx, `
+        SELECT `+certificationColumns+`
+        FROM student_certifications
+        WHERE user_id = $1
+        ORDER BY issue_date DESC NULLS LAST, id DESC
+    `, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	certs := []models.StudentCertification{}
+	for rows.Next() {
+		cert, err := scanCertification(rows)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, *cert)
+	}
+	return certs, rows.Err()
+}
+
+// GetCertification returns nil if the certification doesn't exist or belongs to someone else
+func (r *ProfileSectionRepository) GetCertification(ctx context.Context, userID, id int64) (*models.StudentCertification, error) {
+	cert, err := scanCertification(r.DB.QueryRow(ctx, `
+        SELECT `+certificationColumns+`
+        FROM student_certifications
+        WHERE id = $1 AND user_id = $2
+    `, id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return cert, err
+}
+
+func (r *ProfileSectionRepository) CreateCertification(ctx context.Context, userID int64, input models.StudentCertificationInput, issued, expires pgtype.Date) (*models.StudentCertification, error) {
+	return scanCertification(r.DB.QueryRow(ctx, `
+        INSERT INTO student_certifications (user_id, name, issuer, issue_date, expiry_date, credential_id, credential_url)
+        VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''))
+        RETURNING `+certificationColumns,
+		userID, input.Name, input.Issuer, issued, expires, input.CredentialID, input.CredentialURL))
+}
+
+// UpdateCertification rewrites a certification, keeping its proof. Returns nil if it doesn't exist.
+func (r *ProfileSectionRepository) UpdateCertification(ctx context.Context, userID, id int64, input models.StudentCertificationInput, issued, expires pgtype.Date) (*models.StudentCertification, error) {
+	cert, err := scanCertification(r.DB.QueryRow(ctx, `
+        UPDATE student_certifications
+        SET name = $1, issuer = $2, issue_date = $3, expiry_date = $4,
+            credential_id = NULLIF($5, ''), credential_url = NULLIF($6, ''), updated_at = NOW()
+        WHERE id = $7 AND user_id = $8
+        RETURNING `+certificationColumns,
+		input.Name, input.Issuer, issued, expires, input.CredentialID, input.CredentialURL, id, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return cert, err
+}
+
+// SetCertificationProof stores the uploaded proof's URL
+func (r *ProfileSectionRepository) SetCertificationProof(ctx context.Context, userID, id int64, url string) error {
+	_, err := r.DB.Exec(ctx, `
+        UPDATE student_certifications SET proof_url = $1, updated_at = NOW()
+        WHERE id = $2 AND user_id = $3
+    `, url, id, userID)
+	return err
+}
+
+func (r *ProfileSectionRepository) DeleteCertification(ctx context.Context, userID, id int64) (bool, error) {
+	return r.deleteSectionRow(ctx, "student_certifications", userID, id)
+}
+
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrEmailTaken = errors.New("email is already registered")
+
+type RecruiterRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewRecruiterRepository(db *pgxpool.Pool) *RecruiterRepository {
+	return &RecruiterRepository{DB: db}
+}
+
+const recruiterColumns = `
+            r.user_id, r.company_id, c.name, u.email, r.full_name, COALESCE(r.designation, ''), COALESCE(r.mobile_number, ''),
+            u.is_blocked, u.last_login, r.created_at`
+
+func scanRecruiter(row pgx.Row) (*models.Recruiter, error) {
+	var rc models.Recruiter
+	err := row.Scan(
+		&rc.UserID, &rc.CompanyID, &rc.CompanyName, &rc.Email, &rc.FullName, &rc.Designation, &rc.MobileNumber,
+		&rc.IsBlocked, &rc.LastLogin, &rc.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &rc, nil
+}
+
+// CreateRecruiter creates the user (role 'recruiter') and ties it to the company
+func (r *RecruiterRepository) CreateRecruiter(ctx context.Context, companyID, createdBy int64, input models.CreateRecruiterInput, passwordHash string) (int64, error) {
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int64
+	err = tx.QueryRow(ctx, `
+        INSERT INTO users (email, password_hash, role, is_active, is_blocked)
+        VALUES ($1, $2, 'recruiter', true, false)
+        RETURNING id
+    `, input.Email, passwordHash).Scan(&id)
+	if isUniqueViolation(err) {
+		return 0, ErrEmailTaken
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(ctx, `
+        INSERT INTO recruiters (user_id, company_id, full_name, designation, mobile_number, created_by)
+        VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6)
+    `, id, companyID, input.FullName, input.Designation, input.MobileNumber, createdBy)
+	if err != nil {
+		return 0, err
+	}
+	return id, tx.Commit(ctx)
+}
+
+// GetRecruiter returns a recruiter with their company, or nil if the user isn't a recruiter
+func (r *RecruiterRepository) GetRecruiter(ctx context.Context, userID int64) (*models.Recruiter, error) {
+	query := `SELECT ` + recruiterColumns + `
+        FROM recruiters r
+        JOIN users u ON u.id = r.user_id
+        JOIN companies c ON c.id = r.company_id
+        WHERE r.user_id = $1`
+	rc, err := scanRecruiter(r.DB.QueryRow(ctx, query, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return rc, err
+}
+
+// ListRecruiters returns a company's recruiter accounts
+func (r *RecruiterRepository) ListRecruiters(ctx context.Context, companyID int64) ([]models.Recruiter, error) {
+	query := `SELECT ` + recruiterColumns + `
+        FROM recruiters r
+        JOIN users u ON u.id = r.user_id
+        JOIN companies c ON c.id = r.company_id
+        WHERE r.company_id = $1
+        ORDER BY r.full_name`
+	rows, err := r.DB.Query(ctx, query, companyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recruiters := []models.Recruiter{}
+	for rows.Next() {
+		rc, err := scanRecruiter(rows)
+		if err != nil {
+			return nil, err
+		}
+		recruiters = append(recruiters, *rc)
+	}
+	return recruiters, rows.Err()
+}
+
+// DeleteRecruiter removes the login. Audit entries keep their details; actor_id becomes NULL.
+func (r *RecruiterRepository) DeleteRecruiter(ctx context.Context, userID int64) (bool, error) {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM users WHERE id = $1 AND role = 'recruiter'`, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
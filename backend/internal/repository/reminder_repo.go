@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReminderRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewReminderRepository(db *pgxpool.Pool) *ReminderRepository {
+	return &ReminderRepository{DB: db}
+}
+
+// GetPendingReminderTargets finds eligible students who haven't opted in or out of an open drive whose
+// deadline falls in (from, to], and who haven't received this reminder type for it yet. Ordered by drive.
+func (r *ReminderRepository) GetPendingReminderTargets(ctx context.Context, reminderType string, from, to time.Time) ([]models.ReminderTarget, error) {
+	query := `
+        SELECT pd.id, u.id, sp.full_name, u.email, COALESCE(sp.mobile_number, ''), COALESCE(u.fcm_token, ''),
+               COALESCE(np.push_enabled, true), COALESCE(np.email_enabled, true), COALESCE(np.whatsapp_enabled, true)
+        FROM placement_drives pd
+        JOIN users u ON u.role = 'student' AND u.is_active = true AND COALESCE(u.is_blocked, false) = false
+        JOIN student_personal sp ON sp.user_id = u.id
+        LEFT JOIN student_academics sa ON sa.user_id = u.id
+        LEFT JOIN notification_preferences np ON np.user_id = u.id
+        WHERE pd.status = 'open'
+        AND pd.deadline_date > $2 AND pd.deadline_date <= $3
+        AND (sp.placement_willingness IS NULL OR sp.placement_willingness = 'Interested')
+        AND ` + driveEligibilitySQL + `
+        AND NOT EXISTS (
+            SELECT 1 FROM drive_applications da
+            WHERE da.drive_id = pd.id AND da.student_id = u.id AND da.status <> 'eligible'
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM drive_reminders dr
+            WHERE dr.drive_id = pd.id AND dr.student_id = u.id AND dr.reminder_type = $1
+        )
+        ORDER BY pd.id, u.id
+    `
+	rows, err := r.DB.Query(ctx, query, reminderType, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var targets []models.ReminderTarget
+	for rows.Next() {
+		var t models.ReminderTarget
+		if err := rows.Scan(
+			&t.DriveID, &t.StudentID, &t.FullName, &t.Email, &t.MobileNumber, &t.FCMToken,
+			&t.Preferences.PushEnabled, &t.Preferences.EmailEnabled, &t.Preferences.WhatsAppEnabled,
+		); err != nil {
+			return nil, err
+		}
+		t.Preferences.UserID = t.StudentID
+		targets = append(targets, t)
+	}
+	return targets, rows.Err()
+}
+
+// ClaimReminder records a reminder before it is sent. Returns false if it was already recorded,
+// which guarantees one reminder per drive, student and type even with several app instances.
+func (r *ReminderRepository) ClaimReminder(ctx context.Context, driveID, studentID int64, reminderType string, channels []string) (bool, error) {
+	query := `
+        INSERT INTO drive_reminders (drive_id, student_id, reminder_type, channels, sent_at)
+        VALUES ($1, $2, $3, $4, NOW())
+        ON CONFLICT (drive_id, student_id, reminder_type) DO NOTHING
+    `
+	tag, err := r.DB.Exec(ctx, query, driveID, studentID, reminderType, channels)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetPreferences returns a student's channel preferences (all enabled if never set)
+func (r *ReminderRepository) GetPreferences(ctx context.Context, userID int64) (*models.NotificationPreferences, error) {
+	query := `
+        SELECT COALESCE(np.push_enabled, true), COALESCE(np.email_enabled, true), COALESCE(np.whatsapp_enabled, true),
+               COALESCE(np.updated_at, u.created_at)
+        FROM users u
+        LEFT JOIN notification_preferences np ON np.user_id = u.id
+        WHERE u.id = $1
+    `
+	p := models.NotificationPreferences{UserID: userID}
+	if err := r.DB.QueryRow(ctx, query, userID).Scan(&p.PushEnabled, &p.EmailEnabled, &p.WhatsAppEnabled, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// UpsertPreferences saves a student's channel preferences
+func (r *ReminderRepository) UpsertPreferences(ctx context.Context, p models.NotificationPreferences) (*models.NotificationPreferences, error) {
+	query := `
+        INSERT INTO notification_preferences (user_id, push_enabled, email_enabled, whatsapp_enabled, updated_at)
+        VALUES ($1, $2, $3, $4, NOW())
+        ON CONFLICT (user_id) DO UPDATE SET
+            push_enabled = EXCLUDED.push_enabled,
+            email_enabled = EXCLUDED.email_enabled,
+            whatsapp_enabled = EXCLUDED.whatsapp_enabled,
+            updated_at = NOW()
+        RETURNING updated_at
+    `
+	if err := r.DB.QueryRow(ctx, query, p.UserID, p.PushEnabled, p.EmailEnabled, p.WhatsAppEnabled).Scan(&p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
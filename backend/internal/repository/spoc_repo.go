@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrSpocNotFound       = errors.New("spoc not found")
+	ErrSpocInUse          = errors.New("spoc is the primary contact on drives or has contact history; deactivate it instead")
+	ErrContactLogNotFound = errors.New("contact log not found")
+	ErrContactLogLink     = errors.New("company or drive not found")
+)
+
+type SpocRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewSpocRepository(db *pgxpool.Pool) *SpocRepository {
+	return &SpocRepository{DB: db}
+}
+
+const spocColumns = `
+            s.id, s.name, COALESCE(s.designation, ''), s.mobile_number, COALESCE(s.email, ''),
+            COALESCE(s.is_active, true), s.created_at`
+
+func scanSpoc(row pgx.Row) (*models.Spoc, error) {
+	var s models.Spoc
+	err := row.Scan(&s.ID, &s.Name, &s.Designation, &s.MobileNumber, &s.Email, &s.IsActive, &s.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListSpocs returns SPOCs by name. search matches name, designation, email or mobile;
+// active filters on status when set. A zero limit returns every match.
+func (r *SpocRepository) ListSpocs(ctx context.Context, search string, active *bool, limit, offset int) ([]models.Spoc, int64, error) {
+	where := `
+        WHERE ($1::text = '' OR s.name ILIKE '%' || $1 || '%' OR s.designation ILIKE '%' || $1 || '%'
+               OR s.email ILIKE '%' || $1 || '%' OR s.mobile_number ILIKE '%' || $1 || '%')
+          AND ($2::boolean IS NULL OR COALESCE(s.is_active, true) = $2)`
+
+	var total int64
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM spocs s `+where, search, active).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.DB.Query(ctx, `SELECT `+spocColumns+` FROM spocs s `+where+`
+        ORDER BY s.name ASC, s.id ASC
+        LIMIT NULLIF($3::int, 0) OFFSET $4`, search, active, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	spocs := []models.Spoc{}
+	for rows.Next() {
+		s, err := scanSpoc(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		spocs = append(spocs, *s)
+	}
+	return spocs, total, rows.Err()
+}
+
+// GetSpoc returns nil if the SPOC doesn't exist
+func (r *SpocRepository) GetSpoc(ctx context.Context, id int64) (*models.Spoc, error) {
+	s, err := scanSpoc(r.DB.QueryRow(ctx, `SELECT `+spocColumns+` FROM spocs s WHERE s.id = $1`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return s, err
+}
+
+// SpocsExist reports whether every ID is a SPOC
+func (r *SpocRepository) SpocsExist(ctx context.Context, ids []int64) (bool, error) {
+	var found int
+	err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM spocs WHERE id = ANY($1::bigint[])`, ids).Scan(&found)
+	return found == len(ids), err
+}
+
+func (r *SpocRepository) CreateSpoc(ctx context.Context, input models.CreateSpocInput) (*models.Spoc, error) {
+	return scanSpoc(r.DB.QueryRow(ctx, `
+        INSERT INTO spocs (name, designation, mobile_number, email, is_active, created_at)
+        VALUES ($1, $2, $3, $4, true, NOW())
+        RETURNING id, name, COALESCE(designation, ''), mobile_number, COALESCE(email, ''), is_active, created_at
+    `, input.Name, input.Designation, input.MobileNumber, input.Email))
+}
+
+// UpdateSpoc returns nil if the SPOC doesn't exist
+func (r *SpocRepository) UpdateSpoc(ctx context.Context, id int64, input models.CreateSpocInput) (*models.Spoc, error) {
+	s, err := scanSpoc(r.DB.QueryRow(ctx, `
+        UPDATE spocs
+        SET name = $1, designation = $2, mobile_number = $3, email = $4
+        WHERE id = $5
+        RETURNING id, name, COALESCE(designation, ''), mobile_number, COALESCE(email, ''), is_active, created_at
+    `, input.Name, input.Designation, input.MobileNumber, input.Email, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return s, err
+}
+
+// SetSpocActive returns nil if the SPOC doesn't exist
+func (r *SpocRepository) SetSpocActive(ctx context.Context, id int64, active bool) (*models.Spoc, error) {
+	s, err := scanSpoc(r.DB.QueryRow(ctx, `
+        UPDATE spocs
+        SET is_active = $1
+        WHERE id = $2
+        RETURNING id, name, COALESCE(designation, ''), mobile_number, COALESCE(email, ''), is_active, created_at
+    `, active, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return s, err
+}
+
+// DeleteSpoc returns ErrSpocInUse while drives or contact logs still reference the SPOC
+func (r *SpocRepository) DeleteSpoc(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM spocs WHERE id = $1`, id)
+	if isForeignKeyViolation(err) {
+		return false, ErrSpocInUse
+	}
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+const contactLogColumns = `
+            l.id, l.spoc_id, s.name, l.contact_type, l.company_id, COALESCE(c.name, ''), l.drive_id,
+            l.notes, l.contacted_at, l.follow_up_date, l.follow_up_done, l.logged_by, COALESCE(u.email, ''), l.created_at`
+
+const contactLogJoins = `
+        FROM spoc_contact_logs l
+        JOIN spocs s ON s.id = l.spoc_id
+        LEFT JOIN companies c ON c.id = l.company_id
+        LEFT JOIN users u ON u.id = l.logged_by`
+
+func scanContactLog(row pgx.Row) (*models.SpocContactLog, error) {
+	var l models.SpocContactLog
+	err := row.Scan(
+		&l.ID, &l.SpocID, &l.SpocName, &l.ContactType, &l.CompanyID, &l.CompanyName, &l.DriveID,
+		&l.Notes, &l.ContactedAt, &l.FollowUpDate, &l.FollowUpDone, &l.LoggedBy, &l.LoggedByEmail, &l.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *SpocRepository) getContactLog(ctx context.Context, spocID, logID int64) (*models.SpocContactLog, error) {
+	l, err := scanContactLog(r.DB.QueryRow(ctx, `SELECT `+contactLogColumns+contactLogJoins+`
+        WHERE l.id = $1 AND l.spoc_id = $2`, logID, spocID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return l, err
+}
+
+// CreateContactLog records an interaction. loggedBy is the staff user writing it.
+func (r *SpocRepository) CreateContactLog(ctx context.Context, spocID, loggedBy int64, input models.SpocContactLogInput, contactedAt time.Time, followUp pgtype.Date) (*models.SpocContactLog, error) {
+	var id int64
+	err := r.DB.QueryRow(ctx, `
+        INSERT INTO spoc_contact_logs (spoc_id, contact_type, company_id, drive_id, notes, contacted_at, follow_up_date, follow_up_done, logged_by)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9::bigint, 0))
+        RETURNING id
+    `, spocID, input.ContactType, input.CompanyID, input.DriveID, input.Notes, contactedAt, followUp, input.FollowUpDone, loggedBy).Scan(&id)
+	if err != nil {
+		return nil, contactLogError(err)
+	}
+	return r.getContactLog(ctx, spocID, id)
+}
+
+// UpdateContactLog rewrites a log entry, e.g. to mark its follow-up done. Returns nil if it doesn't exist.
+func (r *SpocRepository) UpdateContactLog(ctx context.Context, spocID, logID int64, input models.SpocContactLogInput, contactedAt time.Time, followUp pgtype.Date) (*models.SpocContactLog, error) {
+	tag, err := r.DB.Exec(ctx, `
+        UPDATE spoc_contact_logs
+        SET contact_type = $1, company_id = $2, drive_id = $3, notes = $4, contacted_at = $5,
+            follow_up_date = $6, follow_up_done = $7
+        WHERE id = $8 AND spoc_id = $9
+    `, input.ContactType, input.CompanyID, input.DriveID, input.Notes, contactedAt, followUp, input.FollowUpDone, logID, spocID)
+	if err != nil {
+		return nil, contactLogError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, nil
+	}
+	return r.getContactLog(ctx, spocID, logID)
+}
+
+func (r *SpocRepository) DeleteContactLog(ctx context.Context, spocID, logID int64) (bool, error) {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM spoc_contact_logs WHERE id = $1 AND spoc_id = $2`, logID, spocID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListContactLogs returns a SPOC's history, most recent contact first
+func (r *SpocRepository) ListContactLogs(ctx context.Context, spocID int64, limit, offset int) ([]models.SpocContactLog, int64, error) {
+	var total int64
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*) FROM spoc_contact_logs WHERE spoc_id = $1`, spocID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.DB.Query(ctx, `SELECT `+contactLogColumns+contactLogJoins+`
+        WHERE l.spoc_id = $1
+        ORDER BY l.contacted_at DESC, l.id DESC
+        LIMIT $2 OFFSET $3`, spocID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	logs := []models.SpocContactLog{}
+	for rows.Next() {
+		l, err := scanContactLog(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		logs = append(logs, *l)
+	}
+	return logs, total, rows.Err()
+}
+
+// ListDueFollowUps returns open follow-ups due on or before the given date, oldest first
+func (r *SpocRepository) ListDueFollowUps(ctx context.Context, dueBy time.Time) ([]models.SpocContactLog, error) {
+	rows, err := r.DB.Query(ctx, `SELECT `+contactLogColumns+contactLogJoins+`
+        WHERE l.follow_up_date IS NOT NULL AND l.follow_up_date <= $1::date AND NOT l.follow_up_done
+        ORDER BY l.follow_up_date ASC, l.id ASC`, dueBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := []models.SpocContactLog{}
+	for rows.Next() {
+		l, err := scanContactLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, *l)
+	}
+	return logs, rows.Err()
+}
+
+func contactLogError(err error) error {
+	if isForeignKeyViolation(err) {
+		return ErrContactLogLink
+	}
+	return err
+}
+
+// isForeignKeyViolation reports whether err is a Postgres foreign_key_violation (23503)
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503"
+}
@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt" // Need this for Tx
 	"time"
 
 	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -73,7 +75,7 @@ func (r *StudentRepository) UpdateStudentProfile(ctx context.Context, userID int
             about_me = $4, 
             placement_willingness = COALESCE(NULLIF($5, ''), student_personal.placement_willingness),
             social_links = $6, language_skills = $7,
-            dob = $8::date,
+            dob = NULLIF($8::text, '')::date,
             updated_at = NOW()
         WHERE user_id = $9
     `
@@ -148,6 +150,55 @@ func (r *StudentRepository) UpdateStudentProfile(ctx context.Context, userID int
 	return tx.Commit(ctx)
 }
 
+// GetProfileInput loads the student's current editable profile in the shape UpdateStudentProfile
+// writes, so a partial change can be merged in without blanking the other fields
+func (r *StudentRepository) GetProfileInput(ctx context.Context, userID int64) (*models.UpdateProfileInput, error) {
+	query := `
+        SELECT
+            COALESCE(sp.about_me, ''), COALESCE(sp.placement_willingness, ''),
+            COALESCE(sp.mobile_number, ''), COALESCE(sp.dob::text, ''),
+            COALESCE(sp.city, ''), COALESCE(sp.state, ''),
+            COALESCE(sp.social_links, '{}'::jsonb), COALESCE(sp.language_skills, '[]'::jsonb),
+
+            COALESCE(sa.tenth_mark, 0), COALESCE(sa.tenth_board, ''), COALESCE(sa.tenth_year_pass, 0), COALESCE(sa.tenth_institution, ''),
+            COALESCE(sa.twelfth_mark, 0), COALESCE(sa.twelfth_board, ''), COALESCE(sa.twelfth_year_pass, 0), COALESCE(sa.twelfth_institution, ''),
+            COALESCE(sa.diploma_mark, 0), COALESCE(sa.diploma_year_pass, 0), COALESCE(sa.diploma_institution, ''),
+            COALESCE(sa.ug_cgpa, 0), COALESCE(sa.pg_cgpa, 0),
+            COALESCE(sa.current_backlogs, 0), COALESCE(sa.history_of_backlogs, 0),
+            COALESCE(sa.gap_years, 0), COALESCE(sa.gap_reason, ''),
+
+            COALESCE(sd.resume_url, '')
+        FROM student_personal sp
+        LEFT JOIN student_academics sa ON sa.user_id = sp.user_id
+        LEFT JOIN student_documents sd ON sd.user_id = sp.user_id
+        WHERE sp.user_id = $1
+    `
+	var p models.UpdateProfileInput
+	err := r.DB.QueryRow(ctx, query, userID).Scan(
+		&p.AboutMe, &p.PlacementWillingness,
+		&p.MobileNumber, &p.Dob,
+		&p.City, &p.State,
+		&p.SocialLinks, &p.LanguageSkills,
+		&p.TenthMark, &p.TenthBoard, &p.TenthYearPass, &p.TenthInstitution,
+		&p.TwelfthMark, &p.TwelfthBoard, &p.TwelfthYearPass, &p.TwelfthInstitution,
+		&p.DiplomaMark, &p.DiplomaYearPass, &p.DiplomaInstitution,
+		&p.UgCgpa, &p.PgCgpa,
+		&p.CurrentBacklogs, &p.HistoryBacklogs,
+		&p.GapYears, &p.GapReason,
+		&p.ResumeURL,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.SocialLinks == nil {
+		p.SocialLinks = map[string]string{}
+	}
+	return &p, nil
+}
+
 // GetStudentFullProfile fetches all details by joining tables
 func (r *StudentRepository) GetStudentFullProfile(ctx context.Context, userID int64) (*models.StudentFullProfile, error) {
 	query := `
@@ -203,5 +254,10 @@ func (r *StudentRepository) GetStudentFullProfile(ctx context.Context, userID in
 	s.SocialLinks = socialLinks
 	s.LanguageSkills = langSkills
 
+	s.StudentProfileSections, err = NewProfileSectionRepository(r.DB).GetSections(ctx, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load profile sections: %w", err)
+	}
+
 	return &s, nil
 }
@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrInvalidStudentSort = errors.New("unknown sort column")
+	ErrInvalidCursor      = errors.New("invalid or stale cursor")
+	ErrSavedSearchExists  = errors.New("a saved search with this name already exists")
+)
+
+// studentSortColumns maps the sortable columns to their SQL. Every expression is
+// non-null so (value, id) keyset comparisons are total; cast is the type the
+// cursor's text value is compared as.
+var studentSortColumns = map[string]struct{ expr, cast string }{
+	"register_number":     {"COALESCE(sp.register_number, '')", "text"},
+	"full_name":           {"sp.full_name", "text"},
+	"email":               {"u.email", "text"},
+	"department":          {"COALESCE(sp.department, '')", "text"},
+	"batch_year":          {"COALESCE(sp.batch_year, 0)", "int"},
+	"ug_cgpa":             {"COALESCE(sa.ug_cgpa, 0)", "numeric"},
+	"pg_cgpa":             {"COALESCE(sa.pg_cgpa, 0)", "numeric"},
+	"tenth_mark":          {"COALESCE(sa.tenth_mark, 0)", "numeric"},
+	"twelfth_mark":        {"COALESCE(sa.twelfth_mark, 0)", "numeric"},
+	"diploma_mark":        {"COALESCE(sa.diploma_mark, 0)", "numeric"},
+	"current_backlogs":    {"COALESCE(sa.current_backlogs, 0)", "int"},
+	"history_of_backlogs": {"COALESCE(sa.history_of_backlogs, 0)", "int"},
+	"last_login":          {"COALESCE(u.last_login, 'epoch'::timestamptz)", "timestamptz"},
+	"created_at":          {"COALESCE(u.created_at, 'epoch'::timestamptz)", "timestamptz"},
+}
+
+// IsStudentSortColumn reports whether sort can be used in a StudentSearchQuery ("" is the default)
+func IsStudentSortColumn(sort string) bool {
+	_, ok := studentSortColumns[sort]
+	return ok || sort == ""
+}
+
+// studentCursor is the position after the last row of a page. Sort and order are
+// kept so a cursor can't be replayed against a different ordering.
+type studentCursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+func encodeStudentCursor(c studentCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeStudentCursor(s string) (studentCursor, error) {
+	var c studentCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+type StudentSearchRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewStudentSearchRepository(db *pgxpool.Pool) *StudentSearchRepository {
+	return &StudentSearchRepository{DB: db}
+}
+
+// studentSearchWhere turns the filters into a WHERE clause with positional args
+func studentSearchWhere(f models.StudentSearchFilters) (string, []interface{}) {
+	where := []string{"u.role = 'student'"}
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if !f.IncludeBlocked {
+		where = append(where, "NOT COALESCE(u.is_blocked, false)")
+	}
+	if s := strings.TrimSpace(f.Search); s != "" {
+		p := arg("%" + s + "%")
+		where = append(where, fmt.Sprintf("(sp.full_name ILIKE %s OR sp.register_number ILIKE %s OR u.email ILIKE %s)", p, p, p))
+	}
+	if len(f.Departments) > 0 {
+		where = append(where, "sp.department = ANY("+arg(f.Departments)+"::text[])")
+	}
+	if len(f.BatchYears) > 0 {
+		where = append(where, "sp.batch_year = ANY("+arg(f.BatchYears)+"::int[])")
+	}
+	if len(f.Genders) > 0 {
+		where = append(where, "sp.gender = ANY("+arg(f.Genders)+"::text[])")
+	}
+	if len(f.StudentTypes) > 0 {
+		where = append(where, "sp.student_type = ANY("+arg(f.StudentTypes)+"::text[])")
+	}
+	if len(f.PlacementWillingness) > 0 {
+		where = append(where, "sp.placement_willingness = ANY("+arg(f.PlacementWillingness)+"::text[])")
+	}
+
+	ranges := []struct {
+		column string
+		r      models.NumberRange
+	}{
+		{"sa.ug_cgpa", f.UgCgpa},
+		{"sa.pg_cgpa", f.PgCgpa},
+		{"sa.tenth_mark", f.TenthMark},
+		{"sa.twelfth_mark", f.TwelfthMark},
+		{"sa.diploma_mark", f.DiplomaMark},
+	}
+	for _, rg := range ranges {
+		if rg.r.Min != nil {
+			where = append(where, rg.column+" >= "+arg(*rg.r.Min)+"::numeric")
+		}
+		if rg.r.Max != nil {
+			where = append(where, rg.column+" <= "+arg(*rg.r.Max)+"::numeric")
+		}
+	}
+	if f.MaxCurrentBacklogs != nil {
+		where = append(where, "COALESCE(sa.current_backlogs, 0) <= "+arg(*f.MaxCurrentBacklogs)+"::int")
+	}
+	if f.MaxHistoryBacklogs != nil {
+		where = append(where, "COALESCE(sa.history_of_backlogs, 0) <= "+arg(*f.MaxHistoryBacklogs)+"::int")
+	}
+
+	switch f.PlacementStatus {
+	case "placed":
+		where = append(where, "EXISTS (SELECT 1 FROM drive_applications da WHERE da.student_id = u.id AND da.status = 'placed')")
+	case "not_placed":
+		where = append(where, "NOT EXISTS (SELECT 1 FROM drive_applications da WHERE da.student_id = u.id AND da.status = 'placed')")
+	}
+
+	sectionClause, sectionArgs := sectionFilterClause(models.StudentSectionFilter{
+		Skills:            f.Skills,
+		MinProficiency:    f.MinProficiency,
+		HasInternship:     f.HasInternship,
+		InternshipCompany: f.InternshipCompany,
+		Certification:     f.Certification,
+	}, len(args)+1)
+	args = append(args, sectionArgs...)
+
+	return "WHERE " + strings.Join(where, " AND ") + sectionClause, args
+}
+
+// SearchStudents runs a filtered, sorted search and returns one page plus the cursor for
+// the next (empty on the last page). total counts every match, ignoring the cursor.
+func (r *StudentSearchRepository) SearchStudents(ctx context.Context, q models.StudentSearchQuery) ([]models.StudentSearchResult, string, int64, error) {
+	sortKey := q.Sort
+	if sortKey == "" {
+		sortKey = "register_number"
+	}
+	col, ok := studentSortColumns[sortKey]
+	if !ok {
+		return nil, "", 0, ErrInvalidStudentSort
+	}
+	order, cmp := "ASC", ">"
+	if q.Order == "desc" {
+		order, cmp = "DESC", "<"
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+
+	from := `
+        FROM users u
+        JOIN student_personal sp ON u.id = sp.user_id
+        LEFT JOIN student_academics sa ON u.id = sa.user_id
+        LEFT JOIN student_documents sd ON u.id = sd.user_id
+        `
+	var cur *studentCursor
+	if q.Cursor != "" {
+		c, err := decodeStudentCursor(q.Cursor)
+		if err != nil || c.Sort != sortKey || c.Order != strings.ToLower(order) {
+			return nil, "", 0, ErrInvalidCursor
+		}
+		cur = &c
+	}
+
+	where, args := studentSearchWhere(q.Filters)
+
+	var total int64
+	if err := r.DB.QueryRow(ctx, `SELECT COUNT(*)`+from+where, args...).Scan(&total); err != nil {
+		return nil, "", 0, err
+	}
+
+	if cur != nil {
+		where += fmt.Sprintf(" AND (%s, u.id) %s ($%d::%s, $%d)", col.expr, cmp, len(args)+1, col.cast, len(args)+2)
+		args = append(args, cur.Value, cur.ID)
+	}
+
+	query := fmt.Sprintf(`
+        SELECT u.id, u.email, sp.full_name, COALESCE(sp.register_number, ''), COALESCE(sp.department, ''),
+            COALESCE(sp.batch_year, 0), COALESCE(sp.gender, ''), COALESCE(sp.student_type, ''),
+            COALESCE(sp.placement_willingness, ''), COALESCE(sp.mobile_number, ''),
+            COALESCE(sa.ug_cgpa, 0), COALESCE(sa.pg_cgpa, 0), COALESCE(sa.tenth_mark, 0),
+            COALESCE(sa.twelfth_mark, 0), COALESCE(sa.diploma_mark, 0),
+            COALESCE(sa.current_backlogs, 0), COALESCE(sa.history_of_backlogs, 0),
+            EXISTS (SELECT 1 FROM drive_applications da WHERE da.student_id = u.id AND da.status = 'placed'),
+            COALESCE(u.is_blocked, false), COALESCE(sd.profile_photo_url, ''),
+            (%s)::text
+        %s%s
+        ORDER BY %s %s, u.id %s
+        LIMIT $%d`, col.expr, from, where, col.expr, order, order, len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	defer rows.Close()
+
+	students := []models.StudentSearchResult{}
+	var sortValues []string
+	for rows.Next() {
+		var s models.StudentSearchResult
+		var sortValue string
+		err := rows.Scan(
+			&s.ID, &s.Email, &s.FullName, &s.RegisterNumber, &s.Department,
+			&s.BatchYear, &s.Gender, &s.StudentType,
+			&s.PlacementWillingness, &s.MobileNumber,
+			&s.UgCgpa, &s.PgCgpa, &s.TenthMark,
+			&s.TwelfthMark, &s.DiplomaMark,
+			&s.CurrentBacklogs, &s.HistoryBacklogs,
+			&s.IsPlaced,
+			&s.IsBlocked, &s.ProfilePhotoURL,
+			&sortValue,
+		)
+		if err != nil {
+			return nil, "", 0, err
+		}
+		students = append(students, s)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", 0, err
+	}
+
+	next := ""
+	if len(students) > limit {
+		students = students[:limit]
+		last := students[limit-1]
+		next = encodeStudentCursor(studentCursor{Sort: sortKey, Order: strings.ToLower(order), Value: sortValues[limit-1], ID: last.ID})
+	}
+	return students, next, total, nil
+}
+
+// ==========================================
+// SAVED SEARCHES
+// ==========================================
+
+const savedSearchColumns = `
+            s.id, s.name, s.query, s.is_shared, s.created_by, COALESCE(u.email, ''), s.created_at, s.updated_at`
+
+func scanSavedSearch(row pgx.Row) (*models.SavedStudentSearch, error) {
+	var s models.SavedStudentSearch
+	err := row.Scan(&s.ID, &s.Name, &s.Query, &s.IsShared, &s.CreatedBy, &s.CreatedEmail, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// storedQuery drops the paging fields, which belong to a single run
+func storedQuery(q models.StudentSearchQuery) models.StudentSearchQuery {
+	q.Cursor = ""
+	q.Limit = 0
+	return q
+}
+
+// ListSavedSearches returns the admin's own searches and everyone's shared ones
+func (r *StudentSearchRepository) ListSavedSearches(ctx context.Context, adminID int64) ([]models.SavedStudentSearch, error) {
+	rows, err := r.DB.Query(ctx, `
+        SELECT `+savedSearchColumns+`
+        FROM saved_student_searches s
+        LEFT JOIN users u ON u.id = s.created_by
+        WHERE s.created_by = $1 OR s.is_shared
+        ORDER BY lower(s.name), s.id
+    `, adminID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	searches := []models.SavedStudentSearch{}
+	for rows.Next() {
+		s, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, err
+		}
+		searches = append(searches, *s)
+	}
+	return searches, rows.Err()
+}
+
+// GetSavedSearch returns nil unless the search is the admin's own or shared
+func (r *StudentSearchRepository) GetSavedSearch(ctx context.Context, id, adminID int64) (*models.SavedStudentSearch, error) {
+	s, err := scanSavedSearch(r.DB.QueryRow(ctx, `
+        SELECT `+savedSearchColumns+`
+        FROM saved_student_searches s
+        LEFT JOIN users u ON u.id = s.created_by
+        WHERE s.id = $1 AND (s.created_by = $2 OR s.is_shared)
+    `, id, adminID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return s, err
+}
+
+func (r *StudentSearchRepository) CreateSavedSearch(ctx context.Context, adminID int64, input models.SavedStudentSearchInput) (*models.SavedStudentSearch, error) {
+	var id int64
+	err := r.DB.QueryRow(ctx, `
+        INSERT INTO saved_student_searches (name, query, is_shared, created_by)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id
+    `, input.Name, storedQuery(input.Query), input.IsShared, adminID).Scan(&id)
+	if isUniqueViolation(err) {
+		return nil, ErrSavedSearchExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.GetSavedSearch(ctx, id, adminID)
+}
+
+// UpdateSavedSearch renames or re-scopes a search. Only its creator can change it;
+// returns nil otherwise.
+func (r *StudentSearchRepository) UpdateSavedSearch(ctx context.Context, id, adminID int64, input models.SavedStudentSearchInput) (*models.SavedStudentSearch, error) {
+	tag, err := r.DB.Exec(ctx, `
+        UPDATE saved_student_searches
+        SET name = $1, query = $2, is_shared = $3, updated_at = NOW()
+        WHERE id = $4 AND created_by = $5
+    `, input.Name, storedQuery(input.Query), input.IsShared, id, adminID)
+	if isUniqueViolation(err) {
+		return nil, ErrSavedSearchExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, nil
+	}
+	return r.GetSavedSearch(ctx, id, adminID)
+}
+
+// DeleteSavedSearch removes one of the admin's own searches
+func (r *StudentSearchRepository) DeleteSavedSearch(ctx context.Context, id, adminID int64) (bool, error) {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM saved_student_searches WHERE id = $1 AND created_by = $2`, id, adminID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
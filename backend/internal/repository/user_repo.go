@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
@@ -10,6 +11,9 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// ErrMobileNumberAmbiguous is returned when several students registered the same number
+var ErrMobileNumberAmbiguous = errors.New("more than one student is registered with this number")
+
 // BulkCreateStudents inserts multiple students in a transaction
 func (r *UserRepository) BulkCreateStudents(ctx context.Context, students [][]string) (int, error) {
 	tx, err := r.DB.Begin(ctx)
@@ -120,6 +124,79 @@ func (r *UserRepository) GetRegisterNumber(ctx context.Context, userID int64) (s
 	return regNo, nil
 }
 
+// GetStudentMobileNumber fetches the mobile number a student registered with.
+// Returns an error if the number is missing or still the bulk-upload placeholder 'NA'.
+func (r *UserRepository) GetStudentMobileNumber(ctx context.Context, userID int64) (string, error) {
+	var mobile string
+	query := `SELECT COALESCE(mobile_number, '') FROM student_personal WHERE user_id = $1`
+
+	if err := r.DB.QueryRow(ctx, query, userID).Scan(&mobile); err != nil {
+		return "", fmt.Errorf("student profile not found")
+	}
+	if mobile == "" || mobile == "NA" {
+		return "", fmt.Errorf("mobile number not set")
+	}
+	return mobile, nil
+}
+
+// GetStudentContact fetches a student's name and login email
+func (r *UserRepository) GetStudentContact(ctx context.Context, userID int64) (*models.StudentContact, error) {
+	query := `
+        SELECT u.id, sp.full_name, u.email
+        FROM users u
+        JOIN student_personal sp ON u.id = sp.user_id
+        WHERE u.id = $1
+    `
+	var sc models.StudentContact
+	if err := r.DB.QueryRow(ctx, query, userID).Scan(&sc.UserID, &sc.FullName, &sc.Email); err != nil {
+		return nil, fmt.Errorf("student not found")
+	}
+	return &sc, nil
+}
+
+// GetStudentByMobileNumber finds the active student who registered the given number.
+// Numbers are compared on their last 10 digits so "+91 98765-43210" matches "9876543210".
+// Returns ErrMobileNumberAmbiguous rather than guessing when the number is shared.
+func (r *UserRepository) GetStudentByMobileNumber(ctx context.Context, number string) (int64, string, error) {
+	query := `
+        SELECT u.id, sp.full_name
+        FROM users u
+        JOIN student_personal sp ON u.id = sp.user_id
+        WHERE u.role = 'student'
+        AND u.is_active = true
+        AND COALESCE(u.is_blocked, false) = false
+        AND LENGTH(regexp_replace(COALESCE(sp.mobile_number, ''), '\D', '', 'g')) >= 10
+        AND RIGHT(regexp_replace(sp.mobile_number, '\D', '', 'g'), 10) = RIGHT(regexp_replace($1, '\D', '', 'g'), 10)
+        LIMIT 2
+    `
+	rows, err := r.DB.Query(ctx, query, number)
+	if err != nil {
+		return 0, "", err
+	}
+	defer rows.Close()
+
+	var userID int64
+	var fullName string
+	matches := 0
+	for rows.Next() {
+		if err := rows.Scan(&userID, &fullName); err != nil {
+			return 0, "", err
+		}
+		matches++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, "", err
+	}
+	switch matches {
+	case 0:
+		return 0, "", fmt.Errorf("no student registered with this number")
+	case 1:
+		return userID, fullName, nil
+	default:
+		return 0, "", ErrMobileNumberAmbiguous
+	}
+}
+
 // IsStudentProfileComplete checks if a student has completed their profile setup
 // Returns true if mobile_number is set and not 'NA'
 func (r *UserRepository) IsStudentProfileComplete(ctx context.Context, userID int64) bool {
@@ -158,6 +235,30 @@ func (r *UserRepository) GetRegisterNumbersByIDs(ctx context.Context, ids []int6
 	return regNos, nil
 }
 
+// GetFCMTokensByIDs returns the FCM token of each given user that has one, keyed by user ID
+func (r *UserRepository) GetFCMTokensByIDs(ctx context.Context, ids []int64) (map[int64]string, error) {
+	tokens := make(map[int64]string)
+	if len(ids) == 0 {
+		return tokens, nil
+	}
+	query := `SELECT id, fcm_token FROM users WHERE id = ANY($1) AND fcm_token IS NOT NULL AND fcm_token != ''`
+	rows, err := r.DB.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var token string
+		if err := rows.Scan(&id, &token); err != nil {
+			return nil, err
+		}
+		tokens[id] = token
+	}
+	return tokens, nil
+}
+
 // GetStudentByRegisterNumber fetches full profile by RegNo
 func (r *UserRepository) GetStudentByRegisterNumber(ctx context.Context, regNo string) (*models.StudentFullProfile, error) {
 	query := `
@@ -191,6 +292,10 @@ func (r *UserRepository) GetStudentByRegisterNumber(ctx context.Context, regNo s
 	if err != nil {
 		return nil, err
 	}
+	s.StudentProfileSections, err = NewProfileSectionRepository(r.DB).GetSections(ctx, s.ID)
+	if err != nil {
+		return nil, err
+	}
 	return &s, nil
 }
 
@@ -284,8 +389,31 @@ func (r *UserRepository) UpdateFCMToken(ctx context.Context, userID int64, token
 	return err
 }
 
+// GetAdminNotificationTargets returns the emails and FCM tokens of active admins (for operational alerts)
+func (r *UserRepository) GetAdminNotificationTargets(ctx context.Context) ([]string, []string, error) {
+	query := `SELECT email, COALESCE(fcm_token, '') FROM users WHERE role = 'admin' AND is_active = true`
+	rows, err := r.DB.Query(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var emails, tokens []string
+	for rows.Next() {
+		var email, token string
+		if err := rows.Scan(&email, &token); err != nil {
+			return nil, nil, err
+		}
+		emails = append(emails, email)
+		if token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return emails, tokens, nil
+}
+
 // GetStudents fetches students with dynamic filters and pagination
-func (r *UserRepository) GetStudents(ctx context.Context, department string, batchYear int, search string, limit, offset int) ([]map[string]interface{}, int64, error) {
+func (r *UserRepository) GetStudents(ctx context.Context, department string, batchYear int, search string, sections models.StudentSectionFilter, limit, offset int) ([]map[string]interface{}, int64, error) {
 	// Base Query conditions
 	whereClause := "WHERE u.role = 'student'"
 	var args []interface{}
@@ -304,10 +432,16 @@ func (r *UserRepository) GetStudents(ctx context.Context, department string, bat
 	}
 	if search != "" {
 		whereClause += fmt.Sprintf(" AND (sp.full_name ILIKE $%d OR sp.register_number ILIKE $%d)", argCounter, argCounter)
-		args = append(args, "%"+search+"%", "%"+search+"%")
+		args = append(args, "%"+search+"%")
 		argCounter++
 	}
 
+	// Profile section filters
+	sectionClause, sectionArgs := sectionFilterClause(sections, argCounter)
+	whereClause += sectionClause
+	args = append(args, sectionArgs...)
+	argCounter += len(sectionArgs)
+
 	// 1. Get Total Count (for pagination)
 	countQuery := fmt.Sprintf(`
         SELECT COUNT(*)
@@ -378,3 +512,37 @@ func (r *UserRepository) GetStudents(ctx context.Context, department string, bat
 	}
 	return students, totalCount, nil
 }
+
+// sectionFilterClause builds the skills / internship / certification conditions for the
+// student lists. Placeholders start at $argStart; u.id is the student.
+func sectionFilterClause(sections models.StudentSectionFilter, argStart int) (string, []interface{}) {
+	clause := ""
+	var args []interface{}
+	next := func() int { return argStart + len(args) }
+
+	if len(sections.Skills) > 0 {
+		minRank := 1
+		for i, p := range models.SkillProficiencies {
+			if p == sections.MinProficiency {
+				minRank = i + 1
+			}
+		}
+		clause += fmt.Sprintf(` AND NOT EXISTS (
+            SELECT 1 FROM unnest($%d::text[]) AS want(name)
+            WHERE NOT EXISTS (
+                SELECT 1 FROM student_skills ss
+                WHERE ss.user_id = u.id AND lower(ss.name) = lower(want.name)
+                  AND array_position($%d::text[], ss.proficiency) >= $%d
+            ))`, next(), next()+1, next()+2)
+		args = append(args, sections.Skills, models.SkillProficiencies, minRank)
+	}
+	if sections.HasInternship || sections.InternshipCompany != "" {
+		clause += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM student_internships si WHERE si.user_id = u.id AND si.company_name ILIKE $%d)", next())
+		args = append(args, "%"+sections.InternshipCompany+"%")
+	}
+	if sections.Certification != "" {
+		clause += fmt.Sprintf(" AND EXISTS (SELECT 1 FROM student_certifications sc WHERE sc.user_id = u.id AND (sc.name ILIKE $%d OR sc.issuer ILIKE $%d))", next(), next())
+		args = append(args, "%"+sections.Certification+"%")
+	}
+	return clause, args
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookDeliveryRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewWebhookDeliveryRepository(db *pgxpool.Pool) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{DB: db}
+}
+
+// RecordDelivery stores a delivery ID for a webhook source.
+// Returns false if the same delivery was already seen (i.e. a replay or a retry of a processed delivery).
+func (r *WebhookDeliveryRepository) RecordDelivery(ctx context.Context, source, deliveryID string) (bool, error) {
+	query := `
+        INSERT INTO webhook_deliveries (source, delivery_id, received_at)
+        VALUES ($1, $2, NOW())
+        ON CONFLICT (source, delivery_id) DO NOTHING
+    `
+	tag, err := r.DB.Exec(ctx, query, source, deliveryID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ReleaseDelivery forgets a delivery ID so the sender's retry is processed
+// (used when our handler failed after the ID was recorded).
+func (r *WebhookDeliveryRepository) ReleaseDelivery(ctx context.Context, source, deliveryID string) error {
+	_, err := r.DB.Exec(ctx, `DELETE FROM webhook_deliveries WHERE source = $1 AND delivery_id = $2`, source, deliveryID)
+	return err
+}
+
+// DeleteOldDeliveries removes the given sources' delivery IDs older than the retention window.
+// Only pass sources that sign a timestamp: past the tolerance a replay is rejected anyway.
+func (r *WebhookDeliveryRepository) DeleteOldDeliveries(ctx context.Context, sources []string, retention time.Duration) (int64, error) {
+	tag, err := r.DB.Exec(ctx, `
+        DELETE FROM webhook_deliveries WHERE source = ANY($1) AND received_at < $2
+    `, sources, time.Now().Add(-retention))
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookEventRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewWebhookEventRepository(db *pgxpool.Pool) *WebhookEventRepository {
+	return &WebhookEventRepository{DB: db}
+}
+
+// CreateEvent stores an incoming webhook in 'received' state and returns its ID
+func (r *WebhookEventRepository) CreateEvent(ctx context.Context, e models.WebhookEvent) (int64, error) {
+	query := `
+        INSERT INTO webhook_events (source, event_type, headers, body, status, attempts, received_at)
+        VALUES ($1, $2, $3, $4, 'received', 0, NOW())
+        RETURNING id
+    `
+	var id int64
+	err := r.DB.QueryRow(ctx, query, e.Source, e.EventType, e.Headers, string(e.Body)).Scan(&id)
+	return id, err
+}
+
+// MarkOutcome records the result of a (re)processing attempt
+func (r *WebhookEventRepository) MarkOutcome(ctx context.Context, id int64, status string, errMsg string) error {
+	query := `
+        UPDATE webhook_events
+        SET status = $2, error = NULLIF($3, ''), attempts = attempts + 1, processed_at = NOW()
+        WHERE id = $1
+    `
+	_, err := r.DB.Exec(ctx, query, id, status, errMsg)
+	return err
+}
+
+// GetEvent fetches one event including headers and body. Returns nil if not found.
+func (r *WebhookEventRepository) GetEvent(ctx context.Context, id int64) (*models.WebhookEvent, error) {
+	query := `
+        SELECT id, source, event_type, headers, body, status, COALESCE(error, ''), attempts, received_at, processed_at
+        FROM webhook_events
+        WHERE id = $1
+    `
+	var e models.WebhookEvent
+	var body string
+	err := r.DB.QueryRow(ctx, query, id).Scan(
+		&e.ID, &e.Source, &e.EventType, &e.Headers, &body, &e.Status, &e.Error, &e.Attempts, &e.ReceivedAt, &e.ProcessedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	e.Body = []byte(body)
+	return &e, nil
+}
+
+// ListEvents returns events newest first with optional source/type/status filters.
+// Headers and body are omitted; fetch a single event to inspect them.
+func (r *WebhookEventRepository) ListEvents(ctx context.Context, source, eventType, status string, limit, offset int) ([]models.WebhookEvent, int64, error) {
+	whereClause := "WHERE 1=1"
+	var args []interface{}
+	argCounter := 1
+
+	if source != "" {
+		whereClause += fmt.Sprintf(" AND source = $%d", argCounter)
+		args = append(args, source)
+		argCounter++
+	}
+	if eventType != "" {
+		whereClause += fmt.Sprintf(" AND event_type = $%d", argCounter)
+		args = append(args, eventType)
+		argCounter++
+	}
+	if status != "" {
+		whereClause += fmt.Sprintf(" AND status = $%d", argCounter)
+		args = append(args, status)
+		argCounter++
+	}
+
+	var total int64
+	if err := r.DB.QueryRow(ctx, "SELECT COUNT(*) FROM webhook_events "+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+        SELECT id, source, event_type, status, COALESCE(error, ''), attempts, received_at, processed_at
+        FROM webhook_events
+        %s
+        ORDER BY received_at DESC, id DESC
+        LIMIT $%d OFFSET $%d
+    `, whereClause, argCounter, argCounter+1)
+	args = append(args, limit, offset)
+
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	events := []models.WebhookEvent{}
+	for rows.Next() {
+		var e models.WebhookEvent
+		if err := rows.Scan(&e.ID, &e.Source, &e.EventType, &e.Status, &e.Error, &e.Attempts, &e.ReceivedAt, &e.ProcessedAt); err != nil {
+			return nil, 0, err
+		}
+		events = append(events, e)
+	}
+	return events, total, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WhatsAppMessageRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewWhatsAppMessageRepository(db *pgxpool.Pool) *WhatsAppMessageRepository {
+	return &WhatsAppMessageRepository{DB: db}
+}
+
+// RecordMessages persists one delivery record per recipient in a single transaction.
+// The student is resolved from the recipient number (last 10 digits) so admins can see who missed an alert.
+func (r *WhatsAppMessageRepository) RecordMessages(ctx context.Context, messages []models.WhatsAppMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	tx, err := r.DB.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+        INSERT INTO whatsapp_messages (message_id, recipient, student_id, drive_id, event, template_name, status, error, created_at, updated_at)
+        VALUES (
+            NULLIF($1, ''), $2::text,
+            (SELECT user_id FROM student_personal
+             WHERE RIGHT(regexp_replace(mobile_number, '\D', '', 'g'), 10) = RIGHT($2::text, 10)
+             LIMIT 1),
+            $3, $4, $5, $6, NULLIF($7, ''), NOW(), NOW()
+        )
+    `
+	for _, m := range messages {
+		if _, err := tx.Exec(ctx, query, m.MessageID, m.Recipient, m.DriveID, m.Event, m.TemplateName, m.Status, m.Error); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ApplyStatusUpdate moves a message to the status reported by a webhook.
+// Meta does not guarantee ordering, so a late "delivered" never downgrades a "read";
+// "failed" always wins. Returns false if the message ID is unknown or the update was stale.
+func (r *WhatsAppMessageRepository) ApplyStatusUpdate(ctx context.Context, u models.WhatsAppStatusUpdate) (bool, error) {
+	query := `
+        UPDATE whatsapp_messages
+        SET status = $2, error = COALESCE(NULLIF($3, ''), error), updated_at = $4
+        WHERE message_id = $1
+        AND (
+            $2 = 'failed'
+            OR (CASE status WHEN 'accepted' THEN 0 WHEN 'sent' THEN 1 WHEN 'delivered' THEN 2 WHEN 'read' THEN 3 ELSE 4 END)
+             < (CASE $2 WHEN 'sent' THEN 1 WHEN 'delivered' THEN 2 WHEN 'read' THEN 3 ELSE 0 END)
+        )
+    `
+	tag, err := r.DB.Exec(ctx, query, u.MessageID, u.Status, u.Error, u.Timestamp)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetDriveDeliveries lists delivery records for a drive along with a count per status
+func (r *WhatsAppMessageRepository) GetDriveDeliveries(ctx context.Context, driveID int64) ([]models.WhatsAppMessage, map[string]int, error) {
+	query := `
+        SELECT id, COALESCE(message_id, ''), recipient, student_id, drive_id, event, template_name,
+               status, COALESCE(error, ''), created_at, updated_at
+        FROM whatsapp_messages
+        WHERE drive_id = $1
+        ORDER BY created_at DESC, id DESC
+    `
+	rows, err := r.DB.Query(ctx, query, driveID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	messages := []models.WhatsAppMessage{}
+	summary := map[string]int{"accepted": 0, "sent": 0, "delivered": 0, "read": 0, "failed": 0}
+	for rows.Next() {
+		var m models.WhatsAppMessage
+		if err := rows.Scan(
+			&m.ID, &m.MessageID, &m.Recipient, &m.StudentID, &m.DriveID, &m.Event, &m.TemplateName,
+			&m.Status, &m.Error, &m.CreatedAt, &m.UpdatedAt,
+		); err != nil {
+			return nil, nil, err
+		}
+		summary[m.Status]++
+		messages = append(messages, m)
+	}
+	return messages, summary, nil
+}
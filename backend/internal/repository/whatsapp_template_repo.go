@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WhatsAppTemplateRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewWhatsAppTemplateRepository(db *pgxpool.Pool) *WhatsAppTemplateRepository {
+	return &WhatsAppTemplateRepository{DB: db}
+}
+
+// ListTemplates returns every configured event -> template mapping
+func (r *WhatsAppTemplateRepository) ListTemplates(ctx context.Context) ([]models.WhatsAppTemplate, error) {
+	query := `
+        SELECT event, template_name, language_code, COALESCE(parameters, '[]'::jsonb), is_active, updated_at
+        FROM whatsapp_templates
+        ORDER BY event ASC
+    `
+	rows, err := r.DB.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []models.WhatsAppTemplate{}
+	for rows.Next() {
+		var t models.WhatsAppTemplate
+		if err := rows.Scan(&t.Event, &t.TemplateName, &t.LanguageCode, &t.Parameters, &t.IsActive, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// GetTemplate fetches the template for an event. Returns (nil, nil) if the event is not configured.
+func (r *WhatsAppTemplateRepository) GetTemplate(ctx context.Context, event string) (*models.WhatsAppTemplate, error) {
+	query := `
+        SELECT event, template_name, language_code, COALESCE(parameters, '[]'::jsonb), is_active, updated_at
+        FROM whatsapp_templates
+        WHERE event = $1
+    `
+	var t models.WhatsAppTemplate
+	err := r.DB.QueryRow(ctx, query, event).Scan(&t.Event, &t.TemplateName, &t.LanguageCode, &t.Parameters, &t.IsActive, &t.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// UpsertTemplate creates or replaces the template mapping for an event
+func (r *WhatsAppTemplateRepository) UpsertTemplate(ctx context.Context, t models.WhatsAppTemplate) (*models.WhatsAppTemplate, error) {
+	query := `
+        INSERT INTO whatsapp_templates (event, template_name, language_code, parameters, is_active, updated_at)
+        VALUES ($1, $2, $3, $4, $5, NOW())
+        ON CONFLICT (event) DO UPDATE SET
+            template_name = EXCLUDED.template_name,
+            language_code = EXCLUDED.language_code,
+            parameters = EXCLUDED.parameters,
+            is_active = EXCLUDED.is_active,
+            updated_at = NOW()
+        RETURNING event, template_name, language_code, parameters, is_active, updated_at
+    `
+	if t.Parameters == nil {
+		t.Parameters = []string{}
+	}
+
+	var out models.WhatsAppTemplate
+	err := r.DB.QueryRow(ctx, query, t.Event, t.TemplateName, t.LanguageCode, t.Parameters, t.IsActive).Scan(
+		&out.Event, &out.TemplateName, &out.LanguageCode, &out.Parameters, &out.IsActive, &out.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -22,10 +22,14 @@ func SetupRoutes(app *fiber.App) {
 	auth := api.Group("/v1/auth")
 	auth.Post("/login", handlers.StudentLogin)
 	auth.Post("/logout", handlers.LogoutUser)
-	// Public Webhooks
-	api.All("/webhooks/whatsapp", handlers.HandleWhatsAppWebhook)
-	api.Post("/webhooks/railway", handlers.HandleRailwayWebhook) // Railway deployment webhooks
-	api.Post("/webhooks/generic", handlers.HandleGenericWebhook) // Generic webhook for any external service
+	// Public Webhooks (HMAC signed, see middleware.VerifyWebhook)
+	webhooks := api.Group("/webhooks")
+	webhooks.All("/whatsapp", middleware.VerifyWebhook(middleware.WhatsAppWebhookSource), handlers.HandleWhatsAppWebhook)
+	webhooks.Post("/railway", middleware.VerifyWebhook(middleware.RailwayWebhookSource), handlers.HandleRailwayWebhook) // Railway deployment webhooks
+	webhooks.Post("/generic", middleware.VerifyWebhook(middleware.GenericWebhookSource), handlers.HandleGenericWebhook) // Generic webhook for any external service
+
+	// Public Calendar Feed (the token in the URL is the credential, calendar apps can't send a JWT)
+	api.Get("/v1/calendar/:token.ics", handlers.ServeCalendarFeed)
 
 	// Public Admin Auth Routes (But separated namespace)
 	// Even though they are public (for login), we group them under /v1/admin/auth
@@ -39,6 +43,10 @@ func SetupRoutes(app *fiber.App) {
 	// If Register is for creating new users, usually it's public OR admin only.
 	// Let's keep it in adminAuth for now as per request "similarly... register"
 
+	// Public Recruiter Auth (company HR accounts, created by admins)
+	recruiterAuth := api.Group("/v1/recruiter/auth")
+	recruiterAuth.Post("/login", handlers.RecruiterLogin)
+
 	// Protected Routes (Login Required)
 	// We group these under /v1 so we can apply middleware to all of them
 	v1 := api.Group("/v1", middleware.Protected)
@@ -46,12 +54,24 @@ func SetupRoutes(app *fiber.App) {
 	// ADMIN ONLY: Post Drives
 	admin := v1.Group("/admin", middleware.AdminOnly)
 	admin.Get("/drives", handlers.ListAdminDrives)                                     // List All Drives
+	admin.Get("/drives/search", handlers.SearchAdminDrives)                            // Full-text search with facets
 	admin.Post("/drives", handlers.CreateDrive)                                        // Create
 	admin.Put("/drives/:id", handlers.UpdateDrive)                                     // Update / Extend Deadline
 	admin.Delete("/drives/:id", handlers.DeleteDrive)                                  // Delete
 	admin.Post("/drives/bulk-delete", handlers.BulkDeleteDrives)                       // Bulk Delete
 	admin.Get("/drives/:id/applicants", handlers.GetDriveApplicants)                   // View Applicants
+	admin.Get("/drives/:id/whatsapp-deliveries", handlers.GetDriveWhatsAppDeliveries)  // WhatsApp Delivery Status
 	admin.Post("/drives/:id/add-student", handlers.AdminManualRegister)                // Force Add
+	admin.Get("/drives/:id/slots", handlers.ListDriveSlots)                            // Interview slots with bookings
+	admin.Post("/drives/:id/slots", handlers.CreateInterviewSlots)                     // Define slots per round
+	admin.Get("/drives/:id/slots.ics", handlers.ExportDriveSlotsICal)                  // iCalendar export
+	admin.Post("/drives/:id/slots/allocate", handlers.AllocateInterviewSlots)          // Auto-allocate shortlisted students
+	admin.Post("/drives/:id/slots/notify-panels", handlers.NotifyInterviewPanels)      // Email panels their schedule
+	admin.Post("/drives/:id/attendance/finalize", handlers.FinalizeDriveAttendance)    // Record no-shows
+	admin.Get("/no-shows", handlers.ListNoShows)                                       // No-show penalties
+	admin.Post("/no-shows/waive", handlers.WaiveNoShow)                                // Lift a penalty
+	admin.Put("/interview-slots/:id", handlers.UpdateInterviewSlot)                    // Update slot
+	admin.Delete("/interview-slots/:id", handlers.DeleteInterviewSlot)                 // Delete unbooked slot
 	admin.Post("/students/bulk-upload", handlers.BulkUploadStudents)                   // Bulk Upload
 	admin.Put("/users/:id/block", handlers.ToggleBlockUser)                            // Toggle Block
 	admin.Put("/applications/status", handlers.UpdateApplicationStatus)                // Update Student Status (Places, Not-Placed)
@@ -62,24 +82,118 @@ func SetupRoutes(app *fiber.App) {
 	admin.Get("/students", handlers.ListStudents)                                      // List Students
 	admin.Get("/students/:id", handlers.GetStudentDetails)                             // Get Full Profile
 	admin.Get("/students/:student_id/documents/:type", handlers.GetStudentDocumentURL) // [NEW] Get presigned URL for student documents
+	admin.Get("/students/:student_id/certifications/:id/proof", handlers.GetStudentCertificationProofURL)
+	admin.Get("/drives/:id/applicants/:student_id/history", handlers.GetApplicationHistory)
+	admin.Post("/drives/:id/applications/status", handlers.BulkUpdateApplicationStatus)
+	admin.Post("/students/search", handlers.SearchStudents)                    // Composable filters, sort, cursor paging
+	admin.Get("/student-searches", handlers.ListSavedStudentSearches)          // Own + shared saved searches
+	admin.Post("/student-searches", handlers.CreateSavedStudentSearch)         // Save a search
+	admin.Put("/student-searches/:id", handlers.UpdateSavedStudentSearch)      // Rename / edit (creator only)
+	admin.Delete("/student-searches/:id", handlers.DeleteSavedStudentSearch)   // Delete (creator only)
+	admin.Get("/student-searches/:id/results", handlers.RunSavedStudentSearch) // Run a saved search
+
+	// COORDINATOR: Drive Day Check-in (coordinators and admins)
+	coordinator := v1.Group("/coordinator", middleware.CoordinatorOnly)
+	coordinator.Post("/checkin", handlers.CheckInStudent)                  // Scan student QR
+	coordinator.Get("/drives/:id/attendance", handlers.GetDriveAttendance) // Present / absent per round
 
 	// STUDENT ACTIONS
 	v1.Get("/drives", handlers.ListStudentDrives) // STUDENT: View Drives (Filtered by Dept/Batch)
+	v1.Get("/drives/search", handlers.SearchStudentDrives)
 	v1.Post("/drives/:id/apply", handlers.ApplyForDrive)
-	v1.Post("/drives/:id/withdraw", handlers.WithdrawFromDrive) // [NEW]
+	v1.Post("/drives/:id/withdraw", handlers.WithdrawFromDrive)        // [NEW]
+	v1.Get("/drives/:id/slots", handlers.ListStudentDriveSlots)        // Bookable interview slots (shortlisted only)
+	v1.Get("/drives/:id/checkin-token", handlers.GetCheckInToken)      // QR payload for drive day
+	v1.Post("/interview-slots/:id/book", handlers.BookInterviewSlot)   // Pick / change slot
+	v1.Get("/student/applications", handlers.GetMyApplications)        // Placement dashboard
+	v1.Get("/student/interviews", handlers.ListMyInterviews)           // My interview slots
+	v1.Get("/student/interviews.ics", handlers.ExportMyInterviewsICal) // My interview slots as iCalendar
+	v1.Get("/student/calendar", handlers.GetCalendarFeed)              // Calendar subscription URL
+	v1.Post("/student/calendar/rotate", handlers.RotateCalendarFeed)   // Revoke + reissue the URL
 	v1.Post("/student/upload", handlers.UploadDocument)
 	v1.Put("/student/profile", handlers.UpdateProfile)
-	v1.Get("/student/profile", handlers.GetMyProfile)           // [NEW] Fetch Own Profile
-	v1.Get("/student/documents/:type", handlers.GetDocumentURL) // [NEW] Get presigned URL for student documents
-	v1.Post("/user/fcm-token", handlers.UpdateFCMToken)         // [NEW] Update FCM Token
-	v1.Get("/brands/search", handlers.SearchBrands)             // [NEW] Prioritize specific path before param param path if conflicting, though /brands/search vs /brands/:domain is fine if search is not a domain.
+	v1.Get("/student/profile", handlers.GetMyProfile)                             // [NEW] Fetch Own Profile
+	v1.Get("/student/documents/:type", handlers.GetDocumentURL)                   // [NEW] Get presigned URL for student documents
+	v1.Get("/student/resume/suggestions", handlers.GetResumeSuggestions)          // Parse uploaded resume into a profile diff
+	v1.Post("/student/resume/suggestions/apply", handlers.ApplyResumeSuggestions) // Save the confirmed fields
+	v1.Post("/user/fcm-token", handlers.UpdateFCMToken)                           // [NEW] Update FCM Token
+	v1.Get("/brands/search", handlers.SearchBrands)                               // [NEW] Prioritize specific path before param param path if conflicting, though /brands/search vs /brands/:domain is fine if search is not a domain.
 	v1.Get("/brands/:domain", handlers.GetBrandDetails)
-	v1.Get("/spocs", handlers.ListSpocs) // [NEW] List all SPOCs
+	v1.Get("/spocs", middleware.AdminOnly, handlers.ListSpocs) // [NEW] List all SPOCs (drive forms)
+
+	// Structured profile sections (own profile only)
+	v1.Get("/student/skills", handlers.ListStudentSkills)
+	v1.Post("/student/skills", handlers.CreateStudentSkill)
+	v1.Put("/student/skills/:id", handlers.UpdateStudentSkill)
+	v1.Delete("/student/skills/:id", handlers.DeleteStudentSkill)
+	v1.Get("/student/projects", handlers.ListStudentProjects)
+	v1.Post("/student/projects", handlers.CreateStudentProject)
+	v1.Put("/student/projects/:id", handlers.UpdateStudentProject)
+	v1.Delete("/student/projects/:id", handlers.DeleteStudentProject)
+	v1.Get("/student/internships", handlers.ListStudentInternships)
+	v1.Post("/student/internships", handlers.CreateStudentInternship)
+	v1.Put("/student/internships/:id", handlers.UpdateStudentInternship)
+	v1.Delete("/student/internships/:id", handlers.DeleteStudentInternship)
+	v1.Get("/student/certifications", handlers.ListStudentCertifications)
+	v1.Post("/student/certifications", handlers.CreateStudentCertification)
+	v1.Put("/student/certifications/:id", handlers.UpdateStudentCertification)
+	v1.Delete("/student/certifications/:id", handlers.DeleteStudentCertification)
+	v1.Post("/student/certifications/:id/proof", handlers.UploadCertificationProof) // Upload certificate file
+	v1.Get("/student/certifications/:id/proof", handlers.GetCertificationProofURL)  // Presigned URL
+
+	v1.Get("/student/notification-preferences", handlers.GetNotificationPreferences)
+	v1.Put("/student/notification-preferences", handlers.UpdateNotificationPreferences)
 	// Admin Only SPOC Management
-	admin.Post("/spocs", handlers.CreateSpoc)                 // [NEW] Create SPOC
-	admin.Put("/spocs/:id", handlers.UpdateSpoc)              // [NEW] Update SPOC
-	admin.Delete("/spocs/:id", handlers.DeleteSpoc)           // [NEW] Delete SPOC
-	admin.Put("/spocs/:id/status", handlers.ToggleSpocStatus) // [NEW] Toggle SPOC Status
+	admin.Get("/spocs", handlers.ListAdminSpocs)                   // Paginated, ?search= & ?status=active|inactive
+	admin.Get("/spocs/follow-ups", handlers.ListSpocFollowUps)     // Open follow-ups due by ?due_by=
+	admin.Post("/spocs", handlers.CreateSpoc)                      // [NEW] Create SPOC
+	admin.Put("/spocs/:id", handlers.UpdateSpoc)                   // [NEW] Update SPOC
+	admin.Delete("/spocs/:id", handlers.DeleteSpoc)                // [NEW] Delete SPOC
+	admin.Put("/spocs/:id/status", handlers.ToggleSpocStatus)      // [NEW] Toggle SPOC Status
+	admin.Get("/spocs/:id/drives", handlers.ListSpocDrives)        // Drives the SPOC is attached to
+	admin.Get("/spocs/:id/contacts", handlers.ListSpocContactLogs) // Calls, emails, visits
+	admin.Post("/spocs/:id/contacts", handlers.CreateSpocContactLog)
+	admin.Put("/spocs/:id/contacts/:log_id", handlers.UpdateSpocContactLog)
+	admin.Delete("/spocs/:id/contacts/:log_id", handlers.DeleteSpocContactLog)
+	// Admin Only Companies Master
+	admin.Get("/companies", handlers.ListCompanies)
+	admin.Post("/companies", handlers.CreateCompany)
+	admin.Get("/companies/:id", handlers.GetCompany)
+	admin.Put("/companies/:id", handlers.UpdateCompany)
+	admin.Post("/companies/:id/merge", handlers.MergeCompanies)              // Fold duplicates into this company
+	admin.Post("/companies/:id/refresh-brand", handlers.RefreshCompanyBrand) // Re-fetch logo from the domain
+	admin.Get("/companies/:id/history", handlers.GetCompanyHistory)          // Drives per year, offers, CTC trend
+	admin.Get("/companies/:id/recruiters", handlers.ListCompanyRecruiters)
+	admin.Post("/companies/:id/recruiters", handlers.CreateRecruiter) // Recruiter login for a company HR
+	admin.Delete("/recruiters/:id", handlers.DeleteRecruiter)
+	admin.Get("/audit-logs", handlers.ListAuditLogs)
+	// Recruiter Portal (scoped to the recruiter's company, every action audited)
+	recruiter := v1.Group("/recruiter", middleware.RecruiterOnly)
+	recruiter.Get("/me", handlers.GetRecruiterProfile)
+	recruiter.Get("/drives", handlers.ListRecruiterDrives)
+	recruiter.Get("/drives/:id/applicants", handlers.GetRecruiterDriveApplicants) // ?format=csv to download
+	recruiter.Get("/drives/:id/applicants/:student_id/resume", handlers.GetRecruiterApplicantResume)
+	recruiter.Post("/drives/:id/results", handlers.UploadRecruiterResults)
+	recruiter.Post("/drives/:id/offers/:student_id", handlers.UploadRecruiterOffer)
+	// Admin Only WhatsApp Template Registry
+	admin.Get("/whatsapp/templates", handlers.ListWhatsAppTemplates)         // Event -> Template mapping
+	admin.Put("/whatsapp/templates/:event", handlers.UpdateWhatsAppTemplate) // Configure template for an event
+	// Admin Only Incoming Webhook Events
+	admin.Get("/webhook-events", handlers.ListWebhookEvents)              // List stored events
+	admin.Get("/webhook-events/:id", handlers.GetWebhookEvent)            // Inspect headers/body/outcome
+	admin.Post("/webhook-events/:id/replay", handlers.ReplayWebhookEvent) // Re-run handlers
+	// Admin Only Outgoing Webhook Subscriptions
+	admin.Get("/outgoing-webhooks", handlers.ListOutgoingWebhooks)
+	admin.Post("/outgoing-webhooks", handlers.CreateOutgoingWebhook)
+	admin.Put("/outgoing-webhooks/:id", handlers.UpdateOutgoingWebhook)
+	admin.Delete("/outgoing-webhooks/:id", handlers.DeleteOutgoingWebhook)
+	admin.Get("/outgoing-webhooks/:id/deliveries", handlers.ListOutgoingWebhookDeliveries)                    // Delivery log
+	admin.Post("/outgoing-webhooks/:id/deliveries/:delivery_id/retry", handlers.RetryOutgoingWebhookDelivery) // Retry dead letter
+
+	admin.Get("/jobs", handlers.ListJobs)              // Background jobs, schedules and last runs
+	admin.Get("/jobs/runs/:id", handlers.GetJobRun)    // Poll a single run
+	admin.Post("/jobs/:name/run", handlers.TriggerJob) // Run now
+	admin.Get("/jobs/:name/runs", handlers.ListJobRuns)
 
 	// Example: Only logged-in users can see this
 	v1.Get("/profile", func(c *fiber.Ctx) error {
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidCheckInToken  = errors.New("invalid check-in token")
+	ErrCheckInTokenExpired  = errors.New("check-in token has expired")
+	ErrCheckInNotConfigured = errors.New("CHECKIN_QR_SECRET is not set")
+)
+
+// checkInSecret signs QR tokens. It must be its own secret (not the JWT secret) so a leaked
+// QR signing key can't be used to forge logins, and so it can be rotated on its own.
+func checkInSecret() ([]byte, error) {
+	s := os.Getenv("CHECKIN_QR_SECRET")
+	if s == "" {
+		return nil, ErrCheckInNotConfigured
+	}
+	return []byte(s), nil
+}
+
+// CheckInTokenTTL is how long a check-in QR stays valid (CHECKIN_QR_TTL_SECONDS, default 5 minutes).
+// The student app refreshes the QR before it expires, so a shared screenshot soon stops working.
+func CheckInTokenTTL() time.Duration {
+	if n, err := strconv.Atoi(os.Getenv("CHECKIN_QR_TTL_SECONDS")); err == nil && n > 0 {
+		return time.Duration(n) * time.Second
+	}
+	return 5 * time.Minute
+}
+
+func checkInSignature(secret []byte, driveID, studentID int64, roundIndex int, expires int64) string {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "checkin:%d:%d:%d:%d", driveID, studentID, roundIndex, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// SignCheckInToken returns the QR payload identifying a student at one round of a drive:
+// "<drive>.<student>.<round>.<expires unix>.<signature>", valid for CheckInTokenTTL.
+func SignCheckInToken(driveID, studentID int64, roundIndex int) (string, time.Time, error) {
+	secret, err := checkInSecret()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expires := time.Now().Add(CheckInTokenTTL()).Unix()
+	token := fmt.Sprintf("%d.%d.%d.%d.%s", driveID, studentID, roundIndex, expires,
+		checkInSignature(secret, driveID, studentID, roundIndex, expires))
+	return token, time.Unix(expires, 0), nil
+}
+
+// ParseCheckInToken verifies a scanned QR token and returns the drive, student and round it was issued for
+func ParseCheckInToken(token string) (int64, int64, int, error) {
+	secret, err := checkInSecret()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 5 {
+		return 0, 0, 0, ErrInvalidCheckInToken
+	}
+	driveID, err1 := strconv.ParseInt(parts[0], 10, 64)
+	studentID, err2 := strconv.ParseInt(parts[1], 10, 64)
+	roundIndex, err3 := strconv.Atoi(parts[2])
+	expires, err4 := strconv.ParseInt(parts[3], 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+		return 0, 0, 0, ErrInvalidCheckInToken
+	}
+	if !hmac.Equal([]byte(parts[4]), []byte(checkInSignature(secret, driveID, studentID, roundIndex, expires))) {
+		return 0, 0, 0, ErrInvalidCheckInToken
+	}
+	if time.Now().Unix() > expires {
+		return 0, 0, 0, ErrCheckInTokenExpired
+	}
+	return driveID, studentID, roundIndex, nil
+}
+
+// NoShowPenaltyDrives is how many subsequently posted drives a no-show is blocked from
+// (NO_SHOW_PENALTY_DRIVES, default 0 = no penalty, absences are only recorded)
+func NoShowPenaltyDrives() int {
+	n, err := strconv.Atoi(os.Getenv("NO_SHOW_PENALTY_DRIVES"))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
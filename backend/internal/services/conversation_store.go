@@ -0,0 +1,81 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// ConversationState is a pending multi-step action for a WhatsApp user,
+// e.g. "apply to drive 42" waiting for the student to tap Confirm.
+type ConversationState struct {
+	Action    string // 'apply', 'withdraw'
+	DriveID   int64
+	StudentID int64
+	Reason    string // withdraw only
+	ExpiresAt time.Time
+}
+
+// ConversationStore keeps bot conversation state keyed by the sender's number
+type ConversationStore interface {
+	Get(number string) (ConversationState, bool)
+	Set(number string, state ConversationState)
+	Clear(number string)
+}
+
+// MemoryConversationStore is an in-process ConversationStore with per-entry expiry.
+// State is lost on restart, which is acceptable for short-lived confirmations.
+type MemoryConversationStore struct {
+	mu     sync.Mutex
+	states map[string]ConversationState
+	ttl    time.Duration
+}
+
+// NewMemoryConversationStore creates a store whose entries expire after ttl
+func NewMemoryConversationStore(ttl time.Duration) *MemoryConversationStore {
+	return &MemoryConversationStore{
+		states: make(map[string]ConversationState),
+		ttl:    ttl,
+	}
+}
+
+// DefaultConversationStore is shared by the WhatsApp webhook handler
+var DefaultConversationStore ConversationStore = NewMemoryConversationStore(5 * time.Minute)
+
+func (s *MemoryConversationStore) Get(number string) (ConversationState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[number]
+	if !ok {
+		return ConversationState{}, false
+	}
+	if time.Now().After(state.ExpiresAt) {
+		delete(s.states, number)
+		return ConversationState{}, false
+	}
+	return state, true
+}
+
+func (s *MemoryConversationStore) Set(number string, state ConversationState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state.ExpiresAt.IsZero() {
+		state.ExpiresAt = time.Now().Add(s.ttl)
+	}
+	s.states[number] = state
+
+	// Opportunistic sweep so abandoned conversations don't pile up
+	now := time.Now()
+	for k, v := range s.states {
+		if now.After(v.ExpiresAt) {
+			delete(s.states, k)
+		}
+	}
+}
+
+func (s *MemoryConversationStore) Clear(number string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, number)
+}
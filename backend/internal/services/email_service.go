@@ -0,0 +1,434 @@
+package services
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"embed"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed email_templates/*
+var emailTemplateFS embed.FS
+
+var (
+	emailHTMLTemplates = htmltemplate.Must(htmltemplate.ParseFS(emailTemplateFS, "email_templates/*.html"))
+	emailTextTemplates = texttemplate.Must(texttemplate.ParseFS(emailTemplateFS, "email_templates/*.txt"))
+)
+
+// Email templates (files in email_templates/<name>.html, <name>.txt; the subject is the "<name>_subject" block of the .txt)
+const (
+	EmailTemplateOTP               = "otp"
+	EmailTemplateNewDrive          = "new_drive"
+	EmailTemplateApplicationStatus = "application_status"
+	EmailTemplateDeploymentFailed  = "deployment_failed"
+	EmailTemplateDeadlineReminder  = "deadline_reminder"
+	EmailTemplateInterviewSlot     = "interview_slot"
+	EmailTemplatePanelSchedule     = "panel_schedule"
+	EmailTemplateWithdrawalAttempt = "withdrawal_attempt"
+)
+
+// EmailService sends mail over SMTP, or writes .eml files to a directory in development
+type EmailService struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	FromName string
+	TLSMode  string // 'starttls' (default), 'tls' (implicit, port 465), 'none'
+	SinkDir  string // If set, messages are written here instead of being sent
+}
+
+func NewEmailService() *EmailService {
+	s := &EmailService{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+		FromName: os.Getenv("SMTP_FROM_NAME"),
+		TLSMode:  strings.ToLower(os.Getenv("SMTP_TLS")),
+		SinkDir:  os.Getenv("EMAIL_SINK_DIR"),
+	}
+	// Defaults keep the original Gmail setup (SMTP_EMAIL / SMTP_PASSWORD) working unchanged
+	if s.Host == "" {
+		s.Host = "smtp.gmail.com"
+	}
+	if s.Port == "" {
+		s.Port = "587"
+	}
+	if s.Username == "" {
+		s.Username = os.Getenv("SMTP_EMAIL")
+	}
+	if s.From == "" {
+		s.From = os.Getenv("SMTP_EMAIL")
+	}
+	if s.FromName == "" {
+		s.FromName = "KEC Placement Cell"
+	}
+	if s.TLSMode == "" {
+		s.TLSMode = "starttls"
+		if s.Port == "465" {
+			s.TLSMode = "tls"
+		}
+	}
+	return s
+}
+
+// EmailAttachment is a file attached to an email
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// EmailMessage is a fully rendered email
+type EmailMessage struct {
+	To          []string
+	Subject     string
+	HTML        string
+	Text        string
+	Attachments []EmailAttachment
+}
+
+// BulkEmailRecipient is one recipient of a bulk send with their personalisation data
+type BulkEmailRecipient struct {
+	Email string
+	Data  map[string]interface{} // Merged over the shared data, e.g. {"Name": "Hari"}
+}
+
+// RenderEmail renders the subject, HTML and text parts of a template
+func RenderEmail(name string, data interface{}) (string, string, string, error) {
+	var subject, text, html bytes.Buffer
+	if err := emailTextTemplates.ExecuteTemplate(&subject, name+"_subject", data); err != nil {
+		return "", "", "", fmt.Errorf("render %s subject: %w", name, err)
+	}
+	if err := emailTextTemplates.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return "", "", "", fmt.Errorf("render %s text: %w", name, err)
+	}
+	if err := emailHTMLTemplates.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return "", "", "", fmt.Errorf("render %s html: %w", name, err)
+	}
+	return strings.TrimSpace(subject.String()), html.String(), strings.TrimSpace(text.String()) + "\n", nil
+}
+
+// SendTemplate renders a template and sends it to the given recipients
+func (s *EmailService) SendTemplate(to []string, name string, data interface{}, attachments []EmailAttachment) error {
+	subject, html, text, err := RenderEmail(name, data)
+	if err != nil {
+		return err
+	}
+	return s.Send(EmailMessage{To: to, Subject: subject, HTML: html, Text: text, Attachments: attachments})
+}
+
+// maxMessagesPerSMTPConnection bounds how many messages SendBulk sends over one connection
+// (Gmail drops sessions after ~100 messages)
+const maxMessagesPerSMTPConnection = 100
+
+// SendBulk sends one personalised copy of a template per recipient (recipients never see each other).
+// Messages go out over one authenticated SMTP connection, reopened every maxMessagesPerSMTPConnection.
+// Returns the number of messages sent and the failures keyed by email.
+func (s *EmailService) SendBulk(name string, shared map[string]interface{}, recipients []BulkEmailRecipient, attachments []EmailAttachment) (int, map[string]error) {
+	failures := map[string]error{}
+	sent := 0
+
+	var client *smtp.Client
+	perConnection := 0
+	defer func() {
+		if client != nil {
+			client.Quit()
+		}
+	}()
+
+	for i, r := range recipients {
+		data := make(map[string]interface{}, len(shared)+len(r.Data))
+		for k, v := range shared {
+			data[k] = v
+		}
+		for k, v := range r.Data {
+			data[k] = v
+		}
+
+		subject, html, text, err := RenderEmail(name, data)
+		if err != nil {
+			failures[r.Email] = err
+			continue
+		}
+		msg := EmailMessage{To: []string{r.Email}, Subject: subject, HTML: html, Text: text, Attachments: attachments}
+		if s.SinkDir != "" {
+			if err := s.Send(msg); err != nil {
+				failures[r.Email] = err
+				continue
+			}
+			sent++
+			continue
+		}
+
+		raw, err := s.buildMIME(msg)
+		if err != nil {
+			failures[r.Email] = err
+			continue
+		}
+
+		if client != nil && perConnection >= maxMessagesPerSMTPConnection {
+			client.Quit()
+			client = nil
+		}
+		if client == nil {
+			if client, err = s.dialSMTP(); err != nil {
+				// The server is unreachable; don't wait out the dial timeout for every recipient
+				for _, rest := range recipients[i:] {
+					failures[rest.Email] = fmt.Errorf("failed to send email: %w", err)
+				}
+				break
+			}
+			perConnection = 0
+		}
+
+		perConnection++
+		if err := s.deliverSMTP(client, msg.To, raw); err != nil {
+			failures[r.Email] = fmt.Errorf("failed to send email: %w", err)
+			// A rejected recipient leaves the session usable after RSET; anything else reconnects
+			if client.Reset() != nil {
+				client.Close()
+				client = nil
+			}
+			continue
+		}
+		sent++
+	}
+	return sent, failures
+}
+
+// Send delivers a rendered message (or writes it to SinkDir)
+func (s *EmailService) Send(msg EmailMessage) error {
+	if len(msg.To) == 0 {
+		return fmt.Errorf("no recipients")
+	}
+
+	raw, err := s.buildMIME(msg)
+	if err != nil {
+		return err
+	}
+
+	if s.SinkDir != "" {
+		return s.writeToSink(msg, raw)
+	}
+	if err := s.sendSMTP(msg.To, raw); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+func (s *EmailService) sendSMTP(to []string, raw []byte) error {
+	client, err := s.dialSMTP()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := s.deliverSMTP(client, to, raw); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// dialSMTP opens a connection using TLSMode and authenticates when credentials are set.
+// net/smtp only sends credentials over TLS or to localhost, so TLSMode "none" with a
+// username works only for a local relay.
+func (s *EmailService) dialSMTP() (*smtp.Client, error) {
+	if s.TLSMode == "none" && s.Username != "" && !isLocalSMTPHost(s.Host) {
+		return nil, fmt.Errorf("SMTP_TLS=none can't authenticate to %s; use starttls or tls, or a localhost relay", s.Host)
+	}
+
+	addr := net.JoinHostPort(s.Host, s.Port)
+	tlsConfig := &tls.Config{ServerName: s.Host}
+
+	var client *smtp.Client
+	if s.TLSMode == "tls" {
+		conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 15 * time.Second}, "tcp", addr, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+		client, err = smtp.NewClient(conn, s.Host)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	} else {
+		conn, err := net.DialTimeout("tcp", addr, 15*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		client, err = smtp.NewClient(conn, s.Host)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if s.TLSMode == "starttls" {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+
+	if s.Username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(smtp.PlainAuth("", s.Username, s.Password, s.Host)); err != nil {
+				client.Close()
+				return nil, err
+			}
+		}
+	}
+	return client, nil
+}
+
+// deliverSMTP sends one message over an open session
+func (s *EmailService) deliverSMTP(client *smtp.Client, to []string, raw []byte) error {
+	if err := client.Mail(s.From); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(raw); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+func isLocalSMTPHost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+var sinkNameSanitizer = regexp.MustCompile(`[^a-zA-Z0-9@._-]+`)
+
+func (s *EmailService) writeToSink(msg EmailMessage, raw []byte) error {
+	if err := os.MkdirAll(s.SinkDir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s_%s_%s.eml",
+		time.Now().Format("20060102-150405.000"),
+		sinkNameSanitizer.ReplaceAllString(msg.To[0], "_"),
+		randomHex(3))
+	path := filepath.Join(s.SinkDir, name)
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Email Sink: Wrote \"%s\" for %s to %s\n", msg.Subject, strings.Join(msg.To, ", "), path)
+	return nil
+}
+
+// buildMIME assembles multipart/mixed { multipart/alternative { text, html }, attachments... }
+func (s *EmailService) buildMIME(msg EmailMessage) ([]byte, error) {
+	var buf bytes.Buffer
+
+	domain := "localhost"
+	if at := strings.LastIndex(s.From, "@"); at >= 0 {
+		domain = s.From[at+1:]
+	}
+	idBytes := make([]byte, 12)
+	rand.Read(idBytes)
+
+	mixed := multipart.NewWriter(&buf)
+	headers := []string{
+		"From: " + (&mail.Address{Name: s.FromName, Address: s.From}).String(),
+		"To: " + strings.Join(msg.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: <" + hex.EncodeToString(idBytes) + "@" + domain + ">",
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/mixed; boundary=" + mixed.Boundary(),
+	}
+	buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	// Body alternatives
+	var alt bytes.Buffer
+	altWriter := multipart.NewWriter(&alt)
+	if msg.Text != "" {
+		if err := writeQuotedPrintablePart(altWriter, "text/plain; charset=utf-8", msg.Text); err != nil {
+			return nil, err
+		}
+	}
+	if msg.HTML != "" {
+		if err := writeQuotedPrintablePart(altWriter, "text/html; charset=utf-8", msg.HTML); err != nil {
+			return nil, err
+		}
+	}
+	altWriter.Close()
+
+	altPart, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + altWriter.Boundary()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	altPart.Write(alt.Bytes())
+
+	// Attachments (base64, 76-char lines)
+	for _, a := range msg.Attachments {
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": a.Filename})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString(a.Data)
+		for len(encoded) > 76 {
+			part.Write([]byte(encoded[:76] + "\r\n"))
+			encoded = encoded[76:]
+		}
+		part.Write([]byte(encoded + "\r\n"))
+	}
+
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintablePart(w *multipart.Writer, contentType, body string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
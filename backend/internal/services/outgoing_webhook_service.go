@@ -0,0 +1,94 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+)
+
+// OutgoingWebhookMaxAttempts is how many times a delivery is tried before it goes dead
+const OutgoingWebhookMaxAttempts = 6
+
+// outgoingWebhookBackoff is the wait after attempt N fails (index = attempts so far - 1)
+var outgoingWebhookBackoff = []time.Duration{
+	1 * time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour, 12 * time.Hour,
+}
+
+var outgoingWebhookHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// NewWebhookSecret returns a random hex secret for a new subscription
+func NewWebhookSecret() string {
+	return randomHex(32)
+}
+
+// NewWebhookEventID returns a random ID identifying one portal event across all its deliveries
+func NewWebhookEventID() string {
+	return "evt_" + randomHex(12)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand never fails on supported platforms
+	}
+	return hex.EncodeToString(b)
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<body>".
+// This is the same scheme our own /api/webhooks/generic endpoint verifies.
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// DeliverWebhook POSTs a signed delivery to its subscriber.
+// Returns the HTTP status code (0 if no response) and an error for anything but 2xx.
+func DeliverWebhook(d models.OutgoingWebhookDelivery) (int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest("POST", d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "KEC-Placement-Portal-Webhooks/1.0")
+	req.Header.Set("X-Webhook-Id", strconv.FormatInt(d.ID, 10))
+	req.Header.Set("X-Webhook-Event", d.EventType)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhookPayload(d.Secret, timestamp, d.Payload))
+
+	resp, err := outgoingWebhookHTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("subscriber responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// NextWebhookAttempt returns when a failed delivery should be retried, or false if it should go dead
+func NextWebhookAttempt(attempts int) (time.Time, bool) {
+	if attempts >= OutgoingWebhookMaxAttempts || attempts < 1 {
+		return time.Time{}, false
+	}
+	idx := attempts - 1
+	if idx >= len(outgoingWebhookBackoff) {
+		idx = len(outgoingWebhookBackoff) - 1
+	}
+	return time.Now().Add(outgoingWebhookBackoff[idx]), true
+}
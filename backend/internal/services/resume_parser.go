@@ -0,0 +1,277 @@
+package services
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+)
+
+// Resume field detection over the plain text from utils.ExtractResumeText. Everything is
+// pattern based, so results are suggestions for the student to confirm, never applied directly.
+
+var (
+	resumeEmailRe = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	resumePhoneRe = regexp.MustCompile(`(?:^|[^\d+])(?:\+?91[\s\-]?|0)?([6-9]\d{4})[\s\-]?(\d{5})(?:\D|$)`)
+	resumeYearRe  = regexp.MustCompile(`\b(19[89]\d|20[0-4]\d)\b`)
+	resumeCGPARe  = regexp.MustCompile(`(?i)\b(?:c\.?g\.?p\.?a|gpa|cpi)\b\s*(?:[:\-]|of)?\s*(\d{1,2}(?:\.\d{1,2})?)(?:\s*/\s*10(?:\.0+)?)?`)
+	resumeOutOfRe = regexp.MustCompile(`\b(\d(?:\.\d{1,2})?|10(?:\.0{1,2})?)\s*/\s*10\b`)
+	resumePctRe   = regexp.MustCompile(`\b(\d{2}(?:\.\d{1,2})?|100)\s*%`)
+
+	resumeLinkRes = map[string]*regexp.Regexp{
+		"linkedin":   regexp.MustCompile(`(?i)\blinkedin\.com/in/[A-Za-z0-9_\-%]+`),
+		"github":     regexp.MustCompile(`(?i)\bgithub\.com/[A-Za-z0-9_\-]+`),
+		"leetcode":   regexp.MustCompile(`(?i)\bleetcode\.com/(?:u/)?[A-Za-z0-9_\-]+`),
+		"hackerrank": regexp.MustCompile(`(?i)\bhackerrank\.com/(?:profile/)?[A-Za-z0-9_\-]+`),
+		"codechef":   regexp.MustCompile(`(?i)\bcodechef\.com/users/[A-Za-z0-9_\-]+`),
+	}
+
+	resumeLanguagesRe = regexp.MustCompile(`(?i)\blanguages?\s*(?:known|spoken)?\s*[:\-]\s*(.+)`)
+
+	// Checked in this order: "Higher Secondary School" is 12th, not 10th
+	resumeEducationLevels = []struct {
+		level string
+		re    *regexp.Regexp
+	}{
+		{"pg", regexp.MustCompile(`\bM\.\s?E\b|\bM\.\s?Tech\b|\bMTech\b|\bM\.?\s?C\.?\s?A\b|\bMBA\b|\bM\.\s?Sc\b|\bMSc\b|(?i)\bmaster(?:'?s)?\s+(?:of|in)\b`)},
+		{"ug", regexp.MustCompile(`\bB\.\s?E\b|\bB\.\s?Tech\b|\bBTech\b|\bB\.\s?Sc\b|\bBSc\b|\bB\.?\s?C\.?\s?A\b|\bB\.\s?Com\b|\bBBA\b|(?i)\bbachelor(?:'?s)?\s+(?:of|in)\b`)},
+		{"diploma", regexp.MustCompile(`(?i)\bdiploma\b`)},
+		{"twelfth", regexp.MustCompile(`(?i)\bhsc\b|\b12th\b|\bxii\b|\bclass\s*12\b|\bhigher\s+secondary\b|\bintermediate\b|\bpuc\b`)},
+		{"tenth", regexp.MustCompile(`(?i)\bsslc\b|\b10th\b|\bclass\s*x\b|\bclass\s*10\b|\bsecondary\s+school\b|\bmatriculation\b|\bssc\b`)},
+	}
+	resumeInstitutionRe = regexp.MustCompile(`(?i)\b(college|university|school|institute|institution|polytechnic|vidyalaya|academy|iit|nit)\b`)
+	resumeBoardRes      = []struct {
+		name string
+		re   *regexp.Regexp
+	}{
+		{"CBSE", regexp.MustCompile(`(?i)\bcbse\b`)},
+		{"ICSE", regexp.MustCompile(`(?i)\bicse\b|\bisc\b`)},
+		{"State Board", regexp.MustCompile(`(?i)\bstate\s+board\b`)},
+		{"Matriculation", regexp.MustCompile(`(?i)\bmatriculation\b`)},
+	}
+
+	resumeKnownLanguages = []string{
+		"English", "Tamil", "Hindi", "Telugu", "Malayalam", "Kannada", "Marathi", "Bengali", "Gujarati",
+		"Urdu", "Punjabi", "Odia", "Sanskrit", "French", "German", "Spanish", "Japanese", "Chinese",
+		"Korean", "Arabic", "Russian", "Italian",
+	}
+	resumeWordRe = regexp.MustCompile(`[A-Za-z]+`)
+
+	// Canonical skill name -> extra spellings. Single letters ("C", "R") and "Go" are left out:
+	// they match too much ordinary text.
+	resumeSkills = map[string][]string{
+		"C++": {"cpp"}, "C#": {}, "Java": {}, "Python": {}, "JavaScript": {"js"}, "TypeScript": {},
+		"Golang": {}, "Rust": {}, "Kotlin": {}, "Swift": {}, "PHP": {}, "Ruby": {}, "Scala": {}, "Dart": {},
+		"MATLAB": {}, "SQL": {}, "MySQL": {}, "PostgreSQL": {"postgres"}, "MongoDB": {}, "Redis": {},
+		"Oracle": {}, "Firebase": {}, "HTML": {"html5"}, "CSS": {"css3"}, "React": {"reactjs", "react.js"},
+		"Angular": {}, "Vue.js": {"vue", "vuejs"}, "Next.js": {"nextjs"}, "Node.js": {"nodejs"},
+		"Express.js": {"expressjs"}, "Django": {}, "Flask": {}, "FastAPI": {},
+		"Spring Boot": {"springboot"}, "Flutter": {}, "React Native": {}, "Android": {},
+		"Tailwind CSS": {"tailwind", "tailwindcss"}, "Bootstrap": {}, "Git": {}, "GitHub": {}, "Docker": {},
+		"Kubernetes": {"k8s"}, "AWS": {}, "Azure": {}, "GCP": {"google cloud"}, "Linux": {}, "Jenkins": {},
+		"REST API": {"restful", "rest apis"}, "GraphQL": {}, "Machine Learning": {}, "Deep Learning": {},
+		"Data Science": {}, "Data Analytics": {}, "NLP": {}, "Computer Vision": {}, "TensorFlow": {},
+		"PyTorch": {}, "Keras": {}, "scikit-learn": {"sklearn"}, "Pandas": {}, "NumPy": {}, "Power BI": {},
+		"Tableau": {}, "Excel": {}, "Figma": {}, "Selenium": {}, "DSA": {"data structures"}, "OOP": {"oops"},
+		"DBMS": {}, "Operating Systems": {}, "Computer Networks": {}, "Embedded Systems": {}, "IoT": {},
+		"Arduino": {}, "Raspberry Pi": {}, "AutoCAD": {}, "SolidWorks": {}, "CATIA": {}, "ANSYS": {},
+		"VLSI": {}, "Verilog": {}, "PLC": {}, "Blockchain": {}, "Cyber Security": {"cybersecurity"},
+	}
+	resumeSkillRes = compileSkillPatterns()
+)
+
+type skillPattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+func compileSkillPatterns() []skillPattern {
+	names := make([]string, 0, len(resumeSkills))
+	for name := range resumeSkills {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	patterns := make([]skillPattern, 0, len(names))
+	for _, name := range names {
+		alts := []string{regexp.QuoteMeta(name)}
+		for _, a := range resumeSkills[name] {
+			alts = append(alts, regexp.QuoteMeta(a))
+		}
+		// Word boundaries that count + and # as letters, so "Java" doesn't match inside "JavaScript"
+		// and "C++" doesn't match "C#"
+		re := regexp.MustCompile(`(?i)(?:^|[^A-Za-z0-9+#])(?:` + strings.Join(alts, "|") + `)(?:$|[^A-Za-z0-9+#])`)
+		patterns = append(patterns, skillPattern{name, re})
+	}
+	return patterns
+}
+
+// ParseResume detects contact details, links, skills, languages and education in resume text
+func ParseResume(text string) models.ParsedResume {
+	parsed := models.ParsedResume{
+		Links:       map[string]string{},
+		Skills:      []string{},
+		Languages:   []string{},
+		Education:   []models.ResumeEducation{},
+		TextLength:  len(strings.TrimSpace(text)),
+		ExtractedAt: time.Now(),
+	}
+
+	parsed.Email = strings.ToLower(resumeEmailRe.FindString(text))
+	if m := resumePhoneRe.FindStringSubmatch(text); m != nil {
+		parsed.Phone = m[1] + m[2]
+	}
+	for key, re := range resumeLinkRes {
+		if m := re.FindString(text); m != "" {
+			parsed.Links[key] = "https://" + strings.TrimRight(m, "/.")
+		}
+	}
+	for _, p := range resumeSkillRes {
+		if p.re.MatchString(text) {
+			parsed.Skills = append(parsed.Skills, p.name)
+		}
+	}
+
+	lines := resumeLines(text)
+	parsed.Languages = parseResumeLanguages(lines)
+	parsed.Education = parseResumeEducation(lines)
+
+	if m := resumeCGPARe.FindStringSubmatch(text); m != nil {
+		parsed.CGPA = parseResumeCGPA(m[1])
+	}
+	return parsed
+}
+
+func resumeLines(text string) []string {
+	var lines []string
+	for _, l := range strings.Split(text, "\n") {
+		l = strings.Join(strings.Fields(l), " ")
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+func parseResumeLanguages(lines []string) []string {
+	found := []string{}
+	for _, line := range lines {
+		m := resumeLanguagesRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		// Only spoken languages: "Languages: Java, Python" is a skills line
+		words := map[string]bool{}
+		for _, w := range resumeWordRe.FindAllString(m[1], -1) {
+			words[strings.ToLower(w)] = true
+		}
+		for _, lang := range resumeKnownLanguages {
+			if words[strings.ToLower(lang)] {
+				found = append(found, lang)
+			}
+		}
+		if len(found) > 0 {
+			return found
+		}
+	}
+	return found
+}
+
+func educationLevel(line string) string {
+	for _, l := range resumeEducationLevels {
+		if l.re.MatchString(line) {
+			return l.level
+		}
+	}
+	return ""
+}
+
+// parseResumeEducation finds the first line for each level and reads its score, year and
+// institution from that line and the next few (resumes often stack them)
+func parseResumeEducation(lines []string) []models.ResumeEducation {
+	entries := []models.ResumeEducation{}
+	seen := map[string]bool{}
+	for i, line := range lines {
+		level := educationLevel(line)
+		if level == "" || seen[level] {
+			continue
+		}
+		seen[level] = true
+
+		window := []string{line}
+		for j := i + 1; j < len(lines) && j <= i+3; j++ {
+			// A school named "... Matriculation School" under "SSLC" is still the same entry
+			if l := educationLevel(lines[j]); l != "" && l != level {
+				break
+			}
+			window = append(window, lines[j])
+		}
+		block := strings.Join(window, "\n")
+
+		e := models.ResumeEducation{Level: level, Title: truncateRunes(line, 100)}
+		for _, w := range window {
+			if !resumeInstitutionRe.MatchString(w) {
+				continue
+			}
+			// "MCA, Kongu Engineering College" keeps only the part naming the institution
+			for _, part := range strings.Split(w, ",") {
+				if resumeInstitutionRe.MatchString(part) {
+					w = part
+					break
+				}
+			}
+			e.Institution = truncateRunes(cleanInstitution(w), 150)
+			break
+		}
+		for _, b := range resumeBoardRes {
+			if b.re.MatchString(block) {
+				e.Board = b.name
+				break
+			}
+		}
+		for _, y := range resumeYearRe.FindAllString(block, -1) {
+			if n, _ := strconv.Atoi(y); n > e.Year {
+				e.Year = n
+			}
+		}
+		if m := resumeCGPARe.FindStringSubmatch(block); m != nil {
+			e.CGPA = parseResumeCGPA(m[1])
+		} else if m := resumeOutOfRe.FindStringSubmatch(block); m != nil {
+			e.CGPA = parseResumeCGPA(m[1])
+		}
+		if m := resumePctRe.FindStringSubmatch(block); m != nil {
+			e.Percentage, _ = strconv.ParseFloat(m[1], 64)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+var resumeScoreRe = regexp.MustCompile(`(?i)\b(?:c\.?g\.?p\.?a|gpa|cpi)\b.*$|\d{1,3}(?:\.\d{1,2})?\s*%`)
+
+// cleanInstitution drops the years and scores that share the institution's line
+func cleanInstitution(line string) string {
+	line = resumeScoreRe.ReplaceAllString(line, "")
+	line = resumeOutOfRe.ReplaceAllString(line, "")
+	line = resumeYearRe.ReplaceAllString(line, "")
+	return strings.Trim(strings.Join(strings.Fields(line), " "), " ,-–|()")
+}
+
+func parseResumeCGPA(s string) float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v <= 0 || v > 10 {
+		return 0
+	}
+	return v
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
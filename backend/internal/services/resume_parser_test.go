@@ -0,0 +1,150 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+)
+
+func TestParseResumeCGPA(t *testing.T) {
+	tests := []struct {
+		text string
+		want float64
+	}{
+		{"CGPA: 8.72", 8.72},
+		{"CGPA - 8.5/10", 8.5},
+		{"C.G.P.A : 9.1 / 10.0", 9.1},
+		{"cgpa 7", 7},
+		{"GPA of 7.85", 7.85},
+		{"CPI: 9.02", 9.02},
+		{"CGPA: 85", 0},   // a percentage, not a CGPA
+		{"CGPA: 10.5", 0}, // out of range
+		{"Scored 85% in HSC", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := ParseResume(tt.text).CGPA; got != tt.want {
+				t.Errorf("CGPA = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResumePhone(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Phone: 9876543210", "9876543210"},
+		{"+91 98765 43210", "9876543210"},
+		{"+91-98765-43210", "9876543210"},
+		{"Mobile: +919876543210", "9876543210"},
+		{"Ph: 098765 43210", "9876543210"},
+		{"Roll No: 1234567890", ""},   // Indian mobiles start with 6-9
+		{"ID 987654321012", ""},       // part of a longer number
+		{"Batch 2019-2023, 2023", ""}, // years
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := ParseResume(tt.text).Phone; got != tt.want {
+				t.Errorf("Phone = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResumeLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want map[string]string
+	}{
+		{
+			name: "bare and full URLs",
+			text: "linkedin.com/in/priya-sharma-01 | https://github.com/priyas/ | www.leetcode.com/u/priya_s",
+			want: map[string]string{
+				"linkedin": "https://linkedin.com/in/priya-sharma-01",
+				"github":   "https://github.com/priyas",
+				"leetcode": "https://leetcode.com/u/priya_s",
+			},
+		},
+		{
+			name: "coding profiles",
+			text: "HackerRank: hackerrank.com/profile/priyas\nCodeChef: https://www.codechef.com/users/priya_s.",
+			want: map[string]string{
+				"hackerrank": "https://hackerrank.com/profile/priyas",
+				"codechef":   "https://codechef.com/users/priya_s",
+			},
+		},
+		{
+			name: "no profile path",
+			text: "Portfolio on github.io, see linkedin.com",
+			want: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseResume(tt.text).Links; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Links = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseResumeEducation(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []models.ResumeEducation
+	}{
+		{
+			name: "degree, 12th and 10th",
+			text: "EDUCATION\n" +
+				"B.E. Computer Science and Engineering\nKongu Engineering College, Perundurai\n2021 - 2025 | CGPA: 8.72\n" +
+				"Higher Secondary (HSC)\nSri Vidya Mandir School, State Board\n2021 | 92.4%\n" +
+				"SSLC\nSri Vidya Mandir School - Matriculation, 2019, 95%\n",
+			want: []models.ResumeEducation{
+				{Level: "ug", Title: "B.E. Computer Science and Engineering", Institution: "Kongu Engineering College", Year: 2025, CGPA: 8.72},
+				{Level: "twelfth", Title: "Higher Secondary (HSC)", Institution: "Sri Vidya Mandir School", Board: "State Board", Year: 2021, Percentage: 92.4},
+				{Level: "tenth", Title: "SSLC", Institution: "Sri Vidya Mandir School - Matriculation", Board: "Matriculation", Year: 2019, Percentage: 95},
+			},
+		},
+		{
+			name: "postgraduate and diploma",
+			text: "MCA, Kongu Engineering College 2024 8.9/10\n" +
+				"Diploma in Mechanical Engineering\nPSG Polytechnic College, 2019, 88.5%\n",
+			want: []models.ResumeEducation{
+				{Level: "pg", Title: "MCA, Kongu Engineering College 2024 8.9/10", Institution: "Kongu Engineering College", Year: 2024, CGPA: 8.9},
+				{Level: "diploma", Title: "Diploma in Mechanical Engineering", Institution: "PSG Polytechnic College", Year: 2019, Percentage: 88.5},
+			},
+		},
+		{
+			name: "only the first line per level",
+			text: "Master of Technology in VLSI\nM.E. (dropped)\nBachelor of Engineering\n",
+			want: []models.ResumeEducation{
+				{Level: "pg", Title: "Master of Technology in VLSI"},
+				{Level: "ug", Title: "Bachelor of Engineering"},
+			},
+		},
+		{
+			name: "12th board",
+			text: "Class 12, CBSE - Kendriya Vidyalaya, 2020, 89%",
+			want: []models.ResumeEducation{
+				{Level: "twelfth", Title: "Class 12, CBSE - Kendriya Vidyalaya, 2020, 89%", Institution: "CBSE - Kendriya Vidyalaya", Board: "CBSE", Year: 2020, Percentage: 89},
+			},
+		},
+		{
+			name: "none",
+			text: "Skills: Java, Python, SQL",
+			want: []models.ResumeEducation{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseResume(tt.text).Education; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Education =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
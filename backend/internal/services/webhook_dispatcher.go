@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+)
+
+// WebhookEventHandler processes one stored webhook event. Returning an error marks the event failed.
+type WebhookEventHandler func(ctx context.Context, event models.WebhookEvent) error
+
+var (
+	webhookHandlersMu sync.RWMutex
+	webhookHandlers   = map[string][]WebhookEventHandler{}
+)
+
+// RegisterWebhookHandler adds a handler for a source + event type.
+// Use "*" as eventType to receive every event from a source. Handlers run in registration order.
+func RegisterWebhookHandler(source, eventType string, h WebhookEventHandler) {
+	webhookHandlersMu.Lock()
+	defer webhookHandlersMu.Unlock()
+	key := source + ":" + eventType
+	webhookHandlers[key] = append(webhookHandlers[key], h)
+}
+
+// DispatchWebhookEvent runs every handler registered for the event and returns the resulting status
+// (processed, failed or ignored) along with the first handler error.
+func DispatchWebhookEvent(ctx context.Context, event models.WebhookEvent) (string, error) {
+	webhookHandlersMu.RLock()
+	handlers := append([]WebhookEventHandler{}, webhookHandlers[event.Source+":"+event.EventType]...)
+	handlers = append(handlers, webhookHandlers[event.Source+":*"]...)
+	webhookHandlersMu.RUnlock()
+
+	if len(handlers) == 0 {
+		return models.WebhookEventIgnored, nil
+	}
+
+	for _, h := range handlers {
+		if err := runWebhookHandler(ctx, h, event); err != nil {
+			return models.WebhookEventFailed, err
+		}
+	}
+	return models.WebhookEventProcessed, nil
+}
+
+// runWebhookHandler turns a handler panic into an error so one bad handler can't take the request down
+func runWebhookHandler(ctx context.Context, h WebhookEventHandler, event models.WebhookEvent) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panic: %v", r)
+		}
+	}()
+	return h(ctx, event)
+}
@@ -3,15 +3,33 @@ package services
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
+// whatsAppHTTPClient is shared by all WhatsAppService instances so connections are reused
+// and a hung Graph API call can never block a broadcast worker forever.
+var whatsAppHTTPClient = &http.Client{Timeout: 15 * time.Second}
+
+// errWhatsAppRateLimited marks responses that should be retried after backing off
+var errWhatsAppRateLimited = errors.New("whatsapp rate limit hit")
+
 type WhatsAppService struct {
 	PhoneNumberID string
 	AccessToken   string
 	APIURL        string
+
+	// Broadcast tuning (Cloud API default throughput is 80 msg/sec per number)
+	Workers       int // Concurrent senders
+	RatePerSecond int // Global cap across all workers
+	MaxRetries    int // Retries for rate-limited sends
 }
 
 func NewWhatsAppService() *WhatsAppService {
@@ -21,9 +39,19 @@ func NewWhatsAppService() *WhatsAppService {
 		PhoneNumberID: phoneID,
 		AccessToken:   token,
 		APIURL:        fmt.Sprintf("https://graph.facebook.com/v17.0/%s/messages", phoneID),
+		Workers:       envInt("WHATSAPP_BROADCAST_WORKERS", 5),
+		RatePerSecond: envInt("WHATSAPP_RATE_PER_SECOND", 20),
+		MaxRetries:    3,
 	}
 }
 
+// BroadcastResult is the outcome of sending to a single recipient
+type BroadcastResult struct {
+	Recipient string // Normalised number the message was sent to (or the raw input if invalid)
+	MessageID string // "wamid..." returned by the Cloud API, used to match status webhooks
+	Err       error
+}
+
 // SendMessage sends a free-form text message (Only allowed for replies within 24h)
 func (s *WhatsAppService) SendMessage(to string, body string) error {
 	payload := map[string]interface{}{
@@ -35,11 +63,44 @@ func (s *WhatsAppService) SendMessage(to string, body string) error {
 			"body": body,
 		},
 	}
-	return s.sendRequest(payload)
+	_, err := s.sendRequest(payload)
+	return err
+}
+
+// ReplyButton is a quick-reply button on an interactive message (max 3 per message)
+type ReplyButton struct {
+	ID    string // Returned to us in interactive.button_reply.id
+	Title string // Max 20 characters
+}
+
+// SendButtons sends an interactive message with quick-reply buttons (Only allowed for replies within 24h)
+func (s *WhatsAppService) SendButtons(to string, body string, buttons []ReplyButton) error {
+	btns := make([]map[string]interface{}, 0, len(buttons))
+	for _, b := range buttons {
+		btns = append(btns, map[string]interface{}{
+			"type":  "reply",
+			"reply": map[string]string{"id": b.ID, "title": b.Title},
+		})
+	}
+
+	payload := map[string]interface{}{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                to,
+		"type":              "interactive",
+		"interactive": map[string]interface{}{
+			"type":   "button",
+			"body":   map[string]string{"text": body},
+			"action": map[string]interface{}{"buttons": btns},
+		},
+	}
+	_, err := s.sendRequest(payload)
+	return err
 }
 
 // SendTemplateMessage sends a pre-approved template (Required for initiating conversation)
-func (s *WhatsAppService) SendTemplateMessage(to string, templateName string, language string, components []interface{}) error {
+// Returns the Cloud API message ID so delivery status webhooks can be matched later.
+func (s *WhatsAppService) SendTemplateMessage(to string, templateName string, language string, components []interface{}) (string, error) {
 	payload := map[string]interface{}{
 		"messaging_product": "whatsapp",
 		"to":                to,
@@ -55,55 +116,178 @@ func (s *WhatsAppService) SendTemplateMessage(to string, templateName string, la
 	return s.sendRequest(payload)
 }
 
-// SendBroadcast sends a template message to multiple numbers
-func (s *WhatsAppService) SendBroadcast(recipients []string, templateName string, components []interface{}) (int, error) {
-	successCount := 0
-	for _, number := range recipients {
-		// Clean number: Cloud API needs country code without +
-		// e.g. "919876543210"
-		cleanNum := number
-		if len(number) == 10 {
-			cleanNum = "91" + number
-		}
-		// Strip leading + if present
-		if cleanNum[0] == '+' {
-			cleanNum = cleanNum[1:]
-		}
+// SendBroadcast sends a template message to multiple numbers using a bounded worker pool.
+// All workers share one rate limiter so the overall throughput never exceeds RatePerSecond.
+// Returns the number of accepted messages and one result per recipient (in input order).
+func (s *WhatsAppService) SendBroadcast(recipients []string, templateName string, language string, components []interface{}) (int, []BroadcastResult) {
+	results := make([]BroadcastResult, len(recipients))
+	if len(recipients) == 0 {
+		return 0, results
+	}
+
+	workers := s.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(recipients) {
+		workers = len(recipients)
+	}
+	rate := s.RatePerSecond
+	if rate < 1 {
+		rate = 1
+	}
+
+	limiter := time.NewTicker(time.Second / time.Duration(rate))
+	defer limiter.Stop()
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
 
-		err := s.SendTemplateMessage(cleanNum, templateName, "en_US", components)
-		if err == nil {
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				raw := recipients[i]
+				number, err := NormalizePhoneNumber(raw)
+				if err != nil {
+					results[i] = BroadcastResult{Recipient: raw, Err: err}
+					continue
+				}
+
+				var msgID string
+				for attempt := 0; attempt <= s.MaxRetries; attempt++ {
+					<-limiter.C
+					msgID, err = s.SendTemplateMessage(number, templateName, language, components)
+					if !errors.Is(err, errWhatsAppRateLimited) {
+						break
+					}
+					// Exponential backoff: 1s, 2s, 4s...
+					time.Sleep(time.Duration(1<<attempt) * time.Second)
+				}
+				results[i] = BroadcastResult{Recipient: number, MessageID: msgID, Err: err}
+			}
+		}()
+	}
+
+	for i := range recipients {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	successCount := 0
+	for _, r := range results {
+		if r.Err == nil {
 			successCount++
 		} else {
-			fmt.Printf("Display Error: Failed to send to %s: %v\n", cleanNum, err)
+			fmt.Printf("WhatsApp Error: Failed to send to %s: %v\n", r.Recipient, r.Err)
+		}
+	}
+	return successCount, results
+}
+
+// NormalizePhoneNumber converts a stored mobile number into E.164 digits as the Cloud API
+// expects them (country code included, no leading '+'), e.g. "98765 43210" -> "919876543210".
+// Bare national numbers get WHATSAPP_DEFAULT_COUNTRY_CODE (default 91) prepended.
+func NormalizePhoneNumber(number string) (string, error) {
+	trimmed := strings.TrimSpace(number)
+	if trimmed == "" || strings.EqualFold(trimmed, "NA") {
+		return "", fmt.Errorf("empty phone number")
+	}
+
+	// Keep digits only (drops '+', spaces, dashes, brackets)
+	var b strings.Builder
+	for _, r := range trimmed {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
 		}
 	}
-	return successCount, nil
+	digits := b.String()
+
+	switch {
+	case strings.HasPrefix(trimmed, "+"):
+		// Already international
+	case strings.HasPrefix(digits, "00"):
+		// International dialling prefix
+		digits = digits[2:]
+	case len(digits) == 11 && digits[0] == '0':
+		// Trunk prefix on a national number, e.g. 09876543210
+		digits = defaultCountryCode() + digits[1:]
+	case len(digits) == 10:
+		digits = defaultCountryCode() + digits
+	}
+
+	// E.164 allows at most 15 digits; anything under 11 cannot carry a country code + subscriber number
+	if len(digits) < 11 || len(digits) > 15 || digits[0] == '0' {
+		return "", fmt.Errorf("invalid phone number %q", number)
+	}
+	return digits, nil
+}
+
+func defaultCountryCode() string {
+	if cc := strings.TrimPrefix(os.Getenv("WHATSAPP_DEFAULT_COUNTRY_CODE"), "+"); cc != "" {
+		return cc
+	}
+	return "91"
+}
+
+func envInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+		return v
+	}
+	return fallback
+}
+
+// graphAPIResponse covers both the success and error shapes of the /messages endpoint
+type graphAPIResponse struct {
+	Messages []struct {
+		ID string `json:"id"`
+	} `json:"messages"`
+	Error *struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error"`
 }
 
-func (s *WhatsAppService) sendRequest(payload interface{}) error {
+func (s *WhatsAppService) sendRequest(payload interface{}) (string, error) {
 	jsonBody, err := json.Marshal(payload)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	req, err := http.NewRequest("POST", s.APIURL, bytes.NewBuffer(jsonBody))
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	req.Header.Set("Authorization", "Bearer "+s.AccessToken)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := whatsAppHTTPClient.Do(req)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var parsed graphAPIResponse
+	_ = json.Unmarshal(body, &parsed)
+
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("API error: %s", resp.Status)
+		// 130429: throughput limit, 131056: pair rate limit (same recipient too often)
+		if resp.StatusCode == http.StatusTooManyRequests ||
+			(parsed.Error != nil && (parsed.Error.Code == 130429 || parsed.Error.Code == 131056)) {
+			return "", fmt.Errorf("%w: %s", errWhatsAppRateLimited, resp.Status)
+		}
+		if parsed.Error != nil {
+			return "", fmt.Errorf("API error: %s (code %d: %s)", resp.Status, parsed.Error.Code, parsed.Error.Message)
+		}
+		return "", fmt.Errorf("API error: %s", resp.Status)
 	}
 
-	return nil
+	if len(parsed.Messages) > 0 {
+		return parsed.Messages[0].ID, nil
+	}
+	return "", nil
 }
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+)
+
+// templateParamBuilders resolves a configurable parameter key to its value for a drive.
+// Admins pick keys from this list when mapping an event to a template, so the order of
+// the configured keys must match the {{1}}, {{2}}... variables of the approved template.
+var templateParamBuilders = map[string]func(d models.PlacementDrive) string{
+	"company_name":     func(d models.PlacementDrive) string { return d.CompanyName },
+	"job_role":         func(d models.PlacementDrive) string { return d.JobRole },
+	"location":         func(d models.PlacementDrive) string { return d.Location },
+	"drive_type":       func(d models.PlacementDrive) string { return d.DriveType },
+	"company_category": func(d models.PlacementDrive) string { return d.CompanyCategory },
+	"ctc_display":      func(d models.PlacementDrive) string { return d.CtcDisplay },
+	"min_cgpa":         func(d models.PlacementDrive) string { return strconv.FormatFloat(d.MinCgpa, 'f', 2, 64) },
+	"drive_id":         func(d models.PlacementDrive) string { return strconv.FormatInt(d.ID, 10) },
+	"deadline_date": func(d models.PlacementDrive) string {
+		return d.DeadlineDate.Format("02 Jan 2006, 03:04 PM")
+	},
+	"drive_date": func(d models.PlacementDrive) string {
+		if !d.DriveDate.Valid {
+			return "TBA"
+		}
+		return d.DriveDate.Time.Format("02 Jan 2006")
+	},
+}
+
+// DefaultWhatsAppTemplates is used for events that have not been configured by an admin yet.
+// The same rows are seeded in project_schema.sql.
+var DefaultWhatsAppTemplates = map[string]models.WhatsAppTemplate{
+	models.WhatsAppEventNewDrive: {
+		Event: models.WhatsAppEventNewDrive, TemplateName: "new_drive_alert", LanguageCode: "en_US",
+		Parameters: []string{"company_name", "job_role", "deadline_date"}, IsActive: true,
+	},
+	models.WhatsAppEventDeadlineReminder: {
+		Event: models.WhatsAppEventDeadlineReminder, TemplateName: "drive_deadline_reminder", LanguageCode: "en_US",
+		Parameters: []string{"company_name", "job_role", "deadline_date"}, IsActive: true,
+	},
+	models.WhatsAppEventShortlisted: {
+		Event: models.WhatsAppEventShortlisted, TemplateName: "drive_shortlisted", LanguageCode: "en_US",
+		Parameters: []string{"company_name", "job_role", "drive_date"}, IsActive: true,
+	},
+	models.WhatsAppEventOffer: {
+		Event: models.WhatsAppEventOffer, TemplateName: "drive_offer", LanguageCode: "en_US",
+		Parameters: []string{"company_name", "job_role", "ctc_display"}, IsActive: true,
+	},
+}
+
+// IsValidWhatsAppEvent reports whether the event can be mapped to a template
+func IsValidWhatsAppEvent(event string) bool {
+	_, ok := DefaultWhatsAppTemplates[event]
+	return ok
+}
+
+// TemplateParameterKeys lists the drive fields admins can use as template variables
+func TemplateParameterKeys() []string {
+	keys := make([]string, 0, len(templateParamBuilders))
+	for k := range templateParamBuilders {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ValidateTemplateParameters returns an error naming the first unknown parameter key
+func ValidateTemplateParameters(params []string) error {
+	for _, p := range params {
+		if _, ok := templateParamBuilders[p]; !ok {
+			return fmt.Errorf("unknown template parameter %q", p)
+		}
+	}
+	return nil
+}
+
+// BuildTemplateComponents renders the body component for a template using the drive's fields.
+// Templates without parameters (e.g. "hello_world") get no components at all.
+func BuildTemplateComponents(tpl models.WhatsAppTemplate, d models.PlacementDrive) []interface{} {
+	if len(tpl.Parameters) == 0 {
+		return nil
+	}
+
+	params := make([]map[string]string, 0, len(tpl.Parameters))
+	for _, key := range tpl.Parameters {
+		value := ""
+		if build, ok := templateParamBuilders[key]; ok {
+			value = build(d)
+		}
+		// Cloud API rejects empty text parameters
+		if value == "" {
+			value = "-"
+		}
+		params = append(params, map[string]string{"type": "text", "text": value})
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"type":       "body",
+			"parameters": params,
+		},
+	}
+}
+
+// SendDriveTemplate sends the event template configured for a drive to a single number
+func (s *WhatsAppService) SendDriveTemplate(to string, tpl models.WhatsAppTemplate, d models.PlacementDrive) (string, error) {
+	return s.SendTemplateMessage(to, tpl.TemplateName, tpl.LanguageCode, BuildTemplateComponents(tpl, d))
+}
+
+// BroadcastDriveTemplate sends the event template configured for a drive to many numbers
+func (s *WhatsAppService) BroadcastDriveTemplate(recipients []string, tpl models.WhatsAppTemplate, d models.PlacementDrive) (int, []BroadcastResult) {
+	return s.SendBroadcast(recipients, tpl.TemplateName, tpl.LanguageCode, BuildTemplateComponents(tpl, d))
+}
+
+// maxRecipientLen is the width of whatsapp_messages.recipient
+const maxRecipientLen = 20
+
+// BroadcastMessages converts broadcast results into delivery records ready to be persisted.
+// Invalid numbers are kept as typed for the admin view, cut to fit the recipient column
+// so one bad entry can't abort the whole batch insert.
+func BroadcastMessages(event, templateName string, driveID *int64, results []BroadcastResult) []models.WhatsAppMessage {
+	messages := make([]models.WhatsAppMessage, 0, len(results))
+	for _, r := range results {
+		recipient := r.Recipient
+		if runes := []rune(recipient); len(runes) > maxRecipientLen {
+			recipient = string(runes[:maxRecipientLen])
+		}
+		m := models.WhatsAppMessage{
+			MessageID:    r.MessageID,
+			Recipient:    recipient,
+			DriveID:      driveID,
+			Event:        event,
+			TemplateName: templateName,
+			Status:       "accepted",
+		}
+		if r.Err != nil {
+			m.Status = "failed"
+			m.Error = r.Err.Error()
+		}
+		messages = append(messages, m)
+	}
+	return messages
+}
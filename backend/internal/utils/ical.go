@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+// ICalEvent is a single VEVENT in an iCalendar (RFC 5545) file
+type ICalEvent struct {
+	UID         string // Globally unique and stable, so calendar apps update the event instead of duplicating it
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time // For all-day events, the day after the last day (defaults to Start + 1 day)
+	AllDay      bool      // Date only, shown on the day in the viewer's own time zone
+	Updated     time.Time // Optional, becomes LAST-MODIFIED
+}
+
+const (
+	icalTimeFormat = "20060102T150405Z"
+	icalDateFormat = "20060102"
+)
+
+// BuildICal renders events as a .ics file that Google Calendar, Outlook and Apple Calendar can import or subscribe to
+func BuildICal(calendarName string, events []ICalEvent) []byte {
+	var b bytes.Buffer
+	now := time.Now().UTC().Format(icalTimeFormat)
+
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:-//KEC Placement Portal//Interviews//EN")
+	writeICalLine(&b, "CALSCALE:GREGORIAN")
+	writeICalLine(&b, "METHOD:PUBLISH")
+	writeICalLine(&b, "X-WR-CALNAME:"+escapeICalText(calendarName))
+	// Hint for subscribed feeds; clients that ignore it use their own refresh interval
+	writeICalLine(&b, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeICalLine(&b, "X-PUBLISHED-TTL:PT1H")
+
+	for _, e := range events {
+		writeICalLine(&b, "BEGIN:VEVENT")
+		writeICalLine(&b, "UID:"+e.UID)
+		writeICalLine(&b, "DTSTAMP:"+now)
+		if e.AllDay {
+			end := e.End
+			if end.IsZero() {
+				end = e.Start.AddDate(0, 0, 1)
+			}
+			writeICalLine(&b, "DTSTART;VALUE=DATE:"+e.Start.Format(icalDateFormat))
+			writeICalLine(&b, "DTEND;VALUE=DATE:"+end.Format(icalDateFormat))
+		} else {
+			writeICalLine(&b, "DTSTART:"+e.Start.UTC().Format(icalTimeFormat))
+			writeICalLine(&b, "DTEND:"+e.End.UTC().Format(icalTimeFormat))
+		}
+		if !e.Updated.IsZero() {
+			writeICalLine(&b, "LAST-MODIFIED:"+e.Updated.UTC().Format(icalTimeFormat))
+		}
+		writeICalLine(&b, "SUMMARY:"+escapeICalText(e.Summary))
+		if e.Description != "" {
+			writeICalLine(&b, "DESCRIPTION:"+escapeICalText(e.Description))
+		}
+		if e.Location != "" {
+			writeICalLine(&b, "LOCATION:"+escapeICalText(e.Location))
+		}
+		if e.URL != "" {
+			writeICalLine(&b, "URL:"+e.URL)
+		}
+		writeICalLine(&b, "END:VEVENT")
+	}
+
+	writeICalLine(&b, "END:VCALENDAR")
+	return b.Bytes()
+}
+
+// escapeICalText escapes TEXT values (RFC 5545 3.3.11)
+func escapeICalText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// writeICalLine writes a CRLF terminated content line, folded at 75 octets without splitting UTF-8 characters
+func writeICalLine(b *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && (line[cut]&0xC0) == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // Continuation lines start with a space
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
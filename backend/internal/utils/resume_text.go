@@ -0,0 +1,928 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Resume text extraction. Everything runs locally with the standard library: DOCX is a zip of XML,
+// and for PDF we read just enough of the file format to pull text out of the page content streams
+// (FlateDecode streams, object streams and ToUnicode maps, which covers Word, Google Docs and LaTeX
+// exports). Scanned resumes have no text layer and come back empty.
+
+var (
+	ErrUnsupportedResume = errors.New("unsupported resume format: upload a PDF or DOCX")
+	ErrEncryptedPDF      = errors.New("resume PDF is password protected")
+
+	errPDFInflateLimit = errors.New("PDF decompresses to more than the allowed size")
+)
+
+const (
+	maxResumeObjects  = 20000    // guards against pathological files
+	maxResumeInflated = 50 << 20 // decompressed bytes across all streams of one PDF (zip bombs)
+)
+
+// ExtractResumeText returns the plain text of a PDF or DOCX resume
+func ExtractResumeText(data []byte) (text string, err error) {
+	// The parsers walk untrusted offsets; a malformed file must fail this request, not the server
+	defer func() {
+		if r := recover(); r != nil {
+			text, err = "", fmt.Errorf("malformed resume: %v", r)
+		}
+	}()
+
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF")):
+		return extractPDFText(data)
+	case bytes.HasPrefix(data, []byte("PK")):
+		return extractDOCXText(data)
+	}
+	return "", ErrUnsupportedResume
+}
+
+// ---------- DOCX ----------
+
+func extractDOCXText(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", fmt.Errorf("invalid DOCX: %w", err)
+	}
+	for _, f := range zr.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+		return docxXMLText(io.LimitReader(rc, 20<<20))
+	}
+	return "", ErrUnsupportedResume
+}
+
+func docxXMLText(r io.Reader) (string, error) {
+	var sb strings.Builder
+	dec := xml.NewDecoder(r)
+	inText := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid DOCX: %w", err)
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				sb.WriteByte('\t')
+			case "br", "cr":
+				sb.WriteByte('\n')
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				sb.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				sb.Write(t)
+			}
+		}
+	}
+	return sb.String(), nil
+}
+
+// ---------- PDF objects ----------
+
+type pdfName string
+type pdfString []byte
+type pdfArray []interface{}
+type pdfDict map[pdfName]interface{}
+type pdfKeyword string
+type pdfRef struct{ num, gen int }
+
+type pdfObject struct {
+	value  interface{}
+	stream []byte // raw (still encoded) stream data, nil if not a stream
+}
+
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFSpace(b byte) bool {
+	return b == ' ' || b == '\n' || b == '\r' || b == '\t' || b == '\f' || b == 0
+}
+
+func isPDFDelim(b byte) bool {
+	return strings.IndexByte("()<>[]{}/%", b) >= 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		b := l.data[l.pos]
+		if isPDFSpace(b) {
+			l.pos++
+		} else if b == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		} else {
+			return
+		}
+	}
+}
+
+// token returns the next token: float64, pdfName, pdfString, or pdfKeyword (which includes the
+// delimiters "[", "]", "<<", ">>"). ok is false at end of input.
+func (l *pdfLexer) token() (tok interface{}, ok bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, false
+	}
+	b := l.data[l.pos]
+	switch {
+	case b == '/':
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelim(l.data[l.pos]) {
+			l.pos++
+		}
+		return pdfName(decodePDFNameEscapes(l.data[start:l.pos])), true
+	case b == '(':
+		return l.literalString(), true
+	case b == '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return pdfKeyword("<<"), true
+		}
+		return l.hexString(), true
+	case b == '>':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return pdfKeyword(">>"), true
+		}
+		l.pos++
+		return pdfKeyword(">"), true
+	case b == '[' || b == ']' || b == '{' || b == '}':
+		l.pos++
+		return pdfKeyword(string(b)), true
+	case b == ')':
+		l.pos++
+		return pdfKeyword(")"), true
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelim(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return f, true
+	}
+	return pdfKeyword(word), true
+}
+
+func decodePDFNameEscapes(b []byte) string {
+	if bytes.IndexByte(b, '#') < 0 {
+		return string(b)
+	}
+	var out []byte
+	for i := 0; i < len(b); i++ {
+		if b[i] == '#' && i+2 < len(b) {
+			if v, err := strconv.ParseUint(string(b[i+1:i+3]), 16, 8); err == nil {
+				out = append(out, byte(v))
+				i += 2
+				continue
+			}
+		}
+		out = append(out, b[i])
+	}
+	return string(out)
+}
+
+func (l *pdfLexer) literalString() pdfString {
+	l.pos++ // (
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		b := l.data[l.pos]
+		l.pos++
+		switch b {
+		case '(':
+			depth++
+			out = append(out, b)
+		case ')':
+			depth--
+			if depth == 0 {
+				return out
+			}
+			out = append(out, b)
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+		default:
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+func (l *pdfLexer) hexString() pdfString {
+	l.pos++ // <
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; !isPDFSpace(c) {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++ // >
+	return decodeHexDigits(digits)
+}
+
+func decodeHexDigits(digits []byte) []byte {
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, 0, len(digits)/2)
+	for i := 0; i+1 < len(digits); i += 2 {
+		v, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			return out
+		}
+		out = append(out, byte(v))
+	}
+	return out
+}
+
+// value parses one object: numbers, names, strings, arrays, dicts and "n g R" references.
+// Keywords other than delimiters (true, false, null, operators) are returned as pdfKeyword.
+func (l *pdfLexer) value() (interface{}, bool) {
+	tok, ok := l.token()
+	if !ok {
+		return nil, false
+	}
+	switch t := tok.(type) {
+	case float64:
+		// Look ahead for "gen R"
+		save := l.pos
+		if gen, ok := l.token(); ok {
+			if g, isNum := gen.(float64); isNum {
+				if r, ok := l.token(); ok && r == pdfKeyword("R") {
+					return pdfRef{int(t), int(g)}, true
+				}
+			}
+		}
+		l.pos = save
+		return t, true
+	case pdfKeyword:
+		switch t {
+		case "[":
+			var arr pdfArray
+			for {
+				l.skipSpace()
+				if l.pos < len(l.data) && l.data[l.pos] == ']' {
+					l.pos++
+					return arr, true
+				}
+				v, ok := l.value()
+				if !ok {
+					return arr, true
+				}
+				arr = append(arr, v)
+			}
+		case "<<":
+			dict := pdfDict{}
+			for {
+				k, ok := l.value()
+				if !ok || k == pdfKeyword(">>") {
+					return dict, true
+				}
+				name, isName := k.(pdfName)
+				if !isName {
+					continue
+				}
+				v, ok := l.value()
+				if !ok {
+					return dict, true
+				}
+				dict[name] = v
+			}
+		}
+	}
+	return tok, true
+}
+
+// ---------- PDF document ----------
+
+type pdfDoc struct {
+	objects  map[int]*pdfObject
+	fonts    map[int]*pdfFont // by font object number
+	inflated int              // decompressed bytes left before we stop decoding streams
+}
+
+var pdfObjHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+func extractPDFText(data []byte) (string, error) {
+	doc := &pdfDoc{objects: map[int]*pdfObject{}, fonts: map[int]*pdfFont{}, inflated: maxResumeInflated}
+	if err := doc.load(data); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, page := range doc.pages() {
+		if doc.inflated <= 0 {
+			break // budget spent: keep the pages we already have
+		}
+		doc.pageText(page, &sb)
+		sb.WriteString("\n\n")
+	}
+	return sb.String(), nil
+}
+
+// load indexes every "n g obj" in the file, later definitions winning (incremental updates),
+// then unpacks compressed object streams
+func (d *pdfDoc) load(data []byte) error {
+	matches := pdfObjHeader.FindAllSubmatchIndex(data, maxResumeObjects)
+	for _, m := range matches {
+		num, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		l := &pdfLexer{data: data, pos: m[1]}
+		v, ok := l.value()
+		if !ok {
+			continue
+		}
+		obj := &pdfObject{value: v}
+		if dict, isDict := v.(pdfDict); isDict {
+			save := l.pos
+			if kw, ok := l.token(); ok && kw == pdfKeyword("stream") {
+				obj.stream = rawStream(data, l.pos, dict)
+			} else {
+				l.pos = save
+			}
+		}
+		d.objects[num] = obj
+	}
+
+	for num, obj := range d.objects {
+		dict, _ := obj.value.(pdfDict)
+		if dict == nil {
+			continue
+		}
+		if dict["Encrypt"] != nil {
+			return ErrEncryptedPDF
+		}
+		if dict["Type"] == pdfName("ObjStm") {
+			d.unpackObjectStream(num, obj)
+		}
+	}
+	// Classic files keep /Encrypt in the trailer, which isn't an indexed object
+	if i := bytes.LastIndex(data, []byte("trailer")); i >= 0 && bytes.Contains(data[i:], []byte("/Encrypt")) {
+		return ErrEncryptedPDF
+	}
+	return nil
+}
+
+// rawStream slices the bytes between "stream" and "endstream"
+func rawStream(data []byte, pos int, dict pdfDict) []byte {
+	if pos < len(data) && data[pos] == '\r' {
+		pos++
+	}
+	if pos < len(data) && data[pos] == '\n' {
+		pos++
+	}
+	if n, ok := dict["Length"].(float64); ok && n >= 0 && pos+int(n) <= len(data) {
+		end := pos + int(n)
+		if bytes.Contains(data[end:min(end+20, len(data))], []byte("endstream")) {
+			return data[pos:end]
+		}
+	}
+	end := bytes.Index(data[pos:], []byte("endstream"))
+	if end < 0 {
+		return nil
+	}
+	return bytes.TrimRight(data[pos:pos+end], "\r\n")
+}
+
+func (d *pdfDoc) unpackObjectStream(num int, obj *pdfObject) {
+	dict := obj.value.(pdfDict)
+	raw, err := d.decodeStream(obj)
+	if err != nil {
+		return
+	}
+	n, _ := dict["N"].(float64)
+	first, _ := dict["First"].(float64)
+	if first < 0 || int(first) > len(raw) {
+		return
+	}
+	header := &pdfLexer{data: raw[:int(first)]}
+	type entry struct{ num, offset int }
+	var entries []entry
+	for i := 0; i < int(n); i++ {
+		a, ok1 := header.token()
+		b, ok2 := header.token()
+		an, isNum1 := a.(float64)
+		bn, isNum2 := b.(float64)
+		if !ok1 || !ok2 || !isNum1 || !isNum2 {
+			break
+		}
+		entries = append(entries, entry{int(an), int(bn)})
+	}
+	for _, e := range entries {
+		if _, exists := d.objects[e.num]; exists {
+			continue // a later plain object overrides the compressed copy
+		}
+		if e.offset < 0 || e.offset >= len(raw)-int(first) {
+			continue
+		}
+		l := &pdfLexer{data: raw, pos: int(first) + e.offset}
+		if v, ok := l.value(); ok {
+			d.objects[e.num] = &pdfObject{value: v}
+		}
+	}
+}
+
+func (d *pdfDoc) resolve(v interface{}) interface{} {
+	for i := 0; i < 10; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		obj := d.objects[ref.num]
+		if obj == nil {
+			return nil
+		}
+		v = obj.value
+	}
+	return nil
+}
+
+func (d *pdfDoc) dict(v interface{}) pdfDict {
+	dict, _ := d.resolve(v).(pdfDict)
+	return dict
+}
+
+// decodeStream applies the stream's filters. Only FlateDecode (the only one used for text in
+// practice) is supported. Every stream draws on the document's decompression budget, and once it
+// is spent nothing more is decoded.
+func (d *pdfDoc) decodeStream(obj *pdfObject) ([]byte, error) {
+	dict, _ := obj.value.(pdfDict)
+	var filters []pdfName
+	switch f := d.resolve(dict["Filter"]).(type) {
+	case pdfName:
+		filters = []pdfName{f}
+	case pdfArray:
+		for _, x := range f {
+			if n, ok := d.resolve(x).(pdfName); ok {
+				filters = append(filters, n)
+			}
+		}
+	}
+	data := obj.stream
+	for _, f := range filters {
+		if f != "FlateDecode" && f != "Fl" {
+			return nil, fmt.Errorf("unsupported PDF filter %s", f)
+		}
+		if d.inflated <= 0 {
+			return nil, errPDFInflateLimit
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		// Truncated streams are common; keep what decompressed
+		out, err := io.ReadAll(io.LimitReader(zr, int64(d.inflated)))
+		zr.Close()
+		d.inflated -= len(out)
+		if len(out) == 0 && err != nil {
+			return nil, err
+		}
+		data = out
+	}
+	return data, nil
+}
+
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages walks the page tree from the catalog, falling back to every /Page object in file order
+func (d *pdfDoc) pages() []pdfPage {
+	var pages []pdfPage
+	seen := map[int]bool{}
+	var walk func(ref interface{}, inherited pdfDict, depth int)
+	walk = func(ref interface{}, inherited pdfDict, depth int) {
+		if r, ok := ref.(pdfRef); ok {
+			if seen[r.num] {
+				return
+			}
+			seen[r.num] = true
+		}
+		node := d.dict(ref)
+		if node == nil || depth > 50 {
+			return
+		}
+		res := inherited
+		if r := d.dict(node["Resources"]); r != nil {
+			res = r
+		}
+		if kids, ok := d.resolve(node["Kids"]).(pdfArray); ok {
+			for _, k := range kids {
+				walk(k, res, depth+1)
+			}
+			return
+		}
+		if node["Type"] == pdfName("Page") || node["Contents"] != nil {
+			pages = append(pages, pdfPage{dict: node, resources: res})
+		}
+	}
+
+	nums := make([]int, 0, len(d.objects))
+	for num := range d.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+
+	for _, num := range nums {
+		if dict, ok := d.objects[num].value.(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+			walk(dict["Pages"], nil, 0)
+			break
+		}
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+	for _, num := range nums {
+		if dict, ok := d.objects[num].value.(pdfDict); ok && dict["Type"] == pdfName("Page") {
+			pages = append(pages, pdfPage{dict: dict, resources: d.dict(dict["Resources"])})
+		}
+	}
+	return pages
+}
+
+func (d *pdfDoc) pageText(page pdfPage, sb *strings.Builder) {
+	var content []byte
+	var refs []interface{}
+	switch c := page.dict["Contents"].(type) {
+	case pdfArray:
+		refs = c
+	default:
+		if arr, ok := d.resolve(c).(pdfArray); ok {
+			refs = arr
+		} else {
+			refs = []interface{}{c}
+		}
+	}
+	for _, ref := range refs {
+		r, ok := ref.(pdfRef)
+		if !ok {
+			continue
+		}
+		obj := d.objects[r.num]
+		if obj == nil || obj.stream == nil {
+			continue
+		}
+		if data, err := d.decodeStream(obj); err == nil {
+			content = append(content, data...)
+			content = append(content, '\n')
+		}
+	}
+
+	fonts := map[pdfName]*pdfFont{}
+	if fontDict := d.dict(page.resources["Font"]); fontDict != nil {
+		for name, ref := range fontDict {
+			fonts[name] = d.font(ref)
+		}
+	}
+	runContentStream(content, fonts, sb)
+}
+
+// ---------- Fonts ----------
+
+type pdfFont struct {
+	codeBytes int               // 1 for simple fonts, 2 for Type0 (CID) fonts
+	toUnicode map[uint32]string // from the ToUnicode CMap, may be empty
+}
+
+func (d *pdfDoc) font(ref interface{}) *pdfFont {
+	r, isRef := ref.(pdfRef)
+	if isRef {
+		if f, ok := d.fonts[r.num]; ok {
+			return f
+		}
+	}
+	f := &pdfFont{codeBytes: 1, toUnicode: map[uint32]string{}}
+	dict := d.dict(ref)
+	if dict != nil {
+		if dict["Subtype"] == pdfName("Type0") {
+			f.codeBytes = 2
+		}
+		if tu, ok := dict["ToUnicode"].(pdfRef); ok {
+			if obj := d.objects[tu.num]; obj != nil && obj.stream != nil {
+				if cmap, err := d.decodeStream(obj); err == nil {
+					parseToUnicode(cmap, f)
+				}
+			}
+		}
+	}
+	if isRef {
+		d.fonts[r.num] = f
+	}
+	return f
+}
+
+// parseToUnicode reads bfchar / bfrange entries of a ToUnicode CMap
+func parseToUnicode(cmap []byte, f *pdfFont) {
+	l := &pdfLexer{data: cmap}
+	var stack []interface{}
+	mode := ""
+	for {
+		tok, ok := l.value()
+		if !ok {
+			return
+		}
+		switch t := tok.(type) {
+		case pdfKeyword:
+			switch t {
+			case "begincodespacerange":
+				mode = "codespace"
+			case "beginbfchar":
+				mode = "bfchar"
+			case "beginbfrange":
+				mode = "bfrange"
+			case "endcodespacerange":
+				if len(stack) >= 1 {
+					if lo, ok := stack[0].(pdfString); ok && len(lo) > 0 {
+						f.codeBytes = len(lo)
+					}
+				}
+				mode = ""
+			case "endbfchar", "endbfrange":
+				mode = ""
+			}
+			stack = stack[:0]
+			continue
+		}
+		stack = append(stack, tok)
+		switch mode {
+		case "bfchar":
+			if len(stack) == 2 {
+				src, ok1 := stack[0].(pdfString)
+				dst, ok2 := stack[1].(pdfString)
+				if ok1 && ok2 {
+					f.toUnicode[codeOf(src)] = utf16BEString(dst)
+				}
+				stack = stack[:0]
+			}
+		case "bfrange":
+			if len(stack) == 3 {
+				lo, ok1 := stack[0].(pdfString)
+				hi, ok2 := stack[1].(pdfString)
+				if ok1 && ok2 {
+					start, end := codeOf(lo), codeOf(hi)
+					if end >= start && end-start < 65536 {
+						switch dst := stack[2].(type) {
+						case pdfString:
+							base := []rune(utf16BEString(dst))
+							if len(base) > 0 {
+								for c := start; c <= end; c++ {
+									r := append([]rune{}, base...)
+									r[len(r)-1] += rune(c - start)
+									f.toUnicode[c] = string(r)
+								}
+							}
+						case pdfArray:
+							for i, x := range dst {
+								if s, ok := x.(pdfString); ok {
+									f.toUnicode[start+uint32(i)] = utf16BEString(s)
+								}
+							}
+						}
+					}
+				}
+				stack = stack[:0]
+			}
+		default:
+			if len(stack) > 8 {
+				stack = stack[:0]
+			}
+		}
+	}
+}
+
+func codeOf(b []byte) uint32 {
+	var c uint32
+	for _, x := range b {
+		c = c<<8 | uint32(x)
+	}
+	return c
+}
+
+func utf16BEString(b []byte) string {
+	if len(b)%2 == 1 {
+		return string(b)
+	}
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(u))
+}
+
+func (f *pdfFont) decode(s []byte) string {
+	if f == nil {
+		f = &pdfFont{codeBytes: 1}
+	}
+	var sb strings.Builder
+	step := f.codeBytes
+	if step < 1 {
+		step = 1
+	}
+	for i := 0; i+step <= len(s); i += step {
+		code := codeOf(s[i : i+step])
+		if u, ok := f.toUnicode[code]; ok {
+			sb.WriteString(u)
+		} else if step == 1 {
+			sb.WriteRune(winAnsiRune(s[i]))
+		}
+	}
+	return sb.String()
+}
+
+// winAnsiRune approximates WinAnsiEncoding for simple fonts without a ToUnicode map
+func winAnsiRune(b byte) rune {
+	switch b {
+	case 0x91, 0x92:
+		return '\''
+	case 0x93, 0x94:
+		return '"'
+	case 0x95:
+		return '•'
+	case 0x96, 0x97:
+		return '-'
+	}
+	if b < 0x20 && b != '\t' && b != '\n' {
+		return ' '
+	}
+	return rune(b)
+}
+
+// ---------- Content streams ----------
+
+func runContentStream(content []byte, fonts map[pdfName]*pdfFont, sb *strings.Builder) {
+	l := &pdfLexer{data: content}
+	var operands []interface{}
+	var font *pdfFont
+	lastY, haveY := 0.0, false
+
+	write := func(s string) {
+		sb.WriteString(s)
+	}
+	newline := func() {
+		str := sb.String()
+		if len(str) > 0 && str[len(str)-1] != '\n' {
+			sb.WriteByte('\n')
+		}
+	}
+	space := func() {
+		str := sb.String()
+		if len(str) > 0 && str[len(str)-1] != ' ' && str[len(str)-1] != '\n' {
+			sb.WriteByte(' ')
+		}
+	}
+	num := func(i int) float64 {
+		if i < len(operands) {
+			if f, ok := operands[i].(float64); ok {
+				return f
+			}
+		}
+		return 0
+	}
+	moveTo := func(y float64, dx float64) {
+		if haveY && (y-lastY > 1 || lastY-y > 1) {
+			newline()
+		} else if dx > 0 {
+			space()
+		}
+		lastY, haveY = y, true
+	}
+
+	for {
+		v, ok := l.value()
+		if !ok {
+			return
+		}
+		op, isOp := v.(pdfKeyword)
+		if !isOp || op == "true" || op == "false" || op == "null" {
+			operands = append(operands, v)
+			continue
+		}
+		switch op {
+		case "Tf":
+			if len(operands) >= 1 {
+				if name, ok := operands[0].(pdfName); ok {
+					font = fonts[name]
+				}
+			}
+		case "Tj":
+			if len(operands) >= 1 {
+				if s, ok := operands[0].(pdfString); ok {
+					write(font.decode(s))
+				}
+			}
+		case "'", "\"":
+			newline()
+			if len(operands) >= 1 {
+				if s, ok := operands[len(operands)-1].(pdfString); ok {
+					write(font.decode(s))
+				}
+			}
+		case "TJ":
+			if len(operands) >= 1 {
+				if arr, ok := operands[0].(pdfArray); ok {
+					for _, x := range arr {
+						switch t := x.(type) {
+						case pdfString:
+							write(font.decode(t))
+						case float64:
+							if t < -180 { // kerning wider than a third of a space is a word gap
+								space()
+							}
+						}
+					}
+				}
+			}
+		case "Td", "TD":
+			dy := num(1)
+			if dy != 0 {
+				moveTo(lastY+dy, 0)
+			} else if num(0) > 0 {
+				space()
+			}
+		case "Tm":
+			moveTo(num(5), 1)
+		case "T*":
+			newline()
+		case "ET":
+			space()
+		case "BI":
+			// Inline image: skip binary data up to EI
+			if i := bytes.Index(content[l.pos:], []byte("EI")); i >= 0 {
+				l.pos += i + 2
+			} else {
+				return
+			}
+		}
+		operands = operands[:0]
+	}
+}
@@ -0,0 +1,293 @@
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// The PDF fixtures are built here rather than checked in: each one reproduces the structure a
+// given producer writes (fonts, encodings, object streams, text operators) with known text.
+
+// buildPDF numbers the objects from 1 and appends a classic trailer when trailer is not empty
+func buildPDF(version, trailer string, objects ...string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%%PDF-%s\n%%\xe2\xe3\xcf\xd3\n", version)
+	for i, obj := range objects {
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	if trailer != "" {
+		fmt.Fprintf(&b, "xref\n0 %d\ntrailer\n%s\nstartxref\n0\n%%%%EOF\n", len(objects)+1, trailer)
+	}
+	return b.Bytes()
+}
+
+func deflate(data []byte) []byte {
+	var b bytes.Buffer
+	zw, _ := zlib.NewWriterLevel(&b, zlib.BestSpeed)
+	zw.Write(data)
+	zw.Close()
+	return b.Bytes()
+}
+
+func flateStream(dict string, data []byte) string {
+	z := deflate(data)
+	return fmt.Sprintf("<< %s /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream", dict, len(z), z)
+}
+
+// objectStream packs objects (numbered from first) the way pdfTeX and Word do for PDF 1.5
+func objectStream(first int, objects ...string) string {
+	var header, body strings.Builder
+	for i, obj := range objects {
+		fmt.Fprintf(&header, "%d %d ", first+i, body.Len())
+		body.WriteString(obj)
+		body.WriteByte('\n')
+	}
+	return flateStream(fmt.Sprintf("/Type /ObjStm /N %d /First %d", len(objects), header.Len()),
+		[]byte(header.String()+body.String()))
+}
+
+func toUnicodeCMap(entries string) []byte {
+	return []byte("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" + entries +
+		"\nendcmap\nCMapName currentdict /CMap defineresource pop\nend\nend")
+}
+
+// Word: WinAnsi TrueType subset with a ToUnicode map, one Tm per line, TJ arrays with small kerning
+func wordPDF() []byte {
+	content := "BT\n/F1 11.04 Tf\n1 0 0 1 72.024 769.9 Tm\n[(P)4(riya)-3( )4(Sh)3(a)-2(rma)] TJ\nET\n" +
+		"BT\n1 0 0 1 72.024 755.86 Tm\n[(priya.sharma@gmail.com )-2(| )4(+91 98765 43210)] TJ\nET\n" +
+		"BT\n1 0 0 1 72.024 727.78 Tm\n(\x95 B.E. Computer Science, CGPA: 8.72) Tj\nET\n"
+	cmap := toUnicodeCMap("1 begincodespacerange\n<00> <FF>\nendcodespacerange\n" +
+		"1 beginbfrange\n<20> <7E> <0020>\nendbfrange\n1 beginbfchar\n<95> <2022>\nendbfchar")
+	return buildPDF("1.7", "<< /Size 7 /Root 1 0 R >>",
+		"<< /Type /Catalog /Pages 2 0 R /Lang (en-IN) >>",
+		"<< /Type /Pages /Count 1 /Kids [ 3 0 R ] >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 5 0 R >> /ProcSet [/PDF /Text] >> "+
+			"/MediaBox [ 0 0 595.32 841.92 ] /Contents 4 0 R /Group << /Type /Group /S /Transparency /CS /DeviceRGB >> >>",
+		flateStream("", []byte(content)),
+		"<< /Type /Font /Subtype /TrueType /Name /F1 /BaseFont /BCDEEE+Calibri /Encoding /WinAnsiEncoding "+
+			"/FirstChar 32 /LastChar 149 /ToUnicode 6 0 R >>",
+		flateStream("", cmap),
+	)
+}
+
+// googleGlyphs maps text to the 2-byte glyph IDs of an Identity-H font (glyph = rune - 29, and
+// glyph 0x0100 is the "fi" ligature)
+func googleGlyphs(s string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for i := 0; i < len(s); i++ {
+		if strings.HasPrefix(s[i:], "fi") {
+			b.WriteString("0100")
+			i++
+			continue
+		}
+		fmt.Fprintf(&b, "%04X", int(s[i])-29)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// Google Docs: Type0 Identity-H font, hex glyph strings, ToUnicode bfrange (plain and array form),
+// lines positioned with Td
+func googleDocsPDF() []byte {
+	content := "q\n.75 0 0 .75 0 0 cm\nBT\n/F4 14.666667 Tf\n1 0 0 -1 0 .47 Tm\n96 -100 Td " + googleGlyphs("Arun Kumar") + " Tj\n" +
+		"0 -20 Td " + googleGlyphs("github.com/arunk | linkedin.com/in/arun-kumar") + " Tj\n" +
+		"0 -20 Td " + googleGlyphs("AWS Certified Cloud Practitioner") + " Tj\nET\nQ\n"
+	cmap := toUnicodeCMap("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n" +
+		"2 beginbfrange\n<0003> <0061> <0020>\n<0100> <0100> [<00660069>]\nendbfrange")
+	return buildPDF("1.4", "<< /Root 1 0 R /Info 8 0 R /Size 9 >>",
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F4 5 0 R >> >> /MediaBox [0 0 612 792] /Contents [4 0 R] >>",
+		flateStream("", []byte(content)),
+		"<< /Type /Font /Subtype /Type0 /BaseFont /AAAAAA+Arial-BoldMT /Encoding /Identity-H "+
+			"/DescendantFonts [6 0 R] /ToUnicode 7 0 R >>",
+		"<< /Type /Font /Subtype /CIDFontType2 /BaseFont /AAAAAA+Arial-BoldMT "+
+			"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /DW 0 >>",
+		flateStream("", cmap),
+		"<< /Producer (Skia/PDF m121 Google Docs Renderer) >>",
+	)
+}
+
+// LaTeX (pdfTeX, PDF 1.5): everything but the streams lives in an object stream, the xref is a
+// stream too (no trailer keyword), Type1 font without ToUnicode, word gaps as TJ kerning
+func latexPDF() []byte {
+	content := "BT\n/F8 11.955 Tf 148.712 707.125 Td [(Educa)28(tion)]TJ\n" +
+		"/F8 9.963 Tf -14.944 -21.918 Td [(M.T)83(ec)28(h)-333(in)-333(Data)-333(Science,)-333(IIT)-333(Madras)]TJ\n" +
+		"0 -11.955 Td [(CGP)83(A:)-333(9.1)-333(/)-333(10)-333(\\(2024\\))]TJ\nET\n"
+	return buildPDF("1.5", "",
+		flateStream("/Length1 1626 /Length2 8574 /Length3 0", []byte("fake font program")),
+		flateStream("", []byte(content)),
+		objectStream(5,
+			"<< /Type /Page /Contents 2 0 R /Resources 6 0 R /MediaBox [0 0 612 792] /Parent 8 0 R >>",
+			"<< /Font << /F8 7 0 R >> /ProcSet [ /PDF /Text ] >>",
+			"<< /Type /Font /Subtype /Type1 /BaseFont /KXTZDO+CMR10 /FirstChar 40 /LastChar 121 /FontDescriptor 1 0 R >>",
+			"<< /Type /Pages /Count 1 /Kids [5 0 R] >>",
+			"<< /Type /Catalog /Pages 8 0 R >>",
+		),
+		flateStream("/Type /XRef /Index [0 10] /Size 10 /W [1 2 1] /Root 9 0 R /ID [<AB> <AB>]", []byte{1, 0, 0, 0}),
+	)
+}
+
+func docxFile(t *testing.T, documentXML string) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	files := []struct{ name, body string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"/>`},
+		{"word/document.xml", documentXML},
+	}
+	for _, f := range files {
+		if f.body == "" {
+			continue
+		}
+		w, err := zw.Create(f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(f.body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+const testDocumentXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Meena</w:t></w:r><w:r><w:t xml:space="preserve"> Raj</w:t></w:r></w:p>
+<w:p><w:r><w:t>HSC</w:t></w:r><w:r><w:tab/><w:t>92.4%</w:t></w:r><w:r><w:br/><w:t>State Board, 2020</w:t></w:r></w:p>
+</w:body></w:document>`
+
+// zipBombPDF has three pages whose content streams each inflate to 30 MiB, more than the document
+// budget allows together; only page one has text
+func zipBombPDF() []byte {
+	padding := bytes.Repeat([]byte(" "), 30<<20)
+	first := append([]byte("BT /F1 12 Tf 72 720 Td (Priya Sharma) Tj ET\n"), padding...)
+	pages, kids := []string{}, []string{}
+	for i := 0; i < 3; i++ {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 3+2*i))
+		data := padding
+		if i == 0 {
+			data = first
+		}
+		pages = append(pages,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /Contents %d 0 R >>", 4+2*i),
+			flateStream("", data))
+	}
+	objects := append([]string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Count 3 /Kids [%s] >>", strings.Join(kids, " ")),
+	}, pages...)
+	return buildPDF("1.7", "<< /Root 1 0 R >>", objects...)
+}
+
+func TestExtractResumeText(t *testing.T) {
+	word := wordPDF()
+	wordCut := word[:bytes.Index(word, []byte("6 0 obj"))+60]
+	content := bytes.Index(word, []byte("4 0 obj"))
+	contentCut := word[:content+(bytes.Index(word[content:], []byte("endstream")))/2]
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    []string // substrings of the whitespace-normalised text
+		wantErr error
+	}{
+		{name: "word", data: word, want: []string{
+			"Priya Sharma\npriya.sharma@gmail.com | +91 98765 43210\n• B.E. Computer Science, CGPA: 8.72"}},
+		{name: "google docs", data: googleDocsPDF(), want: []string{
+			"Arun Kumar\ngithub.com/arunk | linkedin.com/in/arun-kumar\nAWS Certified Cloud Practitioner"}},
+		{name: "latex", data: latexPDF(), want: []string{
+			"Education\nM.Tech in Data Science, IIT Madras\nCGPA: 9.1 / 10 (2024)"}},
+		{name: "docx", data: docxFile(t, testDocumentXML), want: []string{"Meena Raj\nHSC 92.4%\nState Board, 2020"}},
+		{name: "truncated in the ToUnicode map", data: wordCut, want: []string{"Priya Sharma", "• B.E."}},
+		{name: "truncated in the content stream", data: contentCut},
+		{name: "truncated after the header", data: []byte("%PDF-1.7\n1 0 obj\n<< /Type /Catalog /Pages 2 0")},
+		{name: "encrypted, classic trailer", wantErr: ErrEncryptedPDF, data: buildPDF("1.6",
+			"<< /Root 1 0 R /Encrypt 3 0 R /ID [<01> <01>] >>",
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Count 0 /Kids [] >>",
+			"<< /Filter /Standard /V 2 /R 3 /Length 128 /P -1340 /O <00> /U <00> >>")},
+		{name: "encrypted, xref stream", wantErr: ErrEncryptedPDF, data: buildPDF("1.7", "",
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Filter /Standard /V 4 /R 4 /Length 128 /P -1340 /O <00> /U <00> >>",
+			flateStream("/Type /XRef /Size 4 /W [1 2 1] /Root 1 0 R /Encrypt 2 0 R", []byte{1, 0, 0, 0}))},
+		{name: "oversized inflation", data: zipBombPDF(), want: []string{"Priya Sharma"}},
+		{name: "docx without a document", data: docxFile(t, ""), wantErr: ErrUnsupportedResume},
+		{name: "plain text", data: []byte("Priya Sharma\nB.E. CSE"), wantErr: ErrUnsupportedResume},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := ExtractResumeText(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			got := normaliseText(text)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("text %q does not contain %q", got, w)
+				}
+			}
+		})
+	}
+}
+
+// normaliseText collapses the spacing within each line and drops blank lines
+func normaliseText(s string) string {
+	var lines []string
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.Join(strings.Fields(l), " "); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestExtractResumeTextInflateBudget(t *testing.T) {
+	doc := &pdfDoc{objects: map[int]*pdfObject{}, fonts: map[int]*pdfFont{}, inflated: maxResumeInflated}
+	if err := doc.load(zipBombPDF()); err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	for _, page := range doc.pages() {
+		doc.pageText(page, &sb)
+	}
+	if doc.inflated > 0 {
+		t.Fatalf("budget left = %d, want it spent", doc.inflated)
+	}
+	if _, err := doc.decodeStream(doc.objects[4]); !errors.Is(err, errPDFInflateLimit) {
+		t.Fatalf("decode after the budget is spent: err = %v, want %v", err, errPDFInflateLimit)
+	}
+}
+
+func TestParseToUnicode(t *testing.T) {
+	tests := []struct {
+		name  string
+		cmap  string
+		codes []byte
+		want  string
+	}{
+		{"bfchar", "1 begincodespacerange <00> <FF> endcodespacerange 2 beginbfchar <01> <0048> <02> <0069> endbfchar", []byte{1, 2}, "Hi"},
+		{"bfrange", "1 begincodespacerange <0000> <FFFF> endcodespacerange 1 beginbfrange <0024> <003D> <0041> endbfrange", []byte{0, 0x2b, 0, 0x2c}, "HI"},
+		{"bfrange array", "1 begincodespacerange <0000> <FFFF> endcodespacerange 1 beginbfrange <0005> <0006> [<00660066> <0041>] endbfrange", []byte{0, 5, 0, 6}, "ffA"},
+		{"surrogate pair", "1 beginbfchar <0007> <D835DC00> endbfchar", []byte{0, 7}, string(utf16.DecodeRune(0xD835, 0xDC00))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &pdfFont{codeBytes: 2, toUnicode: map[uint32]string{}}
+			parseToUnicode([]byte(tt.cmap), f)
+			if got := f.decode(tt.codes); got != tt.want {
+				t.Errorf("decode = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http" // [NEW]
 	"net/url"
@@ -349,3 +350,39 @@ func GetPresignedURL(objectKey string, expiryMinutes int) (string, error) {
 
 	return presignResult.URL, nil
 }
+
+// DownloadFromS3 reads an object into memory (used for email attachments).
+// Objects larger than maxBytes are rejected; returns the data and its content type.
+func DownloadFromS3(path string, maxBytes int64) ([]byte, string, error) {
+	client, err := getS3Client()
+	if err != nil {
+		return nil, "", err
+	}
+
+	out, err := client.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(os.Getenv("MINIO_BUCKET")),
+		Key:    aws.String(path),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download from S3 storage: %w", err)
+	}
+	defer out.Body.Close()
+
+	if out.ContentLength != nil && *out.ContentLength > maxBytes {
+		return nil, "", fmt.Errorf("object %s is too large (%d bytes)", path, *out.ContentLength)
+	}
+
+	buf := new(bytes.Buffer)
+	if _, err := buf.ReadFrom(io.LimitReader(out.Body, maxBytes+1)); err != nil {
+		return nil, "", fmt.Errorf("failed to read object: %w", err)
+	}
+	if int64(buf.Len()) > maxBytes {
+		return nil, "", fmt.Errorf("object %s is too large", path)
+	}
+
+	contentType := aws.ToString(out.ContentType)
+	if contentType == "" {
+		contentType = http.DetectContentType(buf.Bytes())
+	}
+	return buf.Bytes(), contentType, nil
+}
@@ -0,0 +1,111 @@
+package worker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"@yearly",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1-x * * * *",
+		"*/x * * * *",
+	}
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := parseCron(expr); err == nil {
+				t.Errorf("parseCron(%q) succeeded, want an error", expr)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	at := func(s string) time.Time {
+		t.Helper()
+		v, err := time.Parse("2006-01-02 15:04", s)
+		if err != nil {
+			if v, err = time.Parse("2006-01-02 15:04:05", s); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return v
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from string
+		want string // "" when the schedule never fires
+	}{
+		// Shortcuts
+		{"hourly", "@hourly", "2025-03-10 14:00", "2025-03-10 15:00"},
+		{"daily", "@daily", "2025-03-10 14:30", "2025-03-11 00:00"},
+		{"midnight", "@midnight", "2025-12-31 23:59", "2026-01-01 00:00"},
+		{"weekly", "@weekly", "2025-03-10 14:30", "2025-03-16 00:00"}, // Monday -> Sunday
+		{"monthly", "@monthly", "2025-03-10 14:30", "2025-04-01 00:00"},
+		{"shortcut with spaces", "  @hourly ", "2025-03-10 14:59", "2025-03-10 15:00"},
+
+		// Every minute, strictly after from
+		{"every minute", "* * * * *", "2025-03-10 14:00", "2025-03-10 14:01"},
+		{"seconds are dropped", "* * * * *", "2025-03-10 14:00:59", "2025-03-10 14:01"},
+
+		// Lists, ranges and steps
+		{"list", "0,30 * * * *", "2025-03-10 14:10", "2025-03-10 14:30"},
+		{"range", "0 9-17 * * *", "2025-03-10 17:00", "2025-03-11 09:00"},
+		{"step", "*/15 * * * *", "2025-03-10 14:16", "2025-03-10 14:30"},
+		{"step wraps the hour", "*/15 * * * *", "2025-03-10 14:45", "2025-03-10 15:00"},
+		{"range with step", "0-30/10 * * * *", "2025-03-10 14:25", "2025-03-10 14:30"},
+		{"range with step, past the range", "0-30/10 * * * *", "2025-03-10 14:31", "2025-03-10 15:00"},
+		{"start with step", "5/20 * * * *", "2025-03-10 14:26", "2025-03-10 14:45"},
+		{"list of ranges", "0 8-9,18-19 * * *", "2025-03-10 10:00", "2025-03-10 18:00"},
+
+		// Day of month and month rollover
+		{"day of month", "0 3 15 * *", "2025-03-15 03:00", "2025-04-15 03:00"},
+		{"31st skips short months", "0 0 31 * *", "2025-04-01 00:00", "2025-05-31 00:00"},
+		{"29 February", "0 0 29 2 *", "2025-03-01 00:00", "2028-02-29 00:00"},
+		{"year rollover", "30 3 * * *", "2025-12-31 03:30", "2026-01-01 03:30"},
+		{"month range", "0 0 1 6-8 *", "2025-08-01 00:00", "2026-06-01 00:00"},
+		{"never", "0 0 31 2 *", "2025-01-01 00:00", ""},
+
+		// Day of week
+		{"weekdays", "0 9 * * 1-5", "2025-03-14 09:00", "2025-03-17 09:00"}, // Friday -> Monday
+		{"Sunday as 0", "0 0 * * 0", "2025-03-10 00:00", "2025-03-16 00:00"},
+		{"Sunday as 7", "0 0 * * 7", "2025-03-10 00:00", "2025-03-16 00:00"},
+		{"Saturday into next month", "0 12 * * 6", "2025-05-31 12:00", "2025-06-07 12:00"},
+
+		// Both day fields restricted: either may match
+		{"day of month or day of week", "0 0 13 * 5", "2025-06-01 00:00", "2025-06-06 00:00"},         // Friday 6th before the 13th
+		{"day of month before day of week", "0 0 1 * 1", "2025-05-27 00:00", "2025-06-01 00:00"},      // Sunday 1st before Monday 2nd
+		{"day of week with a star day of month", "0 0 * 6 1", "2025-05-27 00:00", "2025-06-02 00:00"}, // June's first Monday
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("parseCron(%q): %v", tt.expr, err)
+			}
+			got := s.Next(at(tt.from))
+			if tt.want == "" {
+				if !got.IsZero() {
+					t.Errorf("Next(%s) = %s, want never", tt.from, got)
+				}
+				return
+			}
+			if want := at(tt.want); !got.Equal(want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got.Format("2006-01-02 15:04 Mon"), want.Format("2006-01-02 15:04 Mon"))
+			}
+		})
+	}
+}
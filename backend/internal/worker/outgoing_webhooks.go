@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/services"
+)
+
+// outgoingWebhookMu keeps the scheduler and event emitters from running overlapping delivery loops
+var outgoingWebhookMu sync.Mutex
+
+// DeliverDueOutgoingWebhooks sends every pending delivery that is due.
+// Called right after an event is emitted for fast first delivery; retries go through the scheduled job.
+func DeliverDueOutgoingWebhooks() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	if _, err := deliverOutgoingWebhooks(ctx); err != nil {
+		log.Printf("Outgoing Webhooks: Failed to claim deliveries: %v\n", err)
+	}
+}
+
+func deliverOutgoingWebhooks(ctx context.Context) (string, error) {
+	outgoingWebhookMu.Lock()
+	defer outgoingWebhookMu.Unlock()
+
+	repo := repository.NewOutgoingWebhookRepository(database.DB)
+
+	sent, failed := 0, 0
+	for {
+		deliveries, err := repo.ClaimDueDeliveries(ctx, 50)
+		if err != nil {
+			return "", err
+		}
+		if len(deliveries) == 0 {
+			return fmt.Sprintf("%d delivered, %d failed", sent, failed), nil
+		}
+
+		for _, d := range deliveries {
+			code, err := services.DeliverWebhook(d)
+			attempts := d.Attempts + 1
+
+			var next *time.Time
+			errMsg := ""
+			if err != nil {
+				failed++
+				errMsg = err.Error()
+				if at, ok := services.NextWebhookAttempt(attempts); ok {
+					next = &at
+				} else {
+					log.Printf("Outgoing Webhooks: Delivery %d (%s) dead after %d attempts: %v\n", d.ID, d.EventType, attempts, err)
+				}
+			} else {
+				sent++
+			}
+
+			if err := repo.RecordAttempt(ctx, d.ID, code, errMsg, errMsg == "", next); err != nil {
+				log.Printf("Outgoing Webhooks: Failed to record attempt for delivery %d: %v\n", d.ID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/services"
+)
+
+// reminderWindows: a drive gets the 24h reminder while its deadline is 2-24h away and the 2h
+// reminder in the last 2 hours, so a drive created close to its deadline only gets the 2h one.
+var reminderWindows = []struct {
+	Type     string
+	From, To time.Duration
+	TimeLeft string
+}{
+	{models.ReminderType24h, 2 * time.Hour, 24 * time.Hour, "24 hours"},
+	{models.ReminderType2h, 0, 2 * time.Hour, "2 hours"},
+}
+
+// sendDeadlineReminders nudges eligible students who haven't opted in or out of drives closing soon
+func sendDeadlineReminders(ctx context.Context) (string, error) {
+	repo := repository.NewReminderRepository(database.DB)
+	now := time.Now()
+	sent := 0
+
+	for _, w := range reminderWindows {
+		targets, err := repo.GetPendingReminderTargets(ctx, w.Type, now.Add(w.From), now.Add(w.To))
+		if err != nil {
+			return "", fmt.Errorf("fetching %s reminder targets: %w", w.Type, err)
+		}
+
+		// Targets are ordered by drive, send one batch per drive
+		for start := 0; start < len(targets); {
+			end := start
+			for end < len(targets) && targets[end].DriveID == targets[start].DriveID {
+				end++
+			}
+			sent += sendDriveReminders(ctx, repo, w.Type, w.TimeLeft, targets[start:end])
+			start = end
+		}
+	}
+	return fmt.Sprintf("sent %d reminders", sent), nil
+}
+
+// sendDriveReminders sends one drive's reminders and returns how many students were reminded
+func sendDriveReminders(ctx context.Context, repo *repository.ReminderRepository, reminderType, timeLeft string, targets []models.ReminderTarget) int {
+	driveID := targets[0].DriveID
+	drive, err := repository.NewDriveRepository(database.DB).GetDriveByID(ctx, driveID)
+	if err != nil {
+		log.Printf("Reminder: Drive %d not found: %v\n", driveID, err)
+		return 0
+	}
+
+	claimedCount := 0
+	var tokens, numbers []string
+	var emails []services.BulkEmailRecipient
+
+	// 1. Claim each reminder first so it is never sent twice, then bucket by channel
+	for _, t := range targets {
+		channels := []string{}
+		if t.Preferences.PushEnabled && t.FCMToken != "" {
+			channels = append(channels, "push")
+		}
+		if t.Preferences.EmailEnabled && t.Email != "" {
+			channels = append(channels, "email")
+		}
+		if t.Preferences.WhatsAppEnabled && t.MobileNumber != "" && t.MobileNumber != "NA" {
+			channels = append(channels, "whatsapp")
+		}
+
+		claimed, err := repo.ClaimReminder(ctx, driveID, t.StudentID, reminderType, channels)
+		if err != nil {
+			log.Printf("Reminder: Failed to record %s reminder for student %d: %v\n", reminderType, t.StudentID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		claimedCount++
+
+		for _, ch := range channels {
+			switch ch {
+			case "push":
+				tokens = append(tokens, t.FCMToken)
+			case "email":
+				emails = append(emails, services.BulkEmailRecipient{
+					Email: t.Email,
+					Data:  map[string]interface{}{"Name": t.FullName},
+				})
+			case "whatsapp":
+				numbers = append(numbers, t.MobileNumber)
+			}
+		}
+	}
+
+	// 2. Push
+	if len(tokens) > 0 {
+		ns, err := services.NewNotificationService("firebase-service-account.json")
+		if err != nil {
+			log.Printf("Notification Error: Failed to init service: %v\n", err)
+		} else {
+			title := "Deadline in " + timeLeft
+			body := drive.CompanyName + " (" + drive.JobRole + ") registration closes soon. Opt in or out now!"
+			data := map[string]string{
+				"drive_id": strconv.FormatInt(drive.ID, 10),
+				"type":     "deadline_reminder",
+			}
+			if _, err := ns.SendMulticastNotification(ctx, tokens, title, body, data); err != nil {
+				log.Printf("Notification Error: Reminder push failed: %v\n", err)
+			}
+		}
+	}
+
+	// 3. Email
+	if len(emails) > 0 {
+		shared := map[string]interface{}{
+			"CompanyName": drive.CompanyName,
+			"JobRole":     drive.JobRole,
+			"CTC":         drive.CtcDisplay,
+			"Deadline":    drive.DeadlineDate.Format("02 Jan 2006, 03:04 PM"),
+			"TimeLeft":    timeLeft,
+		}
+		if drive.DriveDate.Valid {
+			shared["DriveDate"] = drive.DriveDate.Time.Format("02 Jan 2006")
+		}
+		_, failures := services.NewEmailService().SendBulk(services.EmailTemplateDeadlineReminder, shared, emails, nil)
+		for email, err := range failures {
+			log.Printf("Email Error: Reminder to %s failed: %v\n", email, err)
+		}
+	}
+
+	// 4. WhatsApp (deadline_reminder template from the registry)
+	if len(numbers) > 0 {
+		if tpl := ResolveWhatsAppTemplate(ctx, models.WhatsAppEventDeadlineReminder); tpl != nil {
+			_, results := services.NewWhatsAppService().BroadcastDriveTemplate(numbers, *tpl, *drive)
+			messages := services.BroadcastMessages(tpl.Event, tpl.TemplateName, &driveID, results)
+			if err := repository.NewWhatsAppMessageRepository(database.DB).RecordMessages(ctx, messages); err != nil {
+				log.Printf("WhatsApp Error: Failed to record reminder deliveries: %v\n", err)
+			}
+		}
+	}
+
+	log.Printf("Reminder: Sent %s reminders for drive %d (push %d, email %d, whatsapp %d).\n",
+		reminderType, driveID, len(tokens), len(emails), len(numbers))
+	return claimedCount
+}
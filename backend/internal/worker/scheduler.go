@@ -2,64 +2,425 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/middleware"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
 	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// StartScheduler initializes background tasks
-func StartScheduler() {
-	// Create a Ticker that ticks every 1 minute
-	ticker := time.NewTicker(5 * time.Minute)
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobRunning  = errors.New("job is already running")
+
+	errSlotClaimed = errors.New("scheduled run already claimed by another instance")
+)
+
+// Job is a named background task run on a cron schedule.
+// Run returns a short human readable result (e.g. "closed 3 drives") that is stored in the run history.
+type Job struct {
+	Name        string
+	Description string
+	Schedule    string        // Cron expression, overridable with JOB_SCHEDULE_<NAME> ("off" disables automatic runs)
+	Timeout     time.Duration // The job's context is cancelled after this long
+	Run         func(ctx context.Context) (string, error)
+}
+
+type scheduledJob struct {
+	Job
+	schedule *cronSchedule // nil when automatic runs are disabled
+	running  bool
+}
+
+// Scheduler runs registered jobs on their schedules.
+// Every run takes a Postgres advisory lock first, so with several replicas each job runs on only one of them
+// at a time. Scheduled runs also claim their cron slot in job_runs, so a replica that wakes up after another
+// has already finished the slot doesn't run it again.
+type Scheduler struct {
+	mu       sync.Mutex
+	jobs     map[string]*scheduledJob
+	instance string
+
+	ctx     context.Context // Parent of every run, cancelled if Stop runs out of time
+	cancel  context.CancelFunc
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	started bool
+}
+
+// DefaultScheduler holds the portal's jobs, see StartScheduler
+var DefaultScheduler = NewScheduler()
+
+func NewScheduler() *Scheduler {
+	host, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		jobs:     make(map[string]*scheduledJob),
+		instance: fmt.Sprintf("%s:%d", host, os.Getpid()),
+		ctx:      ctx,
+		cancel:   cancel,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Register adds a job. Must be called before Start.
+func (s *Scheduler) Register(job Job) error {
+	expr := job.Schedule
+	if v := os.Getenv("JOB_SCHEDULE_" + strings.ToUpper(job.Name)); v != "" {
+		expr = v
+	}
+	job.Schedule = expr
+
+	var schedule *cronSchedule
+	if expr != "off" {
+		var err error
+		if schedule, err = parseCron(expr); err != nil {
+			return fmt.Errorf("job %s: %w", job.Name, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("job %s: already registered", job.Name)
+	}
+	s.jobs[job.Name] = &scheduledJob{Job: job, schedule: schedule}
+	return nil
+}
+
+// Start launches one loop per scheduled job
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+
+	for _, j := range s.jobs {
+		if j.schedule == nil {
+			continue
+		}
+		s.wg.Add(1)
+		go s.loop(j)
+	}
+}
+
+// Stop stops scheduling new runs and waits for running jobs to finish.
+// If ctx expires first, running jobs have their contexts cancelled and ctx's error is returned.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	s.mu.Unlock()
 
-	// Run in a separate Goroutine so it doesn't block your API
+	done := make(chan struct{})
 	go func() {
-		for range ticker.C {
-			// Every minute, this code runs
-			closeExpiredDrives()
-			cleanupOTPs()
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop(j *scheduledJob) {
+	defer s.wg.Done()
+
+	for {
+		next := j.schedule.Next(time.Now())
+		if next.IsZero() {
+			log.Printf("Scheduler: Job %s never fires (%s), not scheduling it.\n", j.Name, j.Schedule)
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-s.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
 		}
+
+		run, conn, err := s.begin(j, models.JobTriggerSchedule, &next)
+		if err != nil {
+			// Another instance (or a manual trigger) has it, that's the point of the lock and the slot
+			if !errors.Is(err, ErrJobRunning) && !errors.Is(err, errSlotClaimed) {
+				log.Printf("Scheduler: Failed to start job %s: %v\n", j.Name, err)
+			}
+			continue
+		}
+		s.execute(j, run, conn)
+	}
+}
+
+// Trigger starts a job immediately in the background and returns its run record
+func (s *Scheduler) Trigger(name string) (*models.JobRun, error) {
+	s.mu.Lock()
+	j, ok := s.jobs[name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	run, conn, err := s.begin(j, models.JobTriggerManual, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(j, run, conn)
 	}()
+	return run, nil
+}
+
+// begin marks the job running locally, takes the cluster-wide lock and records the run.
+// slot is the cron time a scheduled run is for, nil for manual runs.
+func (s *Scheduler) begin(j *scheduledJob, trigger string, slot *time.Time) (*models.JobRun, *pgxpool.Conn, error) {
+	s.mu.Lock()
+	if j.running {
+		s.mu.Unlock()
+		return nil, nil, ErrJobRunning
+	}
+	j.running = true
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	repo := repository.NewJobRepository(database.DB)
+	conn, err := repo.TryLockJob(ctx, j.Name)
+	if err == nil && conn == nil {
+		err = ErrJobRunning
+	}
+	if err != nil {
+		s.setRunning(j, false)
+		return nil, nil, err
+	}
 
-	log.Println("Background Scheduler Started: Checking for expired drives every minute...")
-	log.Println("Background Scheduler Started: Managing Drives & OTPs...")
+	run, err := repo.StartRun(ctx, j.Name, trigger, s.instance, slot)
+	if err == nil && run == nil {
+		err = errSlotClaimed
+	}
+	if err != nil {
+		repo.UnlockJob(conn, j.Name)
+		s.setRunning(j, false)
+		return nil, nil, err
+	}
+	return run, conn, nil
 }
 
-func closeExpiredDrives() {
-	// Create a temporary context with a timeout (safety first!)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// execute runs a job begun with begin, records the outcome and releases the lock
+func (s *Scheduler) execute(j *scheduledJob, run *models.JobRun, conn *pgxpool.Conn) {
+	repo := repository.NewJobRepository(database.DB)
+	defer s.setRunning(j, false)
+	defer repo.UnlockJob(conn, j.Name)
+
+	ctx, cancel := context.WithTimeout(s.ctx, j.Timeout)
 	defer cancel()
 
-	repo := repository.NewDriveRepository(database.DB)
+	result, err := runJob(ctx, j.Job)
+	if err == nil && ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", j.Timeout)
+	}
 
-	count, err := repo.AutoCloseExpiredDrives(ctx)
+	status, errMsg := models.JobRunSucceeded, ""
 	if err != nil {
-		log.Printf("Error closing drives: %v\n", err)
-		return
+		status, errMsg = models.JobRunFailed, err.Error()
+		log.Printf("Scheduler: Job %s failed: %v\n", j.Name, err)
+	} else if result != "" {
+		log.Printf("Scheduler: Job %s: %s\n", j.Name, result)
 	}
 
-	if count > 0 {
-		log.Printf("Automatically closed %d expired drives.\n", count)
+	finishCtx, finishCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer finishCancel()
+	if err := repo.FinishRun(finishCtx, run.ID, status, result, errMsg); err != nil {
+		log.Printf("Scheduler: Failed to record run %d of %s: %v\n", run.ID, j.Name, err)
 	}
 }
 
-// Helper function for OTP cleanup
-func cleanupOTPs() {
-	// Create a context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// runJob turns a panicking job into a failed run instead of crashing the API
+func runJob(ctx context.Context, job Job) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+func (s *Scheduler) setRunning(j *scheduledJob, running bool) {
+	s.mu.Lock()
+	j.running = running
+	s.mu.Unlock()
+}
+
+// Jobs lists registered jobs with their next and last runs, sorted by name
+func (s *Scheduler) Jobs(ctx context.Context) ([]models.JobInfo, error) {
+	lastRuns, err := repository.NewJobRepository(database.DB).GetLastRuns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	jobs := make([]models.JobInfo, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		info := models.JobInfo{
+			Name:        j.Name,
+			Description: j.Description,
+			Schedule:    j.Schedule,
+			Timeout:     j.Timeout.String(),
+			Running:     j.running,
+		}
+		if j.schedule != nil {
+			info.NextRun = j.schedule.Next(now)
+		}
+		if run, ok := lastRuns[j.Name]; ok {
+			info.LastRun = &run
+		}
+		jobs = append(jobs, info)
+	}
+	sort.Slice(jobs, func(a, b int) bool { return jobs[a].Name < jobs[b].Name })
+	return jobs, nil
+}
+
+// HasJob reports whether a job with this name is registered
+func (s *Scheduler) HasJob(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.jobs[name]
+	return ok
+}
+
+// StartScheduler registers the portal's background jobs and starts the default scheduler
+func StartScheduler() {
+	jobs := []Job{
+		{
+			Name:        "close_expired_drives",
+			Description: "Close open drives whose deadline has passed",
+			Schedule:    "* * * * *",
+			Timeout:     30 * time.Second,
+			Run:         closeExpiredDrives,
+		},
+		{
+			Name:        "cleanup_otps",
+			Description: "Delete expired password reset OTPs",
+			Schedule:    "*/15 * * * *",
+			Timeout:     30 * time.Second,
+			Run:         cleanupOTPs,
+		},
+		{
+			Name:        "send_deadline_reminders",
+			Description: "Remind eligible students 24h and 2h before a drive deadline",
+			Schedule:    "*/5 * * * *",
+			Timeout:     4 * time.Minute,
+			Run:         sendDeadlineReminders,
+		},
+		{
+			Name:        "deliver_outgoing_webhooks",
+			Description: "Send pending and retrying outgoing webhook deliveries",
+			Schedule:    "* * * * *",
+			Timeout:     2 * time.Minute,
+			Run:         deliverOutgoingWebhooks,
+		},
+		{
+			Name:        "cleanup_webhook_deliveries",
+			Description: "Purge old incoming webhook delivery IDs kept for replay protection",
+			Schedule:    "0 3 * * *",
+			Timeout:     time.Minute,
+			Run:         cleanupWebhookDeliveries,
+		},
+		{
+			Name:        "cleanup_job_runs",
+			Description: "Purge job run history older than 30 days",
+			Schedule:    "30 3 * * *",
+			Timeout:     time.Minute,
+			Run:         cleanupJobRuns,
+		},
+	}
+
+	for _, job := range jobs {
+		if err := DefaultScheduler.Register(job); err != nil {
+			log.Printf("Scheduler: %v\n", err)
+		}
+	}
+	DefaultScheduler.Start()
+
+	log.Printf("Background Scheduler Started: %d jobs registered.\n", len(jobs))
+}
+
+// StopScheduler waits for running jobs to finish, up to ctx's deadline
+func StopScheduler(ctx context.Context) error {
+	return DefaultScheduler.Stop(ctx)
+}
+
+func closeExpiredDrives(ctx context.Context) (string, error) {
+	repo := repository.NewDriveRepository(database.DB)
+
+	count, err := repo.AutoCloseExpiredDrives(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("closed %d expired drives", count), nil
+}
 
+func cleanupOTPs(ctx context.Context) (string, error) {
 	repo := repository.NewUserRepository(database.DB)
 
 	count, err := repo.DeleteExpiredOTPs(ctx)
 	if err != nil {
-		log.Printf("️Error cleaning OTPs: %v\n", err)
-		return
+		return "", err
+	}
+	return fmt.Sprintf("deleted %d expired OTPs", count), nil
+}
+
+// cleanupWebhookDeliveries purges replay-protection records that can no longer be replayed.
+// Only sources that sign a timestamp qualify; WhatsApp and Railway keys are kept for good.
+func cleanupWebhookDeliveries(ctx context.Context) (string, error) {
+	repo := repository.NewWebhookDeliveryRepository(database.DB)
+
+	// A timestamp may be up to the tolerance ahead of when we recorded the key, and is accepted
+	// until the tolerance after it, so the key has to outlive twice the tolerance
+	retention := 2 * middleware.WebhookTimestampTolerance()
+
+	count, err := repo.DeleteOldDeliveries(ctx, middleware.ExpiringWebhookSources(), retention)
+	if err != nil {
+		return "", err
 	}
+	return fmt.Sprintf("deleted %d old webhook delivery IDs", count), nil
+}
+
+// cleanupJobRuns trims run history. Runs still 'running' after an hour outlived every job timeout,
+// so the instance running them must have died.
+func cleanupJobRuns(ctx context.Context) (string, error) {
+	repo := repository.NewJobRepository(database.DB)
 
-	if count > 0 {
-		log.Printf("Garbage Collector: Deleted %d expired OTPs.\n", count)
+	count, err := repo.CleanupRuns(ctx, 30*24*time.Hour, time.Hour)
+	if err != nil {
+		return "", err
 	}
+	return fmt.Sprintf("deleted %d old runs", count), nil
 }
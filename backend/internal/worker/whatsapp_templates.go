@@ -0,0 +1,31 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/SysSyncer/placement-portal-kec/internal/database"
+	"github.com/SysSyncer/placement-portal-kec/internal/models"
+	"github.com/SysSyncer/placement-portal-kec/internal/repository"
+	"github.com/SysSyncer/placement-portal-kec/internal/services"
+)
+
+// ResolveWhatsAppTemplate returns the active template for an event, falling back to the
+// built-in default when no admin configuration exists. Returns nil if the event is disabled.
+// Shared by the drive/status alerts (handlers) and the deadline reminder job.
+func ResolveWhatsAppTemplate(ctx context.Context, event string) *models.WhatsAppTemplate {
+	repo := repository.NewWhatsAppTemplateRepository(database.DB)
+	tpl, err := repo.GetTemplate(ctx, event)
+	if err != nil {
+		fmt.Printf("WhatsApp Error: Failed to load template for %s: %v\n", event, err)
+		return nil
+	}
+	if tpl == nil {
+		def := services.DefaultWhatsAppTemplates[event]
+		tpl = &def
+	}
+	if !tpl.IsActive {
+		return nil
+	}
+	return tpl
+}
@@ -0,0 +1,29 @@
+-- ==========================================
+-- 001: WHATSAPP TEMPLATE REGISTRY
+-- Adds whatsapp_templates, which maps portal events to Meta-approved templates, and
+-- seeds the default mapping (existing rows are kept).
+-- Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/001_whatsapp_templates.sql
+-- ==========================================
+BEGIN;
+
+-- Maps portal events to templates approved in Meta Business Manager.
+-- parameters is the ordered list of drive fields rendered into {{1}}, {{2}}, ...
+CREATE TABLE IF NOT EXISTS whatsapp_templates (
+    event VARCHAR(50) PRIMARY KEY CHECK (event IN ('new_drive', 'deadline_reminder', 'shortlisted', 'offer')),
+    template_name VARCHAR(100) NOT NULL,
+    language_code VARCHAR(10) NOT NULL DEFAULT 'en_US',
+    parameters JSONB DEFAULT '[]', -- e.g. ["company_name", "job_role", "deadline_date"]
+    is_active BOOLEAN DEFAULT TRUE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO whatsapp_templates (event, template_name, language_code, parameters) VALUES
+    ('new_drive', 'new_drive_alert', 'en_US', '["company_name", "job_role", "deadline_date"]'),
+    ('deadline_reminder', 'drive_deadline_reminder', 'en_US', '["company_name", "job_role", "deadline_date"]'),
+    ('shortlisted', 'drive_shortlisted', 'en_US', '["company_name", "job_role", "drive_date"]'),
+    ('offer', 'drive_offer', 'en_US', '["company_name", "job_role", "ctc_display"]')
+ON CONFLICT (event) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,29 @@
+-- ==========================================
+-- 002: WHATSAPP DELIVERY LOG
+-- Adds whatsapp_messages, one row per broadcast recipient, updated by status webhooks.
+-- Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/002_whatsapp_messages.sql
+-- ==========================================
+BEGIN;
+
+-- Per-recipient delivery records for business-initiated messages.
+-- message_id is the "wamid" returned by the Cloud API; status webhooks update the row.
+CREATE TABLE IF NOT EXISTS whatsapp_messages (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    message_id VARCHAR(255) UNIQUE, -- NULL when the API rejected the send
+    recipient VARCHAR(20) NOT NULL,
+    student_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE CASCADE,
+    event VARCHAR(50) NOT NULL,
+    template_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'accepted'
+    CHECK (status IN ('accepted', 'sent', 'delivered', 'read', 'failed')),
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_whatsapp_messages_drive ON whatsapp_messages(drive_id, status);
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- ==========================================
+-- 003: WEBHOOK REPLAY PROTECTION
+-- Adds webhook_deliveries, the keys of verified incoming webhooks used to reject replays.
+-- Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/003_webhook_deliveries.sql
+-- ==========================================
+BEGIN;
+
+-- Keys of verified incoming webhooks (SHA-256 of the signed "<timestamp>.<body>", or of the body).
+-- Keys of sources that sign a timestamp ('generic') are purged by the scheduler after twice the
+-- timestamp tolerance; 'whatsapp' and 'railway' send none, so their keys are kept to block replays.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    source VARCHAR(50) NOT NULL, -- 'whatsapp', 'railway', 'generic'
+    delivery_id VARCHAR(255) NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (source, delivery_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_received ON webhook_deliveries(received_at);
+
+COMMIT;
@@ -0,0 +1,27 @@
+-- ==========================================
+-- 004: INCOMING WEBHOOK EVENTS
+-- Adds webhook_events, every incoming webhook with the outcome of its handlers.
+-- Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/004_webhook_events.sql
+-- ==========================================
+BEGIN;
+
+-- Incoming webhook events as received, with the outcome of their handlers (replayable by admins)
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    source VARCHAR(50) NOT NULL, -- 'railway', 'generic'
+    event_type VARCHAR(100) NOT NULL,
+    headers JSONB DEFAULT '{}',
+    body JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'received'
+    CHECK (status IN ('received', 'processed', 'failed', 'ignored')),
+    error TEXT,
+    attempts INT DEFAULT 0,
+    received_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_events_lookup ON webhook_events(source, event_type, received_at DESC);
+
+COMMIT;
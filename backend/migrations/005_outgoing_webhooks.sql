@@ -0,0 +1,42 @@
+-- ==========================================
+-- 005: OUTGOING WEBHOOKS
+-- Adds outgoing_webhooks (subscriptions) and outgoing_webhook_deliveries (retry queue and log).
+-- Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/005_outgoing_webhooks.sql
+-- ==========================================
+BEGIN;
+
+-- Subscriptions of other internal tools (alumni tracker, department dashboard) to portal events
+CREATE TABLE IF NOT EXISTS outgoing_webhooks (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL, -- HMAC-SHA256 key for X-Webhook-Signature
+    event_types JSONB NOT NULL DEFAULT '[]', -- e.g. ["drive.published", "student.placed"]
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per event per subscription; retried with backoff, 'dead' after the last attempt
+CREATE TABLE IF NOT EXISTS outgoing_webhook_deliveries (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    webhook_id BIGINT NOT NULL REFERENCES outgoing_webhooks(id) ON DELETE CASCADE,
+    event_id VARCHAR(50) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts INT DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    last_status_code INT,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_outgoing_deliveries_due ON outgoing_webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_outgoing_deliveries_webhook ON outgoing_webhook_deliveries(webhook_id, created_at DESC);
+
+COMMIT;
@@ -0,0 +1,30 @@
+-- ==========================================
+-- 006: DEADLINE REMINDERS
+-- Adds notification_preferences (channels per student) and drive_reminders (one row per
+-- reminder sent).
+-- Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/006_deadline_reminders.sql
+-- ==========================================
+BEGIN;
+
+-- Channels each student wants deadline reminders on (no row = everything enabled)
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    push_enabled BOOLEAN DEFAULT TRUE,
+    email_enabled BOOLEAN DEFAULT TRUE,
+    whatsapp_enabled BOOLEAN DEFAULT TRUE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per reminder sent, so each drive/student/type is reminded only once
+CREATE TABLE IF NOT EXISTS drive_reminders (
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE CASCADE,
+    student_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    reminder_type VARCHAR(10) NOT NULL CHECK (reminder_type IN ('24h', '2h')),
+    channels JSONB DEFAULT '[]', -- e.g. ["push", "email", "whatsapp"]
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (drive_id, student_id, reminder_type)
+);
+
+COMMIT;
@@ -0,0 +1,46 @@
+-- ==========================================
+-- 007: BACKGROUND JOB RUNS
+-- Adds job_runs, the history of scheduled and manual background job runs, with one row
+-- per cron slot so replicas never run the same slot twice.
+-- Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/007_job_runs.sql
+-- ==========================================
+BEGIN;
+
+-- One row per scheduled or manual run of a background job (see worker.StartScheduler)
+CREATE TABLE IF NOT EXISTS job_runs (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    job_name VARCHAR(100) NOT NULL,
+    trigger VARCHAR(20) NOT NULL CHECK (trigger IN ('schedule', 'manual')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('running', 'succeeded', 'failed')),
+    result TEXT,
+    error TEXT,
+    instance VARCHAR(255), -- host:pid that ran the job
+    scheduled_for TIMESTAMP WITH TIME ZONE, -- Cron slot of a scheduled run, NULL for manual runs
+    started_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    duration_ms BIGINT
+);
+
+-- Earlier runs created id as BIGSERIAL and had no scheduled_for
+ALTER TABLE job_runs ADD COLUMN IF NOT EXISTS scheduled_for TIMESTAMP WITH TIME ZONE;
+
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'job_runs' AND column_name = 'id' AND is_identity = 'NO'
+    ) THEN
+        ALTER TABLE job_runs ALTER COLUMN id DROP DEFAULT;
+        DROP SEQUENCE IF EXISTS job_runs_id_seq;
+        ALTER TABLE job_runs ALTER COLUMN id ADD GENERATED ALWAYS AS IDENTITY;
+        PERFORM setval(pg_get_serial_sequence('job_runs', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM job_runs;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job ON job_runs(job_name, started_at DESC);
+-- Each cron slot runs once across replicas (manual runs have no slot, and NULLs never conflict)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_runs_slot ON job_runs(job_name, scheduled_for);
+
+COMMIT;
@@ -0,0 +1,48 @@
+-- ==========================================
+-- 008: INTERVIEW SLOTS
+-- Adds interview_slots (per drive round) and interview_bookings (one slot per student per round).
+-- Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/008_interview_slots.sql
+-- ==========================================
+BEGIN;
+
+-- Interview Slots (per drive round)
+-- round_index is the position of the round in placement_drives.rounds; round_name is a snapshot for display.
+CREATE TABLE IF NOT EXISTS interview_slots (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE CASCADE,
+    round_index INT NOT NULL CHECK (round_index >= 0),
+    round_name VARCHAR(100),
+
+    panel_name VARCHAR(150) NOT NULL,
+    panel_emails JSONB DEFAULT '[]', -- Panel members notified of their schedule
+    location VARCHAR(255),           -- Room
+    meeting_link TEXT,               -- For online interviews
+
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL CHECK (end_time > start_time),
+    capacity INT NOT NULL DEFAULT 1 CHECK (capacity > 0),
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_interview_slots_drive ON interview_slots(drive_id, round_index, start_time);
+
+-- Interview Bookings (one slot per student per round)
+CREATE TABLE IF NOT EXISTS interview_bookings (
+    slot_id BIGINT REFERENCES interview_slots(id) ON DELETE CASCADE,
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE CASCADE,
+    round_index INT NOT NULL,
+    student_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+
+    source VARCHAR(20) DEFAULT 'student' CHECK (source IN ('auto', 'student')),
+    booked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (slot_id, student_id),
+    UNIQUE (drive_id, round_index, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_interview_bookings_student ON interview_bookings(student_id);
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- ==========================================
+-- 009: CALENDAR FEED TOKENS
+-- Adds calendar_tokens, the secret in each student's iCalendar feed URL.
+-- Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/009_calendar_tokens.sql
+-- ==========================================
+BEGIN;
+
+-- Secret token in each student's calendar feed URL (/api/v1/calendar/<token>.ics); rotating it revokes old subscriptions
+CREATE TABLE IF NOT EXISTS calendar_tokens (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMIT;
@@ -0,0 +1,38 @@
+-- ==========================================
+-- 010: DRIVE DAY ATTENDANCE
+-- Adds drive_attendance (QR check-ins per round) and drive_no_shows (no-show penalties).
+-- Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/010_drive_attendance.sql
+-- ==========================================
+BEGIN;
+
+-- Drive Day Attendance (QR check-in per round)
+CREATE TABLE IF NOT EXISTS drive_attendance (
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE CASCADE,
+    student_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    round_index INT NOT NULL DEFAULT 0, -- Position in placement_drives.rounds
+
+    checked_in_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    checked_in_by BIGINT REFERENCES users(id) ON DELETE SET NULL, -- Coordinator who scanned the QR
+
+    PRIMARY KEY (drive_id, student_id, round_index)
+);
+
+-- No-Shows (expected but never checked in)
+-- penalty_drives is captured when recorded (NO_SHOW_PENALTY_DRIVES): the student can't apply to that many drives posted afterwards.
+CREATE TABLE IF NOT EXISTS drive_no_shows (
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE CASCADE,
+    student_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    round_index INT NOT NULL DEFAULT 0,
+
+    penalty_drives INT NOT NULL DEFAULT 0,
+    waived BOOLEAN DEFAULT FALSE,
+    recorded_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (drive_id, student_id, round_index)
+);
+
+CREATE INDEX IF NOT EXISTS idx_no_shows_student ON drive_no_shows(student_id) WHERE waived = false;
+
+COMMIT;
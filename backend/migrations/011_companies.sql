@@ -0,0 +1,127 @@
+-- ==========================================
+-- 011: COMPANIES MASTER
+-- Adds companies / company_aliases / company_spocs and placement_drives.company_id,
+-- then merges the existing free-text company names into companies.
+-- Safe to re-run: every step skips rows that are already migrated.
+--
+--   psql "$DATABASE_URL" -f migrations/011_companies.sql
+-- ==========================================
+BEGIN;
+
+-- 1. Schema (same definitions as project_schema.sql section 4.2)
+CREATE OR REPLACE FUNCTION normalize_company_name(p_name TEXT) RETURNS TEXT AS $$
+    SELECT COALESCE(NULLIF(trim(regexp_replace(regexp_replace(
+        regexp_replace(lower(COALESCE(p_name, '')), '[^a-z0-9]+', ' ', 'g'),
+        '\m(private|pvt|limited|ltd|inc|llp|llc|corp|corporation)\M', ' ', 'g'),
+        '\s+', ' ', 'g')), ''), lower(trim(COALESCE(p_name, ''))));
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION normalize_company_domain(p_url TEXT) RETURNS TEXT AS $$
+    SELECT CASE WHEN host ~ ('(^|\.)(linkedin\.com|lnkd\.in|naukri\.com|indeed\.com|glassdoor\.[a-z.]+|internshala\.com'
+                             '|unstop\.com|wellfound\.com|instahyre\.com|hirist\.(com|tech)|foundit\.in|joinsuperset\.com'
+                             '|myworkdayjobs\.com|greenhouse\.io|lever\.co|smartrecruiters\.com|darwinbox\.in'
+                             '|forms\.gle|docs\.google\.com|forms\.google\.com|drive\.google\.com|sites\.google\.com'
+                             '|forms\.office\.com|bit\.ly|tinyurl\.com)$')
+                THEN '' ELSE host END
+    FROM (SELECT regexp_replace(regexp_replace(lower(trim(COALESCE(p_url, ''))), '^([a-z]+://)?(www\.)?', ''), '[/:?#].*$', '') AS host) h;
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE TABLE IF NOT EXISTS companies (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name VARCHAR(150) NOT NULL,
+    normalized_name VARCHAR(150) NOT NULL UNIQUE,
+    domain VARCHAR(255) UNIQUE,
+    logo_url TEXT,
+    category VARCHAR(50) CHECK (category IN ('Core', 'IT', 'Service', 'Non-Tech', 'Product', 'Start-up', 'MNC')),
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS company_aliases (
+    alias VARCHAR(150) PRIMARY KEY,
+    company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_company_aliases_company ON company_aliases(company_id);
+
+CREATE TABLE IF NOT EXISTS company_spocs (
+    company_id BIGINT REFERENCES companies(id) ON DELETE CASCADE,
+    spoc_id BIGINT REFERENCES spocs(id) ON DELETE CASCADE,
+
+    PRIMARY KEY (company_id, spoc_id)
+);
+
+ALTER TABLE placement_drives ADD COLUMN IF NOT EXISTS company_id BIGINT REFERENCES companies(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_drives_company ON placement_drives(company_id);
+
+-- Shared hosts (job boards, form builders, link shorteners) don't identify a company;
+-- clear any that an earlier run stored as a company's domain
+UPDATE companies SET domain = NULL WHERE domain IS NOT NULL AND normalize_company_domain(domain) = '';
+
+-- 2. Drives with a website: one company per domain, so "TCS" and "Tata Consultancy Services"
+--    on tcs.com become one company. Display fields come from the most recent drive.
+--    Links to shared hosts (linkedin.com/company/..., forms.gle, naukri.com, ...) normalize to ''
+--    and fall through to the name match in step 3.
+INSERT INTO companies (name, normalized_name, domain, logo_url, category)
+SELECT DISTINCT ON (normalize_company_domain(website))
+    trim(company_name), normalize_company_name(company_name), normalize_company_domain(website),
+    NULLIF(logo_url, ''), company_category
+FROM placement_drives
+WHERE company_id IS NULL AND normalize_company_domain(website) <> ''
+ORDER BY normalize_company_domain(website), created_at DESC
+ON CONFLICT DO NOTHING;
+
+UPDATE placement_drives pd
+SET company_id = c.id
+FROM companies c
+WHERE pd.company_id IS NULL AND c.domain = normalize_company_domain(pd.website);
+
+-- Every spelling used on those drives becomes an alias
+INSERT INTO company_aliases (alias, company_id)
+SELECT normalized_name, id FROM companies
+ON CONFLICT DO NOTHING;
+
+INSERT INTO company_aliases (alias, company_id)
+SELECT DISTINCT normalize_company_name(company_name), company_id
+FROM placement_drives
+WHERE company_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- 3. Remaining drives: group by normalized name ("TCS" = "tcs " = "TCS Ltd."), unless the name is already an alias
+INSERT INTO companies (name, normalized_name, logo_url, category)
+SELECT DISTINCT ON (normalize_company_name(pd.company_name))
+    trim(pd.company_name), normalize_company_name(pd.company_name), NULLIF(pd.logo_url, ''), pd.company_category
+FROM placement_drives pd
+WHERE pd.company_id IS NULL
+  AND NOT EXISTS (SELECT 1 FROM company_aliases a WHERE a.alias = normalize_company_name(pd.company_name))
+ORDER BY normalize_company_name(pd.company_name), pd.created_at DESC
+ON CONFLICT DO NOTHING;
+
+INSERT INTO company_aliases (alias, company_id)
+SELECT normalized_name, id FROM companies
+ON CONFLICT DO NOTHING;
+
+UPDATE placement_drives pd
+SET company_id = a.company_id
+FROM company_aliases a
+WHERE pd.company_id IS NULL AND a.alias = normalize_company_name(pd.company_name);
+
+-- 4. SPOCs who handled a company's drives become its SPOCs
+INSERT INTO company_spocs (company_id, spoc_id)
+SELECT DISTINCT company_id, spoc_id FROM placement_drives
+WHERE company_id IS NOT NULL AND spoc_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+INSERT INTO company_spocs (company_id, spoc_id)
+SELECT DISTINCT pd.company_id, ds.spoc_id
+FROM drive_spocs ds
+JOIN placement_drives pd ON pd.id = ds.drive_id
+WHERE pd.company_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+COMMIT;
+
+-- Review what was merged; remaining duplicates can be merged with POST /api/v1/admin/companies/{id}/merge
+-- SELECT c.id, c.name, c.domain, array_agg(DISTINCT pd.company_name) AS spellings
+-- FROM companies c JOIN placement_drives pd ON pd.company_id = c.id GROUP BY c.id ORDER BY c.name;
@@ -0,0 +1,42 @@
+-- ==========================================
+-- 012: RECRUITER ACCOUNTS & AUDIT LOG
+-- Adds the 'recruiter' role, recruiters (user -> company) and audit_logs.
+-- Requires 011_companies.sql. Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/012_recruiters.sql
+-- ==========================================
+BEGIN;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('student', 'admin', 'coordinator', 'recruiter'));
+
+CREATE TABLE IF NOT EXISTS recruiters (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    full_name VARCHAR(150) NOT NULL,
+    designation VARCHAR(100),
+    mobile_number VARCHAR(15),
+
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_recruiters_company ON recruiters(company_id);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    actor_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    actor_role VARCHAR(20) NOT NULL,
+    company_id BIGINT REFERENCES companies(id) ON DELETE SET NULL,
+    action VARCHAR(100) NOT NULL,
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE SET NULL,
+    student_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    details JSONB DEFAULT '{}',
+    ip_address VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_company ON audit_logs(company_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON audit_logs(actor_id, created_at DESC);
+
+COMMIT;
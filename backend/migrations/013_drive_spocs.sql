@@ -0,0 +1,22 @@
+-- ==========================================
+-- 013: MULTIPLE SPOCS PER DRIVE
+-- Drives now keep their SPOCs in drive_spocs; placement_drives.spoc_id stays as the primary contact.
+-- Copies each drive's spoc_id into drive_spocs. Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/013_drive_spocs.sql
+-- ==========================================
+BEGIN;
+
+-- Drives that already list a primary keep it
+INSERT INTO drive_spocs (drive_id, spoc_id, is_primary_contact)
+SELECT pd.id, pd.spoc_id, NOT EXISTS (
+    SELECT 1 FROM drive_spocs ds WHERE ds.drive_id = pd.id AND ds.is_primary_contact
+)
+FROM placement_drives pd
+WHERE pd.spoc_id IS NOT NULL
+ON CONFLICT (drive_id, spoc_id) DO NOTHING;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_drive_spocs_primary ON drive_spocs(drive_id) WHERE is_primary_contact;
+CREATE INDEX IF NOT EXISTS idx_drive_spocs_spoc ON drive_spocs(spoc_id);
+
+COMMIT;
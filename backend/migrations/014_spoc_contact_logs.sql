@@ -0,0 +1,28 @@
+-- ==========================================
+-- 014: SPOC CONTACT LOG
+-- Adds spoc_contact_logs (calls, emails, visits with follow-up dates). Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/014_spoc_contact_logs.sql
+-- ==========================================
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS spoc_contact_logs (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    spoc_id BIGINT NOT NULL REFERENCES spocs(id) ON DELETE RESTRICT,
+    contact_type VARCHAR(10) NOT NULL CHECK (contact_type IN ('call', 'email', 'visit')),
+    company_id BIGINT REFERENCES companies(id) ON DELETE SET NULL, -- Optional: what the contact was about
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE SET NULL,
+    notes TEXT NOT NULL,
+    contacted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    follow_up_date DATE,
+    follow_up_done BOOLEAN NOT NULL DEFAULT FALSE,
+
+    logged_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_spoc_contact_logs_spoc ON spoc_contact_logs(spoc_id, contacted_at DESC);
+CREATE INDEX IF NOT EXISTS idx_spoc_contact_logs_follow_up ON spoc_contact_logs(follow_up_date) WHERE NOT follow_up_done;
+
+COMMIT;
@@ -0,0 +1,69 @@
+-- ==========================================
+-- 015: STUDENT PROFILE SECTIONS
+-- Adds structured skills, projects, internships and certifications. Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/015_student_profile_sections.sql
+-- ==========================================
+BEGIN;
+
+-- Skills (one row per skill, searchable by admins)
+CREATE TABLE IF NOT EXISTS student_skills (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    proficiency VARCHAR(20) NOT NULL DEFAULT 'intermediate' CHECK (proficiency IN ('beginner', 'intermediate', 'advanced', 'expert')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_student_skills_name ON student_skills(user_id, lower(name));
+CREATE INDEX IF NOT EXISTS idx_student_skills_lookup ON student_skills(lower(name));
+
+-- Projects
+CREATE TABLE IF NOT EXISTS student_projects (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(150) NOT NULL,
+    description TEXT,
+    tech_stack JSONB DEFAULT '[]', -- e.g. ["Go", "PostgreSQL"]
+    project_url TEXT,
+    repo_url TEXT,
+    start_date DATE,
+    end_date DATE, -- NULL while ongoing
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_student_projects_user ON student_projects(user_id);
+
+-- Internships
+CREATE TABLE IF NOT EXISTS student_internships (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    company_name VARCHAR(150) NOT NULL,
+    role VARCHAR(150) NOT NULL,
+    location VARCHAR(100),
+    start_date DATE NOT NULL,
+    end_date DATE, -- NULL while ongoing
+    stipend DECIMAL(10,2),
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date IS NULL OR end_date >= start_date)
+);
+CREATE INDEX IF NOT EXISTS idx_student_internships_user ON student_internships(user_id);
+
+-- Certifications (proof is an uploaded file in S3)
+CREATE TABLE IF NOT EXISTS student_certifications (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(150) NOT NULL,
+    issuer VARCHAR(150) NOT NULL,
+    issue_date DATE,
+    expiry_date DATE,
+    credential_id VARCHAR(100),
+    credential_url TEXT,
+    proof_url TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_student_certifications_user ON student_certifications(user_id);
+
+COMMIT;
@@ -0,0 +1,21 @@
+-- ==========================================
+-- 016: SAVED STUDENT SEARCHES
+-- Adds saved_student_searches for the admin student search. Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/016_saved_student_searches.sql
+-- ==========================================
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS saved_student_searches (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    query JSONB NOT NULL DEFAULT '{}',
+    is_shared BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_student_searches_name ON saved_student_searches(created_by, lower(name));
+
+COMMIT;
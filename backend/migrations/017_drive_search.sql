@@ -0,0 +1,19 @@
+-- ==========================================
+-- 017: DRIVE FULL-TEXT SEARCH
+-- Adds placement_drives.search_vector (generated, so existing drives are indexed on
+-- upgrade) and its GIN index. Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/017_drive_search.sql
+-- ==========================================
+BEGIN;
+
+ALTER TABLE placement_drives ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(company_name, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(job_role, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(location, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(job_description, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_drives_search ON placement_drives USING GIN (search_vector);
+
+COMMIT;
@@ -0,0 +1,70 @@
+-- ==========================================
+-- 018: APPLICATION STATUS HISTORY
+-- Adds application_status_history and backfills it from drive_applications: the
+-- original application at applied_at, plus the current status at updated_at when it
+-- has moved on. Who made those earlier changes isn't known, so they are recorded as
+-- 'system'. History is kept when the application, drive or student is deleted, and a
+-- trigger rejects edits and deletes. Safe to re-run (applications that already have history
+-- are skipped).
+--
+--   psql "$DATABASE_URL" -f migrations/018_application_status_history.sql
+-- ==========================================
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS application_status_history (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    drive_id BIGINT NOT NULL,
+    student_id BIGINT NOT NULL,
+
+    from_status VARCHAR(30),
+    to_status VARCHAR(30) NOT NULL,
+    changed_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    actor_role VARCHAR(20) NOT NULL CHECK (actor_role IN ('student', 'admin', 'coordinator', 'recruiter', 'system')),
+    remark TEXT,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Earlier runs tied history to drive_applications with ON DELETE CASCADE, which erased it with the application
+ALTER TABLE application_status_history DROP CONSTRAINT IF EXISTS application_status_history_drive_id_student_id_fkey;
+
+CREATE INDEX IF NOT EXISTS idx_app_status_history_app ON application_status_history(drive_id, student_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_app_status_history_student ON application_status_history(student_id);
+
+-- Append-only: history rows can't be edited or deleted. The one UPDATE let through is the
+-- changed_by foreign key clearing the actor when their account is deleted.
+CREATE OR REPLACE FUNCTION application_status_history_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.changed_by IS NULL
+       AND to_jsonb(NEW) - 'changed_by' = to_jsonb(OLD) - 'changed_by' THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'application_status_history is append-only: % is not allowed', TG_OP;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_application_status_history_append_only ON application_status_history;
+CREATE TRIGGER trg_application_status_history_append_only
+BEFORE UPDATE OR DELETE ON application_status_history
+FOR EACH ROW EXECUTE FUNCTION application_status_history_append_only();
+
+-- Backfill
+WITH pending AS (
+    SELECT da.*
+    FROM drive_applications da
+    WHERE NOT EXISTS (
+        SELECT 1 FROM application_status_history h
+        WHERE h.drive_id = da.drive_id AND h.student_id = da.student_id
+    )
+)
+INSERT INTO application_status_history (drive_id, student_id, from_status, to_status, actor_role, remark, created_at)
+SELECT drive_id, student_id, NULL, 'opted_in', 'system', 'Backfilled', COALESCE(applied_at, NOW())
+FROM pending
+WHERE status <> 'eligible'
+UNION ALL
+SELECT drive_id, student_id, CASE WHEN status = 'eligible' THEN NULL ELSE 'opted_in' END, status, 'system', 'Backfilled',
+       COALESCE(updated_at, applied_at, NOW())
+FROM pending
+WHERE status <> 'opted_in';
+
+COMMIT;
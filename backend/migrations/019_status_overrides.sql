@@ -0,0 +1,13 @@
+-- ==========================================
+-- 019: STATUS OVERRIDES
+-- Application status changes now follow a transition graph; admins can force other
+-- moves with a remark. Adds application_status_history.is_override to flag those.
+-- Safe to re-run.
+--
+--   psql "$DATABASE_URL" -f migrations/019_status_overrides.sql
+-- ==========================================
+BEGIN;
+
+ALTER TABLE application_status_history ADD COLUMN IF NOT EXISTS is_override BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMIT;
@@ -9,11 +9,40 @@
 -- ==========================================
 DROP VIEW IF EXISTS view_student_details CASCADE;
 DROP FUNCTION IF EXISTS apply_for_drive(BIGINT, BIGINT);
+DROP FUNCTION IF EXISTS normalize_company_name(TEXT);
+DROP FUNCTION IF EXISTS normalize_company_domain(TEXT);
+DROP FUNCTION IF EXISTS application_status_history_append_only() CASCADE;
+DROP TABLE IF EXISTS saved_student_searches CASCADE;
+DROP TABLE IF EXISTS audit_logs CASCADE;
+DROP TABLE IF EXISTS job_runs CASCADE;
+DROP TABLE IF EXISTS drive_no_shows CASCADE;
+DROP TABLE IF EXISTS drive_attendance CASCADE;
+DROP TABLE IF EXISTS interview_bookings CASCADE;
+DROP TABLE IF EXISTS interview_slots CASCADE;
+DROP TABLE IF EXISTS calendar_tokens CASCADE;
+DROP TABLE IF EXISTS drive_reminders CASCADE;
+DROP TABLE IF EXISTS notification_preferences CASCADE;
+DROP TABLE IF EXISTS outgoing_webhook_deliveries CASCADE;
+DROP TABLE IF EXISTS outgoing_webhooks CASCADE;
+DROP TABLE IF EXISTS webhook_events CASCADE;
+DROP TABLE IF EXISTS webhook_deliveries CASCADE;
+DROP TABLE IF EXISTS whatsapp_messages CASCADE;
+DROP TABLE IF EXISTS whatsapp_templates CASCADE;
 DROP TABLE IF EXISTS password_resets CASCADE;
+DROP TABLE IF EXISTS application_status_history CASCADE;
 DROP TABLE IF EXISTS drive_applications CASCADE;
+DROP TABLE IF EXISTS spoc_contact_logs CASCADE;
 DROP TABLE IF EXISTS drive_spocs CASCADE;
+DROP TABLE IF EXISTS recruiters CASCADE;
+DROP TABLE IF EXISTS company_spocs CASCADE;
+DROP TABLE IF EXISTS company_aliases CASCADE;
+DROP TABLE IF EXISTS companies CASCADE;
 DROP TABLE IF EXISTS spocs CASCADE;
 DROP TABLE IF EXISTS placement_drives CASCADE;
+DROP TABLE IF EXISTS student_certifications CASCADE;
+DROP TABLE IF EXISTS student_internships CASCADE;
+DROP TABLE IF EXISTS student_projects CASCADE;
+DROP TABLE IF EXISTS student_skills CASCADE;
 DROP TABLE IF EXISTS student_documents CASCADE;
 DROP TABLE IF EXISTS student_academics CASCADE;
 DROP TABLE IF EXISTS student_personal CASCADE;
@@ -27,7 +56,7 @@ CREATE TABLE users (
     email VARCHAR(255) UNIQUE NOT NULL,
     password_hash VARCHAR(255) NOT NULL,
     
-    role VARCHAR(20) NOT NULL CHECK (role IN ('student', 'admin', 'coordinator')),
+    role VARCHAR(20) NOT NULL CHECK (role IN ('student', 'admin', 'coordinator', 'recruiter')),
     
     -- Notification Token
     fcm_token TEXT,
@@ -125,6 +154,66 @@ CREATE TABLE student_documents (
     pan_card_url TEXT
 );
 
+-- 3.4 Skills (one row per skill, searchable by admins)
+CREATE TABLE student_skills (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    proficiency VARCHAR(20) NOT NULL DEFAULT 'intermediate' CHECK (proficiency IN ('beginner', 'intermediate', 'advanced', 'expert')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_student_skills_name ON student_skills(user_id, lower(name));
+CREATE INDEX idx_student_skills_lookup ON student_skills(lower(name));
+
+-- 3.5 Projects
+CREATE TABLE student_projects (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(150) NOT NULL,
+    description TEXT,
+    tech_stack JSONB DEFAULT '[]', -- e.g. ["Go", "PostgreSQL"]
+    project_url TEXT,
+    repo_url TEXT,
+    start_date DATE,
+    end_date DATE, -- NULL while ongoing
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_student_projects_user ON student_projects(user_id);
+
+-- 3.6 Internships
+CREATE TABLE student_internships (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    company_name VARCHAR(150) NOT NULL,
+    role VARCHAR(150) NOT NULL,
+    location VARCHAR(100),
+    start_date DATE NOT NULL,
+    end_date DATE, -- NULL while ongoing
+    stipend DECIMAL(10,2),
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (end_date IS NULL OR end_date >= start_date)
+);
+CREATE INDEX idx_student_internships_user ON student_internships(user_id);
+
+-- 3.7 Certifications (proof is an uploaded file in S3)
+CREATE TABLE student_certifications (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(150) NOT NULL,
+    issuer VARCHAR(150) NOT NULL,
+    issue_date DATE,
+    expiry_date DATE,
+    credential_id VARCHAR(100),
+    credential_url TEXT,
+    proof_url TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_student_certifications_user ON student_certifications(user_id);
+
 -- ==========================================
 -- 4. MASTER DATA Management (SPOCs)
 -- ==========================================
@@ -142,6 +231,71 @@ CREATE TABLE spocs (
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
+-- 4.2 Companies (Recruiter Master)
+-- Drives keep company_name as typed; company_id ties the spellings ("TCS", "tcs ", "Tata Consultancy Services")
+-- to one company. Names are matched on normalize_company_name(), websites on normalize_company_domain().
+CREATE OR REPLACE FUNCTION normalize_company_name(p_name TEXT) RETURNS TEXT AS $$
+    -- "Infosys Pvt. Ltd" -> "infosys": lowercase, punctuation to spaces, legal suffixes dropped
+    SELECT COALESCE(NULLIF(trim(regexp_replace(regexp_replace(
+        regexp_replace(lower(COALESCE(p_name, '')), '[^a-z0-9]+', ' ', 'g'),
+        '\m(private|pvt|limited|ltd|inc|llp|llc|corp|corporation)\M', ' ', 'g'),
+        '\s+', ' ', 'g')), ''), lower(trim(COALESCE(p_name, ''))));
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION normalize_company_domain(p_url TEXT) RETURNS TEXT AS $$
+    -- "https://www.tcs.com/careers" -> "tcs.com". Shared hosts (job boards, form builders, link
+    -- shorteners) -> '': a linkedin.com or forms.gle link says nothing about which company it is
+    SELECT CASE WHEN host ~ ('(^|\.)(linkedin\.com|lnkd\.in|naukri\.com|indeed\.com|glassdoor\.[a-z.]+|internshala\.com'
+                             '|unstop\.com|wellfound\.com|instahyre\.com|hirist\.(com|tech)|foundit\.in|joinsuperset\.com'
+                             '|myworkdayjobs\.com|greenhouse\.io|lever\.co|smartrecruiters\.com|darwinbox\.in'
+                             '|forms\.gle|docs\.google\.com|forms\.google\.com|drive\.google\.com|sites\.google\.com'
+                             '|forms\.office\.com|bit\.ly|tinyurl\.com)$')
+                THEN '' ELSE host END
+    FROM (SELECT regexp_replace(regexp_replace(lower(trim(COALESCE(p_url, ''))), '^([a-z]+://)?(www\.)?', ''), '[/:?#].*$', '') AS host) h;
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE TABLE companies (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name VARCHAR(150) NOT NULL,                    -- Display name
+    normalized_name VARCHAR(150) NOT NULL UNIQUE,
+    domain VARCHAR(255) UNIQUE,                    -- Normalized, e.g. "tcs.com"
+    logo_url TEXT,
+    category VARCHAR(50) CHECK (category IN ('Core', 'IT', 'Service', 'Non-Tech', 'Product', 'Start-up', 'MNC')),
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Every normalized spelling that resolves to a company, including its own normalized_name
+CREATE TABLE company_aliases (
+    alias VARCHAR(150) PRIMARY KEY,
+    company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_company_aliases_company ON company_aliases(company_id);
+
+CREATE TABLE company_spocs (
+    company_id BIGINT REFERENCES companies(id) ON DELETE CASCADE,
+    spoc_id BIGINT REFERENCES spocs(id) ON DELETE CASCADE,
+
+    PRIMARY KEY (company_id, spoc_id)
+);
+
+-- 4.3 Recruiter Accounts (company HRs; role = 'recruiter')
+-- Every recruiter route is scoped to company_id.
+CREATE TABLE recruiters (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    company_id BIGINT NOT NULL REFERENCES companies(id) ON DELETE CASCADE,
+    full_name VARCHAR(150) NOT NULL,
+    designation VARCHAR(100),
+    mobile_number VARCHAR(15),
+
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_recruiters_company ON recruiters(company_id);
+
 -- ==========================================
 -- 5. PLACEMENT DRIVE MODULE
 -- ==========================================
@@ -151,6 +305,7 @@ CREATE TABLE placement_drives (
     
     -- Job Details
     company_name VARCHAR(150) NOT NULL,
+    company_id BIGINT REFERENCES companies(id) ON DELETE SET NULL,
     job_role VARCHAR(150) NOT NULL,
     job_description TEXT,
     location VARCHAR(100),
@@ -195,10 +350,20 @@ CREATE TABLE placement_drives (
     status VARCHAR(20) DEFAULT 'open' 
     CHECK (status IN ('open', 'closed', 'completed', 'cancelled', 'on_hold', 'draft')),
     
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    -- Full-text search (company and role rank above location, then description)
+    search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(company_name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(job_role, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(location, '')), 'B') ||
+        setweight(to_tsvector('english', coalesce(job_description, '')), 'C')
+    ) STORED
 );
 
 CREATE INDEX idx_drives_deadline ON placement_drives(deadline_date);
+CREATE INDEX idx_drives_company ON placement_drives(company_id);
+CREATE INDEX idx_drives_search ON placement_drives USING GIN (search_vector);
 
 -- 5.1 Drive-SPOC Binding (Many-to-Many)
 -- A drive can have multiple SPOCs, and a SPOC can manage multiple drives.
@@ -211,6 +376,30 @@ CREATE TABLE drive_spocs (
     PRIMARY KEY (drive_id, spoc_id)
 );
 
+CREATE UNIQUE INDEX idx_drive_spocs_primary ON drive_spocs(drive_id) WHERE is_primary_contact; -- One primary per drive
+CREATE INDEX idx_drive_spocs_spoc ON drive_spocs(spoc_id);
+
+-- 5.2 SPOC Contact Log
+-- Calls, emails and visits with a SPOC. Kept when staff change; a SPOC with history can only be deactivated.
+CREATE TABLE spoc_contact_logs (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    spoc_id BIGINT NOT NULL REFERENCES spocs(id) ON DELETE RESTRICT,
+    contact_type VARCHAR(10) NOT NULL CHECK (contact_type IN ('call', 'email', 'visit')),
+    company_id BIGINT REFERENCES companies(id) ON DELETE SET NULL, -- Optional: what the contact was about
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE SET NULL,
+    notes TEXT NOT NULL,
+    contacted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    follow_up_date DATE,
+    follow_up_done BOOLEAN NOT NULL DEFAULT FALSE,
+
+    logged_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_spoc_contact_logs_spoc ON spoc_contact_logs(spoc_id, contacted_at DESC);
+CREATE INDEX idx_spoc_contact_logs_follow_up ON spoc_contact_logs(follow_up_date) WHERE NOT follow_up_done;
+
 -- ==========================================
 -- 6. APPLICATIONS & REGISTRATIONS
 -- ==========================================
@@ -218,7 +407,7 @@ CREATE TABLE drive_applications (
     drive_id BIGINT REFERENCES placement_drives(id) ON DELETE CASCADE,
     student_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
     
-    -- Status Workflow
+    -- Status Workflow (allowed moves: models.ApplicationStatus)
     status VARCHAR(30) DEFAULT 'opted_in' 
     CHECK (status IN ('eligible', 'opted_in', 'opted_out', 'shortlisted', 'rejected', 'placed', 'removed')),
     
@@ -234,6 +423,110 @@ CREATE TABLE drive_applications (
 
 CREATE INDEX idx_apps_status ON drive_applications(drive_id, status);
 
+-- 6.1 Interview Slots (per drive round)
+-- round_index is the position of the round in placement_drives.rounds; round_name is a snapshot for display.
+CREATE TABLE interview_slots (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE CASCADE,
+    round_index INT NOT NULL CHECK (round_index >= 0),
+    round_name VARCHAR(100),
+
+    panel_name VARCHAR(150) NOT NULL,
+    panel_emails JSONB DEFAULT '[]', -- Panel members notified of their schedule
+    location VARCHAR(255),           -- Room
+    meeting_link TEXT,               -- For online interviews
+
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL CHECK (end_time > start_time),
+    capacity INT NOT NULL DEFAULT 1 CHECK (capacity > 0),
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_interview_slots_drive ON interview_slots(drive_id, round_index, start_time);
+
+-- 6.2 Interview Bookings (one slot per student per round)
+CREATE TABLE interview_bookings (
+    slot_id BIGINT REFERENCES interview_slots(id) ON DELETE CASCADE,
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE CASCADE,
+    round_index INT NOT NULL,
+    student_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+
+    source VARCHAR(20) DEFAULT 'student' CHECK (source IN ('auto', 'student')),
+    booked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (slot_id, student_id),
+    UNIQUE (drive_id, round_index, student_id)
+);
+
+CREATE INDEX idx_interview_bookings_student ON interview_bookings(student_id);
+
+-- 6.3 Drive Day Attendance (QR check-in per round)
+CREATE TABLE drive_attendance (
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE CASCADE,
+    student_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    round_index INT NOT NULL DEFAULT 0, -- Position in placement_drives.rounds
+
+    checked_in_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    checked_in_by BIGINT REFERENCES users(id) ON DELETE SET NULL, -- Coordinator who scanned the QR
+
+    PRIMARY KEY (drive_id, student_id, round_index)
+);
+
+-- 6.4 No-Shows (expected but never checked in)
+-- penalty_drives is captured when recorded (NO_SHOW_PENALTY_DRIVES): the student can't apply to that many drives posted afterwards.
+CREATE TABLE drive_no_shows (
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE CASCADE,
+    student_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    round_index INT NOT NULL DEFAULT 0,
+
+    penalty_drives INT NOT NULL DEFAULT 0,
+    waived BOOLEAN DEFAULT FALSE,
+    recorded_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (drive_id, student_id, round_index)
+);
+
+CREATE INDEX idx_no_shows_student ON drive_no_shows(student_id) WHERE waived = false;
+
+-- 6.5 Application Status History (append-only)
+-- One row per status change of a drive_applications row; rows are never updated.
+-- from_status is NULL for the first application. changed_by is NULL for system changes.
+-- No foreign key to drive_applications: the history outlives a deleted application, drive or student.
+CREATE TABLE application_status_history (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    drive_id BIGINT NOT NULL,
+    student_id BIGINT NOT NULL,
+
+    from_status VARCHAR(30),
+    to_status VARCHAR(30) NOT NULL,
+    changed_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    actor_role VARCHAR(20) NOT NULL CHECK (actor_role IN ('student', 'admin', 'coordinator', 'recruiter', 'system')),
+    remark TEXT,
+    is_override BOOLEAN NOT NULL DEFAULT FALSE, -- Admin forced a move the transition graph (models.ApplicationStatus) doesn't allow
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_app_status_history_app ON application_status_history(drive_id, student_id, created_at);
+CREATE INDEX idx_app_status_history_student ON application_status_history(student_id);
+
+-- Append-only: history rows can't be edited or deleted. The one UPDATE let through is the
+-- changed_by foreign key clearing the actor when their account is deleted.
+CREATE OR REPLACE FUNCTION application_status_history_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.changed_by IS NULL
+       AND to_jsonb(NEW) - 'changed_by' = to_jsonb(OLD) - 'changed_by' THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'application_status_history is append-only: % is not allowed', TG_OP;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_application_status_history_append_only
+BEFORE UPDATE OR DELETE ON application_status_history
+FOR EACH ROW EXECUTE FUNCTION application_status_history_append_only();
+
 -- ==========================================
 -- 7. UTILITIES
 -- ==========================================
@@ -243,6 +536,196 @@ CREATE TABLE password_resets (
     expires_at TIMESTAMP WITH TIME ZONE NOT NULL
 );
 
+-- ==========================================
+-- 7.1 NOTIFICATIONS (WhatsApp Template Registry)
+-- ==========================================
+-- Maps portal events to templates approved in Meta Business Manager.
+-- parameters is the ordered list of drive fields rendered into {{1}}, {{2}}, ...
+CREATE TABLE whatsapp_templates (
+    event VARCHAR(50) PRIMARY KEY CHECK (event IN ('new_drive', 'deadline_reminder', 'shortlisted', 'offer')),
+    template_name VARCHAR(100) NOT NULL,
+    language_code VARCHAR(10) NOT NULL DEFAULT 'en_US',
+    parameters JSONB DEFAULT '[]', -- e.g. ["company_name", "job_role", "deadline_date"]
+    is_active BOOLEAN DEFAULT TRUE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Channels each student wants deadline reminders on (no row = everything enabled)
+CREATE TABLE notification_preferences (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    push_enabled BOOLEAN DEFAULT TRUE,
+    email_enabled BOOLEAN DEFAULT TRUE,
+    whatsapp_enabled BOOLEAN DEFAULT TRUE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Secret token in each student's calendar feed URL (/api/v1/calendar/<token>.ics); rotating it revokes old subscriptions
+CREATE TABLE calendar_tokens (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token VARCHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per reminder sent, so each drive/student/type is reminded only once
+CREATE TABLE drive_reminders (
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE CASCADE,
+    student_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    reminder_type VARCHAR(10) NOT NULL CHECK (reminder_type IN ('24h', '2h')),
+    channels JSONB DEFAULT '[]', -- e.g. ["push", "email", "whatsapp"]
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (drive_id, student_id, reminder_type)
+);
+
+INSERT INTO whatsapp_templates (event, template_name, language_code, parameters) VALUES
+    ('new_drive', 'new_drive_alert', 'en_US', '["company_name", "job_role", "deadline_date"]'),
+    ('deadline_reminder', 'drive_deadline_reminder', 'en_US', '["company_name", "job_role", "deadline_date"]'),
+    ('shortlisted', 'drive_shortlisted', 'en_US', '["company_name", "job_role", "drive_date"]'),
+    ('offer', 'drive_offer', 'en_US', '["company_name", "job_role", "ctc_display"]');
+
+-- Per-recipient delivery records for business-initiated messages.
+-- message_id is the "wamid" returned by the Cloud API; status webhooks update the row.
+CREATE TABLE whatsapp_messages (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    message_id VARCHAR(255) UNIQUE, -- NULL when the API rejected the send
+    recipient VARCHAR(20) NOT NULL,
+    student_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE CASCADE,
+    event VARCHAR(50) NOT NULL,
+    template_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'accepted'
+    CHECK (status IN ('accepted', 'sent', 'delivered', 'read', 'failed')),
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_whatsapp_messages_drive ON whatsapp_messages(drive_id, status);
+
+-- ==========================================
+-- 7.2 INCOMING WEBHOOKS
+-- ==========================================
+-- Keys of verified incoming webhooks (SHA-256 of the signed "<timestamp>.<body>", or of the body).
+-- Keys of sources that sign a timestamp ('generic') are purged by the scheduler after twice the
+-- timestamp tolerance; 'whatsapp' and 'railway' send none, so their keys are kept to block replays.
+CREATE TABLE webhook_deliveries (
+    source VARCHAR(50) NOT NULL, -- 'whatsapp', 'railway', 'generic'
+    delivery_id VARCHAR(255) NOT NULL,
+    received_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (source, delivery_id)
+);
+
+CREATE INDEX idx_webhook_deliveries_received ON webhook_deliveries(received_at);
+
+-- Incoming webhook events as received, with the outcome of their handlers (replayable by admins)
+CREATE TABLE webhook_events (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    source VARCHAR(50) NOT NULL, -- 'railway', 'generic'
+    event_type VARCHAR(100) NOT NULL,
+    headers JSONB DEFAULT '{}',
+    body JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'received'
+    CHECK (status IN ('received', 'processed', 'failed', 'ignored')),
+    error TEXT,
+    attempts INT DEFAULT 0,
+    received_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_webhook_events_lookup ON webhook_events(source, event_type, received_at DESC);
+
+-- ==========================================
+-- 7.3 OUTGOING WEBHOOKS
+-- ==========================================
+-- Subscriptions of other internal tools (alumni tracker, department dashboard) to portal events
+CREATE TABLE outgoing_webhooks (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL, -- HMAC-SHA256 key for X-Webhook-Signature
+    event_types JSONB NOT NULL DEFAULT '[]', -- e.g. ["drive.published", "student.placed"]
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One row per event per subscription; retried with backoff, 'dead' after the last attempt
+CREATE TABLE outgoing_webhook_deliveries (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    webhook_id BIGINT NOT NULL REFERENCES outgoing_webhooks(id) ON DELETE CASCADE,
+    event_id VARCHAR(50) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts INT DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    last_status_code INT,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_outgoing_deliveries_due ON outgoing_webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_outgoing_deliveries_webhook ON outgoing_webhook_deliveries(webhook_id, created_at DESC);
+
+-- ==========================================
+-- 7.4 BACKGROUND JOBS
+-- ==========================================
+-- One row per scheduled or manual run of a background job (see worker.StartScheduler)
+CREATE TABLE job_runs (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    job_name VARCHAR(100) NOT NULL,
+    trigger VARCHAR(20) NOT NULL CHECK (trigger IN ('schedule', 'manual')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('running', 'succeeded', 'failed')),
+    result TEXT,
+    error TEXT,
+    instance VARCHAR(255), -- host:pid that ran the job
+    scheduled_for TIMESTAMP WITH TIME ZONE, -- Cron slot of a scheduled run, NULL for manual runs
+    started_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    duration_ms BIGINT
+);
+
+CREATE INDEX idx_job_runs_job ON job_runs(job_name, started_at DESC);
+-- Each cron slot runs once across replicas (manual runs have no slot, and NULLs never conflict)
+CREATE UNIQUE INDEX idx_job_runs_slot ON job_runs(job_name, scheduled_for);
+
+-- ==========================================
+-- 7.5 AUDIT LOG
+-- ==========================================
+-- Who did what to which drive/student. Every recruiter action is recorded.
+CREATE TABLE audit_logs (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    actor_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    actor_role VARCHAR(20) NOT NULL,
+    company_id BIGINT REFERENCES companies(id) ON DELETE SET NULL, -- Recruiter's company scope
+    action VARCHAR(100) NOT NULL,  -- e.g. 'recruiter.results_upload'
+    drive_id BIGINT REFERENCES placement_drives(id) ON DELETE SET NULL,
+    student_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    details JSONB DEFAULT '{}',
+    ip_address VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_logs_company ON audit_logs(company_id, created_at DESC);
+CREATE INDEX idx_audit_logs_actor ON audit_logs(actor_id, created_at DESC);
+
+-- ==========================================
+-- 7.6 SAVED STUDENT SEARCHES
+-- ==========================================
+-- Named admin searches (filters + sort, stored as the search request JSON)
+CREATE TABLE saved_student_searches (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    query JSONB NOT NULL DEFAULT '{}',
+    is_shared BOOLEAN NOT NULL DEFAULT FALSE, -- Visible to every admin
+    created_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_saved_student_searches_name ON saved_student_searches(created_by, lower(name));
+
 -- ==========================================
 -- 8. ANALYTICS & VIEWS
 -- ==========================================
@@ -10,7 +10,7 @@ Write-Host "======================================" -ForegroundColor Cyan
 Write-Host ""
 
 # Configuration
-$RailwayWebhookUrl = "$BaseUrl/api/webhooks/railway"
+$RailwayWebhookUrl = "$BaseUrl/api/webhooks/railway?token=$env:RAILWAY_WEBHOOK_SECRET"
 $GenericWebhookUrl = "$BaseUrl/api/webhooks/generic"
 $HealthCheckUrl = "$BaseUrl/api/health"
 
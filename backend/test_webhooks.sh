@@ -9,7 +9,7 @@ echo ""
 
 # Configuration
 BASE_URL="${1:-http://localhost:8080}"
-RAILWAY_WEBHOOK_URL="$BASE_URL/api/webhooks/railway"
+RAILWAY_WEBHOOK_URL="$BASE_URL/api/webhooks/railway?token=$RAILWAY_WEBHOOK_SECRET"
 GENERIC_WEBHOOK_URL="$BASE_URL/api/webhooks/generic"
 
 echo "📍 Base URL: $BASE_URL"